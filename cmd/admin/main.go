@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/config"
+	"daya-listrik-api/internal/db"
+	"daya-listrik-api/internal/repository"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+)
+
+const usage = `Usage: admin [flags] <command>
+
+Commands:
+  grant-admin EMAIL
+                  let the registered user EMAIL manage tariffs (tariffs:admin)
+  revoke-admin EMAIL
+                  take tariffs:admin away from the user EMAIL
+  adopt-orphans HOUSEHOLD_ID
+                  move records, devices, token purchases and meter readings
+                  created before households existed into HOUSEHOLD_ID
+                  (PostgreSQL only; SQLite data always has a household)
+
+Database settings are read like the server's (YAML, .env, environment, flags);
+run "admin -h" for the flags. PostgreSQL must already be migrated with
+"migrate up"; with DB_DRIVER=sqlite the file at DB_PATH is migrated first, as
+the server does on startup. DB_DRIVER=memory keeps no data to manage.`
+
+func main() {
+	cfg, args, err := config.Parse("admin", os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, usage)
+		return
+	}
+	if err != nil {
+		log.Fatal("Configuration error: ", err)
+	}
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg.Database, args[0], args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, cfg config.Database, command string, args []string) error {
+	switch command {
+	case "grant-admin", "revoke-admin":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: admin %s EMAIL", command)
+		}
+	case "adopt-orphans":
+		if len(args) != 1 {
+			return errors.New("usage: admin adopt-orphans HOUSEHOLD_ID")
+		}
+		if cfg.Driver != config.DriverPostgres {
+			return fmt.Errorf("adopt-orphans only applies to DB_DRIVER=%s", config.DriverPostgres)
+		}
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+
+	conn, err := open(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if command == "adopt-orphans" {
+		householdID, err := strconv.Atoi(args[0])
+		if err != nil || householdID < 1 {
+			return fmt.Errorf("invalid household id %q", args[0])
+		}
+		// Data tanpa pemilik hanya berpindah atas perintah operator, tidak
+		// pernah ke user yang kebetulan mendaftar lebih dulu.
+		households := &repository.HouseholdRepository{DB: conn, Timeout: cfg.QueryTimeout}
+		adopted, err := households.AdoptOrphans(ctx, householdID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Moved %d rows into household %d\n", adopted, householdID)
+		return nil
+	}
+
+	// Admin hanya ditetapkan di sini, bukan dari urutan pendaftaran.
+	users := &repository.UserRepository{DB: conn, Timeout: cfg.QueryTimeout}
+	if err := users.SetAdmin(ctx, args[0], command == "grant-admin"); err != nil {
+		return err
+	}
+	fmt.Printf("Updated admin status of %s\n", args[0])
+	return nil
+}
+
+// open membuka database sesuai DB_DRIVER. Driver memory ditolak sebelum
+// menyentuh apa pun karena datanya hanya ada di proses server.
+func open(cfg config.Database) (*sql.DB, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("configuration error: %w", err)
+	}
+	switch cfg.Driver {
+	case config.DriverPostgres:
+		return db.Open(cfg)
+	case config.DriverSQLite:
+		return db.ConnectSQLite(cfg)
+	}
+	return nil, fmt.Errorf("DB_DRIVER=%s keeps no database to manage", cfg.Driver)
+}
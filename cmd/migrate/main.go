@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"daya-listrik-api/internal/config"
+	"daya-listrik-api/internal/db"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `Usage: migrate [flags] <command>
+
+Commands:
+  up              apply all pending migrations
+  down [N]        revert the last N applied migrations (default 1)
+  status          list migrations and whether they are applied; like verify,
+                  it only reads and never waits for a running migration
+  goto VERSION    migrate up or down to VERSION (0 reverts everything)
+  create NAME     create empty NNN_name.up.sql and .down.sql files in
+                  MIGRATIONS_DIR (default ./migrations); rebuild to embed them
+  verify          fail when applied migrations were modified or removed
+
+Migrations are embedded in the binary unless MIGRATIONS_DIR is set. Database
+settings are read like the server's (YAML, .env, environment, flags); run
+"migrate -h" for the flags. With DB_DRIVER=sqlite the commands manage the file
+at DB_PATH with the migrations in MIGRATIONS_DIR/sqlite; the server also
+applies those on startup.`
+
+func main() {
+	cfg, args, err := config.Parse("migrate", os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, usage)
+		return
+	}
+	if err != nil {
+		log.Fatal("Configuration error: ", err)
+	}
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Ctrl+C membatalkan migrasi yang sedang berjalan; transaksinya di-rollback.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg.Database, args[0], args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, cfg config.Database, command string, args []string) error {
+	switch command {
+	case "up", "down", "goto", "status", "verify":
+	case "create":
+		if len(args) != 1 {
+			return errors.New("usage: migrate create NAME")
+		}
+		dir := cfg.MigrationsDir
+		if dir == "" {
+			dir = "./migrations"
+		}
+		if cfg.Driver == config.DriverSQLite {
+			dir = filepath.Join(dir, "sqlite")
+		}
+		paths, err := db.CreateMigration(dir, args[0])
+		for _, path := range paths {
+			fmt.Println("Created", path)
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	if cfg.Driver == config.DriverMemory {
+		return fmt.Errorf("DB_DRIVER=%s keeps no database to migrate", config.DriverMemory)
+	}
+	fsys, open := db.MigrationsFS(cfg.MigrationsDir), db.Open
+	if cfg.Driver == config.DriverSQLite {
+		fsys, open = db.SQLiteMigrationsFS(cfg.MigrationsDir), db.OpenSQLite
+	}
+	// File migrasi dicek dulu agar nama file yang salah ketahuan tanpa
+	// menyentuh database.
+	migrations, err := db.LoadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+	conn, err := open(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	migrator := &db.Migrator{DB: conn, Migrations: migrations}
+	if cfg.Driver == config.DriverSQLite {
+		migrator.Driver = config.DriverSQLite
+	}
+
+	switch command {
+	case "up":
+		if len(args) != 0 {
+			return errors.New("usage: migrate up")
+		}
+		return migrator.Up(ctx)
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			return errors.New("usage: migrate down [N]")
+		}
+		if len(args) == 1 {
+			if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations %q", args[0])
+			}
+		}
+		return migrator.Down(ctx, n)
+	case "goto":
+		if len(args) != 1 {
+			return errors.New("usage: migrate goto VERSION")
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+		return migrator.Goto(ctx, version)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatus(statuses)
+		return nil
+	case "verify":
+		if err := migrator.Verify(ctx); err != nil {
+			return err
+		}
+		fmt.Println("All applied migrations match their files")
+	}
+	return nil
+}
+
+func printStatus(statuses []db.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range statuses {
+		state, appliedAt := "pending", ""
+		switch {
+		case s.Missing:
+			state = "missing"
+		case s.Modified:
+			state = "modified"
+		case s.Applied:
+			state = "applied"
+		}
+		if s.Applied {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	w.Flush()
+}
@@ -1,48 +1,135 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"daya-listrik-api/internal/auth"
+	"daya-listrik-api/internal/billing"
+	"daya-listrik-api/internal/capacity"
+	"daya-listrik-api/internal/config"
 	"daya-listrik-api/internal/db"
 	"daya-listrik-api/internal/handlers"
+	"daya-listrik-api/internal/models"
 	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/server"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 )
 
 func main() {
-	dbConn, err := db.Connect()
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal("Configuration error: ", err)
+	}
+
+	// ctx selesai saat SIGINT/SIGTERM diterima, mis. dari docker compose down.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	repos, closeDB, err := openRepositories(cfg)
 	if err != nil {
 		log.Fatal("Database connection error: ", err)
 	}
-	defer dbConn.Close()
 
-	r := initializeRouter(dbConn)
+	r := initializeRouter(repos, cfg)
 
-	startServer(r)
+	// Koneksi database ditutup setelah semua request selesai.
+	if err := startServer(ctx, r, cfg.Server, closeDB); err != nil {
+		log.Fatal("Server error: ", err)
+	}
+	log.Println("Server stopped")
 }
 
-func initializeRouter(dbConn *sql.DB) *mux.Router {
+// openRepositories membuka database sesuai DB_DRIVER. StopFunc yang
+// dikembalikan menutup koneksinya.
+func openRepositories(cfg *config.Config) (repository.Repositories, server.StopFunc, error) {
+	// Batas waktu setiap pemanggilan repository; 0 mematikan batas ini.
+	queryTimeout := cfg.Database.QueryTimeout
+	switch cfg.Database.Driver {
+	case config.DriverSQLite:
+		sqliteConn, err := db.ConnectSQLite(cfg.Database)
+		if err != nil {
+			return repository.Repositories{}, nil, err
+		}
+		log.Printf("All data is stored in SQLite at %s", cfg.Database.Path)
+		return repository.NewSQLiteRepositories(sqliteConn, queryTimeout), func(context.Context) error { return sqliteConn.Close() }, nil
+	case config.DriverMemory:
+		log.Println("All data is kept in memory and lost when the server stops")
+		return repository.NewMemoryRepositories(repository.NewMemoryStore()), func(context.Context) error { return nil }, nil
+	}
+
+	dbConn, err := db.Connect(cfg.Database)
+	if err != nil {
+		return repository.Repositories{}, nil, err
+	}
+	return repository.NewPostgresRepositories(dbConn, queryTimeout), func(context.Context) error { return dbConn.Close() }, nil
+}
+
+func initializeRouter(repos repository.Repositories, cfg *config.Config) *mux.Router {
+	recordRules := handlers.DefaultRecordRules()
+	recordRules.FutureTolerance = cfg.Records.FutureTolerance
+	recordRules.MaxDuration = cfg.Records.MaxDurationHours
+
 	r := mux.NewRouter()
-	repo := &repository.EnergyRecordRepository{DB: dbConn}
-	handlers.InitializeRoutes(r, repo)
+	handlers.InitializeRoutes(r, handlers.Dependencies{
+		Records:     repos.Records,
+		Devices:     repos.Devices,
+		Tariffs:     repos.Tariffs,
+		Tokens:      repos.Tokens,
+		Meters:      repos.Meters,
+		Billing:     billing.Config{PPJRate: cfg.Billing.PPJRate},
+		Capacity:    capacity.Config{PowerFactor: cfg.Billing.PowerFactor},
+		RecordRules: recordRules,
+		Users:       repos.Users,
+		Auth: auth.Config{
+			Secret:     []byte(cfg.Auth.JWTSecret),
+			AccessTTL:  cfg.Auth.AccessTTL,
+			RefreshTTL: cfg.Auth.RefreshTTL,
+		},
+		Households: repos.Households,
+		APIKeys:    repos.APIKeys,
+		// Pengaturan awal untuk rumah tangga baru; setiap rumah tangga bisa
+		// mengubahnya lewat PUT /api/households/{id}.
+		HouseholdDefaults: models.Household{
+			Name:         models.DefaultHouseholdName,
+			TariffClass:  cfg.Household.TariffClass,
+			ContractedVA: cfg.Household.ContractedVA,
+			Timezone:     cfg.Household.Timezone,
+			BillingDay:   1,
+		},
+	})
 	return r
 }
 
-func startServer(router http.Handler) {
-	const addr = ":8080"
-
+func startServer(ctx context.Context, router http.Handler, cfg config.Server, stops ...server.StopFunc) error {
 	// Bungkus router dengan middleware CORS
 	handler := cors.New(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:5173"},
+		AllowedOrigins:   cfg.CORSOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Household-ID"},
 		AllowCredentials: true,
 	}).Handler(router)
 
-	fmt.Printf("Server is running on http://localhost%s\n", addr)
-	log.Fatal(http.ListenAndServe(addr, handler))
-}
\ No newline at end of file
+	srv := &http.Server{
+		Addr:         cfg.Addr(),
+		Handler:      handler,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+
+	fmt.Printf("Server is running on http://localhost%s\n", srv.Addr)
+	return server.Run(ctx, srv, cfg.ShutdownTimeout, stops...)
+}
@@ -0,0 +1,69 @@
+package apikey
+
+import (
+	"context"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/tenant"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Scheme adalah skema header Authorization untuk API key.
+const Scheme = "ApiKey"
+
+type contextKey struct{}
+
+// WithKey menyimpan API key yang terautentikasi di context.
+func WithKey(ctx context.Context, key models.APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext mengembalikan API key yang terautentikasi dari context.
+func FromContext(ctx context.Context) (models.APIKey, bool) {
+	key, ok := ctx.Value(contextKey{}).(models.APIKey)
+	return key, ok
+}
+
+// Middleware menerima header "Authorization: ApiKey <key>" yang memiliki
+// scope. Key dicek ke database pada setiap request sehingga pencabutan
+// langsung berlaku. Request tanpa API key diteruskan ke fallback, biasanya
+// rangkaian auth.Middleware dan tenant.Middleware.
+func Middleware(repo repository.APIKeyRepositoryInterface, scope string, fallback func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withUser := fallback(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), Scheme+" ")
+			if !ok {
+				withUser.ServeHTTP(w, r)
+				return
+			}
+
+			key, household, err := repo.AuthenticateAPIKey(r.Context(), strings.TrimSpace(raw))
+			if err != nil {
+				if errors.Is(err, repository.ErrInvalidAPIKey) {
+					w.Header().Set("WWW-Authenticate", Scheme)
+					problem.Write(w, r, problem.New(http.StatusUnauthorized, "invalid_api_key", nil))
+					return
+				}
+				problem.Error(w, r, err)
+				return
+			}
+
+			if !key.HasScope(scope) {
+				p := problem.New(http.StatusForbidden, "forbidden", i18n.Params{"permission": scope})
+				p.Permission = scope
+				problem.Write(w, r, p)
+				return
+			}
+
+			// Membership tanpa role: hak akses key hanya sebatas scope-nya.
+			ctx := WithKey(r.Context(), *key)
+			ctx = tenant.WithMembership(ctx, models.Membership{Household: *household})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
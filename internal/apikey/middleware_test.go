@@ -0,0 +1,62 @@
+package apikey
+
+import (
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/repository/mocks"
+	"daya-listrik-api/internal/tenant"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMiddleware(t *testing.T) {
+	household := &models.Household{ID: 10, Name: "Rumah"}
+	repo := new(mocks.MockAPIKeyRepository)
+	repo.On("AuthenticateAPIKey", mock.Anything, "dlk_valid").Return(&models.APIKey{ID: 1, HouseholdID: 10, Scopes: []string{models.ScopeRecordsWrite}}, household, nil)
+	repo.On("AuthenticateAPIKey", mock.Anything, "dlk_noscope").Return(&models.APIKey{ID: 2, HouseholdID: 10}, household, nil)
+	repo.On("AuthenticateAPIKey", mock.Anything, "dlk_revoked").Return(nil, nil, repository.ErrInvalidAPIKey)
+
+	fallback := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "fallback", http.StatusTeapot)
+		})
+	}
+	handler := Middleware(repo, models.ScopeRecordsWrite, fallback)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m, _ := tenant.FromContext(r.Context())
+		key, _ := FromContext(r.Context())
+		w.Write([]byte(strconv.Itoa(m.Household.ID) + "/" + strconv.Itoa(key.ID)))
+	}))
+
+	cases := []struct {
+		name          string
+		authorization string
+		status        int
+		body          string
+	}{
+		{"valid key", "ApiKey dlk_valid", http.StatusOK, "10/1"},
+		{"missing scope", "ApiKey dlk_noscope", http.StatusForbidden, ""},
+		{"revoked key", "ApiKey dlk_revoked", http.StatusUnauthorized, ""},
+		{"bearer token", "Bearer abc", http.StatusTeapot, ""},
+		{"no header", "", http.StatusTeapot, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/records/add", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.body != "" {
+				assert.Equal(t, tc.body, w.Body.String())
+			}
+		})
+	}
+}
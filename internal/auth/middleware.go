@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"daya-listrik-api/internal/problem"
+	"net/http"
+	"strings"
+)
+
+type contextKey struct{}
+
+// WithUserID menyimpan id user yang terautentikasi di context.
+func WithUserID(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, contextKey{}, userID)
+}
+
+// UserID mengembalikan id user yang terautentikasi dari context.
+func UserID(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(contextKey{}).(int)
+	return userID, ok
+}
+
+// Middleware menolak request tanpa access token Bearer yang valid dan
+// menyimpan id user-nya di context request.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || strings.TrimSpace(raw) == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				problem.Write(w, r, problem.New(http.StatusUnauthorized, "unauthorized", nil))
+				return
+			}
+
+			userID, err := cfg.ParseToken(strings.TrimSpace(raw), TokenAccess)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				problem.Write(w, r, problem.New(http.StatusUnauthorized, "invalid_token", nil))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithUserID(r.Context(), userID)))
+		})
+	}
+}
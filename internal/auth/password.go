@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const MinPasswordLength = 8
+
+// HashPassword meng-hash password dengan bcrypt.
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %v", err)
+	}
+	return string(hash), nil
+}
+
+// dummyHash dipakai saat email tidak ditemukan agar waktu respons login
+// tidak membocorkan email mana yang terdaftar.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("daya-listrik-dummy"), bcrypt.DefaultCost)
+
+// CheckPassword mengecek password terhadap hash bcrypt. hash kosong tetap
+// menjalankan perbandingan dengan hash dummy.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Jenis token yang diterbitkan.
+const (
+	TokenAccess  = "access"
+	TokenRefresh = "refresh"
+)
+
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 7 * 24 * time.Hour
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Config berisi kunci penandatangan dan masa berlaku token.
+type Config struct {
+	Secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// TokenPair adalah pasangan token yang dikembalikan saat register, login
+// dan refresh.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type claims struct {
+	Type string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// IssueTokens menerbitkan access token dan refresh token HS256 untuk user.
+func (c Config) IssueTokens(userID int) (*TokenPair, error) {
+	access, err := c.sign(userID, TokenAccess, c.AccessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := c.sign(userID, TokenRefresh, c.RefreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(c.AccessTTL.Seconds()),
+	}, nil
+}
+
+func (c Config) sign(userID int, kind string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Type: kind,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	})
+	signed, err := token.SignedString(c.Secret)
+	if err != nil {
+		return "", fmt.Errorf("error signing token: %v", err)
+	}
+	return signed, nil
+}
+
+// ParseToken memverifikasi token dengan jenis kind dan mengembalikan id user.
+func (c Config) ParseToken(raw, kind string) (int, error) {
+	var parsed claims
+	_, err := jwt.ParseWithClaims(raw, &parsed, func(*jwt.Token) (any, error) {
+		return c.Secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil || parsed.Type != kind {
+		return 0, ErrInvalidToken
+	}
+	userID, err := strconv.Atoi(parsed.Subject)
+	if err != nil || userID <= 0 {
+		return 0, ErrInvalidToken
+	}
+	return userID, nil
+}
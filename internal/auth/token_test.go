@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfig = Config{Secret: []byte("test-secret"), AccessTTL: time.Minute, RefreshTTL: time.Hour}
+
+func TestIssueAndParseTokens(t *testing.T) {
+	tokens, err := testConfig.IssueTokens(42)
+	assert.NoError(t, err)
+	assert.Equal(t, 60, tokens.ExpiresIn)
+
+	userID, err := testConfig.ParseToken(tokens.AccessToken, TokenAccess)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, userID)
+
+	userID, err = testConfig.ParseToken(tokens.RefreshToken, TokenRefresh)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, userID)
+
+	_, err = testConfig.ParseToken(tokens.RefreshToken, TokenAccess)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	other := Config{Secret: []byte("other-secret"), AccessTTL: time.Minute}
+	_, err = other.ParseToken(tokens.AccessToken, TokenAccess)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	expired := Config{Secret: testConfig.Secret, AccessTTL: -time.Minute, RefreshTTL: time.Hour}
+	stale, _ := expired.IssueTokens(42)
+	_, err = testConfig.ParseToken(stale.AccessToken, TokenAccess)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestMiddleware(t *testing.T) {
+	var seen int
+	handler := Middleware(testConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = UserID(r.Context())
+	}))
+	tokens, _ := testConfig.IssueTokens(42)
+
+	for header, want := range map[string]int{
+		"":                              http.StatusUnauthorized,
+		"Basic abc":                     http.StatusUnauthorized,
+		"Bearer rusak":                  http.StatusUnauthorized,
+		"Bearer " + tokens.RefreshToken: http.StatusUnauthorized,
+		"Bearer " + tokens.AccessToken:  http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/records", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code, header)
+	}
+	assert.Equal(t, 42, seen)
+}
+
+func TestPassword(t *testing.T) {
+	_, err := HashPassword("pendek")
+	assert.Error(t, err)
+
+	hash, err := HashPassword("rahasia123")
+	assert.NoError(t, err)
+	assert.True(t, CheckPassword(hash, "rahasia123"))
+	assert.False(t, CheckPassword(hash, "salah12345"))
+	assert.False(t, CheckPassword("", "rahasia123"))
+}
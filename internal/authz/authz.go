@@ -0,0 +1,126 @@
+package authz
+
+import (
+	"daya-listrik-api/internal/apikey"
+	"daya-listrik-api/internal/auth"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/tenant"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Permission adalah izin yang dibutuhkan sebuah route.
+type Permission string
+
+const (
+	// RecordsRead dan RecordsWrite berlaku untuk semua data pemakaian rumah
+	// tangga aktif: record, perangkat, token dan pembacaan meter.
+	RecordsRead  Permission = "records:read"
+	RecordsWrite Permission = "records:write"
+	// HouseholdRead dimiliki semua anggota untuk melihat rumah tangga dan
+	// daftar anggotanya.
+	HouseholdRead Permission = "household:read"
+	// HouseholdAdmin dibutuhkan untuk mengubah atau menghapus rumah tangga,
+	// mengelola anggota dan undangan, serta API key rumah tangga.
+	HouseholdAdmin Permission = "household:admin"
+	// TariffsAdmin dibutuhkan untuk mengubah tabel tarif yang dipakai semua
+	// rumah tangga; hanya dimiliki user admin.
+	TariffsAdmin Permission = "tariffs:admin"
+)
+
+var rolePermissions = map[string][]Permission{
+	models.RoleOwner:  {RecordsRead, RecordsWrite, HouseholdRead, HouseholdAdmin},
+	models.RoleEditor: {RecordsRead, RecordsWrite, HouseholdRead},
+	models.RoleViewer: {RecordsRead, HouseholdRead},
+}
+
+// RolePermissions mengembalikan izin yang dimiliki peran anggota rumah tangga.
+func RolePermissions(role string) []Permission {
+	return rolePermissions[role]
+}
+
+// Authorizer memeriksa izin request berdasarkan API key, peran di rumah
+// tangga aktif, atau status admin user.
+type Authorizer struct {
+	Users repository.UserRepositoryInterface
+}
+
+// Guard adalah handler yang hanya diteruskan bila request memiliki Permission.
+type Guard struct {
+	Permission Permission
+	authorizer *Authorizer
+	next       http.Handler
+	// selfParam adalah path parameter berisi id user yang boleh mengakses
+	// route tanpa Permission, lihat RequireOrSelf.
+	selfParam string
+}
+
+// Require membungkus handler dengan pemeriksaan izin. Harus dipasang setelah
+// middleware autentikasi (dan tenant.Middleware untuk data rumah tangga).
+func (a *Authorizer) Require(permission Permission, next http.Handler) *Guard {
+	return &Guard{Permission: permission, authorizer: a, next: next}
+}
+
+// RequireOrSelf sama dengan Require, tetapi request juga diteruskan bila path
+// parameter userParam berisi id user itu sendiri, mis. anggota yang keluar
+// dari rumah tangga.
+func (a *Authorizer) RequireOrSelf(permission Permission, userParam string, next http.Handler) *Guard {
+	return &Guard{Permission: permission, authorizer: a, next: next, selfParam: userParam}
+}
+
+func (g *Guard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	allowed, err := g.authorizer.allowed(r, g.Permission)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+	if !allowed && g.selfParam != "" {
+		userID, ok := auth.UserID(r.Context())
+		allowed = ok && strings.TrimSpace(mux.Vars(r)[g.selfParam]) == strconv.Itoa(userID)
+	}
+	if !allowed {
+		writeForbidden(w, r, g.Permission)
+		return
+	}
+	g.next.ServeHTTP(w, r)
+}
+
+func (a *Authorizer) allowed(r *http.Request, permission Permission) (bool, error) {
+	ctx := r.Context()
+
+	// API key hanya memiliki izin sebatas scope-nya.
+	if key, ok := apikey.FromContext(ctx); ok {
+		return key.HasScope(string(permission)), nil
+	}
+
+	if permission == TariffsAdmin {
+		userID, ok := auth.UserID(ctx)
+		if !ok {
+			return false, nil
+		}
+		user, err := a.Users.GetByIdUser(r.Context(), userID)
+		if err != nil {
+			return false, err
+		}
+		return user.IsAdmin, nil
+	}
+
+	membership, ok := tenant.FromContext(ctx)
+	if !ok {
+		return false, nil
+	}
+	return slices.Contains(RolePermissions(membership.Role), permission), nil
+}
+
+func writeForbidden(w http.ResponseWriter, r *http.Request, permission Permission) {
+	p := problem.New(http.StatusForbidden, "forbidden", i18n.Params{"permission": permission})
+	p.Permission = string(permission)
+	problem.Write(w, r, p)
+}
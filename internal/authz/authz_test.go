@@ -0,0 +1,107 @@
+package authz
+
+import (
+	"daya-listrik-api/internal/apikey"
+	"daya-listrik-api/internal/auth"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository/mocks"
+	"daya-listrik-api/internal/tenant"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequire(t *testing.T) {
+	users := new(mocks.MockUserRepository)
+	users.On("GetByIdUser", mock.Anything, 1).Return(&models.User{ID: 1, IsAdmin: true}, nil)
+	users.On("GetByIdUser", mock.Anything, 2).Return(&models.User{ID: 2}, nil)
+	authorizer := &Authorizer{Users: users}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	member := func(role string) func(*http.Request) *http.Request {
+		return func(r *http.Request) *http.Request {
+			return r.WithContext(tenant.WithMembership(r.Context(), models.Membership{Role: role}))
+		}
+	}
+	user := func(id int) func(*http.Request) *http.Request {
+		return func(r *http.Request) *http.Request {
+			return r.WithContext(auth.WithUserID(r.Context(), id))
+		}
+	}
+	key := func(scopes ...string) func(*http.Request) *http.Request {
+		return func(r *http.Request) *http.Request {
+			ctx := apikey.WithKey(r.Context(), models.APIKey{Scopes: scopes})
+			return r.WithContext(tenant.WithMembership(ctx, models.Membership{}))
+		}
+	}
+
+	cases := []struct {
+		name       string
+		permission Permission
+		with       func(*http.Request) *http.Request
+		status     int
+	}{
+		{"editor writes records", RecordsWrite, member(models.RoleEditor), http.StatusNoContent},
+		{"viewer reads records", RecordsRead, member(models.RoleViewer), http.StatusNoContent},
+		{"viewer cannot write records", RecordsWrite, member(models.RoleViewer), http.StatusForbidden},
+		{"editor cannot manage keys", HouseholdAdmin, member(models.RoleEditor), http.StatusForbidden},
+		{"owner manages keys", HouseholdAdmin, member(models.RoleOwner), http.StatusNoContent},
+		{"admin changes tariffs", TariffsAdmin, user(1), http.StatusNoContent},
+		{"non-admin cannot change tariffs", TariffsAdmin, user(2), http.StatusForbidden},
+		{"api key within scope", RecordsWrite, key(models.ScopeRecordsWrite), http.StatusNoContent},
+		{"api key outside scope", RecordsRead, key(models.ScopeRecordsWrite), http.StatusForbidden},
+		{"no household", RecordsRead, user(2), http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			authorizer.Require(tc.permission, ok).ServeHTTP(w, tc.with(httptest.NewRequest(http.MethodGet, "/", nil)))
+			assert.Equal(t, tc.status, w.Code)
+
+			if tc.status == http.StatusForbidden {
+				var resp problem.Problem
+				assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, "forbidden", resp.Code)
+				assert.Equal(t, string(tc.permission), resp.Permission)
+			}
+		})
+	}
+}
+
+func TestRequireOrSelf(t *testing.T) {
+	authorizer := &Authorizer{}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cases := []struct {
+		name   string
+		role   string
+		target string
+		status int
+	}{
+		{"owner removes member", models.RoleOwner, "2", http.StatusNoContent},
+		{"viewer leaves", models.RoleViewer, "3", http.StatusNoContent},
+		{"viewer cannot remove member", models.RoleViewer, "2", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			ctx := tenant.WithMembership(auth.WithUserID(req.Context(), 3), models.Membership{Role: tc.role})
+			req = mux.SetURLVars(req.WithContext(ctx), map[string]string{"user_id": tc.target})
+
+			w := httptest.NewRecorder()
+			authorizer.RequireOrSelf(HouseholdAdmin, "user_id", ok).ServeHTTP(w, req)
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package billing
+
+import (
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"time"
+)
+
+const (
+	// Rekening minimum pascabayar: 40 jam nyala x daya tersambung.
+	MinimumChargeHours = 40
+
+	// Bea meterai dikenakan untuk tagihan di atas batas nominal.
+	StampDutyThreshold = 5_000_000
+	StampDuty          = 10_000
+)
+
+// Kode item tagihan yang stabil untuk klien.
+const (
+	ItemEnergyCharge  = "energy_charge"
+	ItemMinimumCharge = "minimum_charge"
+	ItemPPJ           = "ppj"
+	ItemStampDuty     = "stamp_duty"
+)
+
+// Config berisi parameter tagihan rumah tangga.
+type Config struct {
+	TariffClass string
+	// ContractedVA adalah daya tersambung; 0 berarti ikut min_va dari tarif.
+	ContractedVA int
+	// PPJRate adalah tarif Pajak Penerangan Jalan daerah, mis. 0.10 untuk 10%.
+	PPJRate float64
+}
+
+// LineItem adalah satu baris tagihan. Description diisi handler dari katalog
+// i18n ("bill_" + Code) dengan Params, sesuai bahasa request.
+type LineItem struct {
+	Code        string      `json:"code"`
+	Description string      `json:"description"`
+	Params      i18n.Params `json:"-"`
+	Quantity    float64     `json:"quantity,omitempty"`
+	Unit        string      `json:"unit,omitempty"`
+	Rate        float64     `json:"rate,omitempty"`
+	Amount      float64     `json:"amount"`
+}
+
+type Estimate struct {
+	Month          string     `json:"month"`
+	From           time.Time  `json:"from"`
+	To             time.Time  `json:"to"`
+	TariffClass    string     `json:"tariff_class"`
+	ContractedVA   int        `json:"contracted_va"`
+	PricePerKWh    float64    `json:"price_per_kwh"`
+	RecordCount    int        `json:"record_count"`
+	EnergyKWh      float64    `json:"energy_kwh"`
+	MinimumKWh     float64    `json:"minimum_kwh"`
+	MinimumApplied bool       `json:"minimum_applied"`
+	Items          []LineItem `json:"items"`
+	Total          float64    `json:"total"`
+}
+
+// MinimumKWh menghitung kWh rekening minimum untuk daya tersambung va.
+func MinimumKWh(va int) float64 {
+	return models.WhToKWh(models.EnergyWh(float64(va), MinimumChargeHours))
+}
+
+// EstimateBill menyusun perkiraan tagihan dari ringkasan pemakaian sebulan.
+// Biaya energi memakai cost_idr record (tarif yang berlaku per tanggal record);
+// bila pemakaian di bawah rekening minimum, yang ditagih adalah rekening
+// minimum dengan tarif yang berlaku saat ini.
+func EstimateBill(cfg Config, tariff *models.Tariff, summary *models.UsageSummary) *Estimate {
+	va := cfg.ContractedVA
+	if va == 0 {
+		va = tariff.MinVA
+	}
+
+	estimate := &Estimate{
+		Month:        summary.From.Format("2006-01"),
+		From:         summary.From,
+		To:           summary.To,
+		TariffClass:  tariff.Class,
+		ContractedVA: va,
+		PricePerKWh:  tariff.PricePerKWh,
+		RecordCount:  summary.RecordCount,
+		EnergyKWh:    summary.EnergyKWh,
+		MinimumKWh:   MinimumKWh(va),
+	}
+
+	var energyCharge LineItem
+	if summary.EnergyKWh < estimate.MinimumKWh {
+		estimate.MinimumApplied = true
+		energyCharge = LineItem{
+			Code:     ItemMinimumCharge,
+			Params:   i18n.Params{"hours": MinimumChargeHours},
+			Quantity: estimate.MinimumKWh,
+			Unit:     "kWh",
+			Rate:     tariff.PricePerKWh,
+			Amount:   models.Cost(estimate.MinimumKWh, tariff.PricePerKWh),
+		}
+	} else {
+		energyCharge = LineItem{
+			Code:     ItemEnergyCharge,
+			Quantity: summary.EnergyKWh,
+			Unit:     "kWh",
+			Amount:   summary.CostIDR,
+		}
+	}
+	estimate.Items = append(estimate.Items, energyCharge)
+
+	if cfg.PPJRate > 0 {
+		estimate.Items = append(estimate.Items, LineItem{
+			Code:   ItemPPJ,
+			Rate:   cfg.PPJRate,
+			Amount: models.RoundIDR(energyCharge.Amount * cfg.PPJRate),
+		})
+	}
+
+	for _, item := range estimate.Items {
+		estimate.Total += item.Amount
+	}
+
+	if estimate.Total > StampDutyThreshold {
+		estimate.Items = append(estimate.Items, LineItem{
+			Code:   ItemStampDuty,
+			Amount: StampDuty,
+		})
+		estimate.Total += StampDuty
+	}
+
+	estimate.Total = models.RoundIDR(estimate.Total)
+	return estimate
+}
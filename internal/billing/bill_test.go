@@ -0,0 +1,55 @@
+package billing
+
+import (
+	"daya-listrik-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateBill(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	tariff := &models.Tariff{Class: models.TariffR1_1300VA, MinVA: 1300, PricePerKWh: 1444.70}
+	cfg := Config{TariffClass: models.TariffR1_1300VA, PPJRate: 0.10}
+
+	t.Run("usage above minimum", func(t *testing.T) {
+		summary := &models.UsageSummary{From: from, To: to, RecordCount: 30, EnergyKWh: 200, CostIDR: 288940}
+
+		estimate := EstimateBill(cfg, tariff, summary)
+
+		assert.Equal(t, "2026-10", estimate.Month)
+		assert.Equal(t, 1300, estimate.ContractedVA)
+		assert.Equal(t, 52.0, estimate.MinimumKWh)
+		assert.False(t, estimate.MinimumApplied)
+		assert.Len(t, estimate.Items, 2)
+		assert.Equal(t, ItemEnergyCharge, estimate.Items[0].Code)
+		assert.Equal(t, 28894.0, estimate.Items[1].Amount)
+		assert.Equal(t, 317834.0, estimate.Total)
+	})
+
+	t.Run("usage below minimum", func(t *testing.T) {
+		summary := &models.UsageSummary{From: from, To: to, RecordCount: 2, EnergyKWh: 10, CostIDR: 14447}
+
+		estimate := EstimateBill(cfg, tariff, summary)
+
+		assert.True(t, estimate.MinimumApplied)
+		assert.Equal(t, ItemMinimumCharge, estimate.Items[0].Code)
+		assert.Equal(t, 75124.4, estimate.Items[0].Amount)
+		assert.Equal(t, MinimumChargeHours, estimate.Items[0].Params["hours"])
+		assert.Equal(t, 82636.84, estimate.Total)
+	})
+
+	t.Run("stamp duty on large bill", func(t *testing.T) {
+		summary := &models.UsageSummary{From: from, To: to, EnergyKWh: 4000, CostIDR: 6798120}
+		big := Config{TariffClass: models.TariffR3, ContractedVA: 11000}
+
+		estimate := EstimateBill(big, &models.Tariff{Class: models.TariffR3, MinVA: 6600, PricePerKWh: 1699.53}, summary)
+
+		assert.Equal(t, 11000, estimate.ContractedVA)
+		assert.Len(t, estimate.Items, 2)
+		assert.Equal(t, ItemStampDuty, estimate.Items[1].Code)
+		assert.Equal(t, 6808120.0, estimate.Total)
+	})
+}
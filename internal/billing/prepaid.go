@@ -0,0 +1,53 @@
+package billing
+
+import (
+	"daya-listrik-api/internal/models"
+	"time"
+)
+
+// BurnRateWindow adalah rentang pemakaian terakhir yang dipakai untuk
+// menghitung laju pemakaian token.
+const BurnRateWindow = 7 * 24 * time.Hour
+
+// BurnRateStart mengembalikan awal jendela laju pemakaian, tidak lebih awal
+// dari pembelian token pertama.
+func BurnRateStart(firstPurchase, now time.Time) time.Time {
+	start := now.Add(-BurnRateWindow)
+	if firstPurchase.After(start) {
+		return firstPurchase
+	}
+	return start
+}
+
+// ProjectBalance menghitung sisa kWh prabayar (total kWh token dikurangi kWh
+// yang terpakai sejak pembelian pertama) dan memperkirakan kapan saldo habis
+// dengan laju pemakaian recentKWh selama [burnStart, now).
+func ProjectBalance(totals *models.TokenTotals, consumedKWh, recentKWh float64, burnStart, now time.Time) *models.TokenBalance {
+	balance := &models.TokenBalance{
+		KWhCredited:  totals.KWhCredited,
+		KWhConsumed:  consumedKWh,
+		KWhRemaining: totals.KWhCredited - consumedKWh,
+		CalculatedAt: now,
+	}
+	if totals.PurchaseCount == 0 {
+		return balance
+	}
+
+	latest := totals.LatestPurchase
+	balance.LastPurchasedAt = &latest
+
+	days := now.Sub(burnStart).Hours() / 24
+	if days <= 0 || recentKWh <= 0 {
+		return balance
+	}
+	balance.BurnRateKWhDay = recentKWh / days
+
+	remainingDays := 0.0
+	if balance.KWhRemaining > 0 {
+		remainingDays = balance.KWhRemaining / balance.BurnRateKWhDay
+	}
+	empty := now.Add(time.Duration(remainingDays * 24 * float64(time.Hour)))
+	balance.DaysRemaining = &remainingDays
+	balance.PredictedEmpty = &empty
+	return balance
+}
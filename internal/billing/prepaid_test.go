@@ -0,0 +1,47 @@
+package billing
+
+import (
+	"daya-listrik-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectBalance(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	first := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	totals := &models.TokenTotals{PurchaseCount: 2, KWhCredited: 150, FirstPurchase: first, LatestPurchase: now.AddDate(0, 0, -2)}
+
+	burnStart := BurnRateStart(first, now)
+	assert.Equal(t, now.AddDate(0, 0, -7), burnStart)
+
+	balance := ProjectBalance(totals, 80, 35, burnStart, now)
+
+	assert.Equal(t, 70.0, balance.KWhRemaining)
+	assert.Equal(t, 5.0, balance.BurnRateKWhDay)
+	assert.Equal(t, 14.0, *balance.DaysRemaining)
+	assert.Equal(t, now.AddDate(0, 0, 14), *balance.PredictedEmpty)
+}
+
+func TestProjectBalance_NoUsage(t *testing.T) {
+	now := time.Now()
+	totals := &models.TokenTotals{PurchaseCount: 1, KWhCredited: 50, FirstPurchase: now.Add(-time.Hour), LatestPurchase: now.Add(-time.Hour)}
+
+	balance := ProjectBalance(totals, 0, 0, BurnRateStart(totals.FirstPurchase, now), now)
+
+	assert.Equal(t, 50.0, balance.KWhRemaining)
+	assert.Nil(t, balance.PredictedEmpty)
+}
+
+func TestProjectBalance_AlreadyEmpty(t *testing.T) {
+	now := time.Now()
+	first := now.AddDate(0, 0, -20)
+	totals := &models.TokenTotals{PurchaseCount: 1, KWhCredited: 20, FirstPurchase: first, LatestPurchase: first}
+
+	balance := ProjectBalance(totals, 25, 7, BurnRateStart(first, now), now)
+
+	assert.Equal(t, -5.0, balance.KWhRemaining)
+	assert.Equal(t, 0.0, *balance.DaysRemaining)
+	assert.Equal(t, now, *balance.PredictedEmpty)
+}
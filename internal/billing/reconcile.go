@@ -0,0 +1,43 @@
+package billing
+
+import (
+	"daya-listrik-api/internal/models"
+	"fmt"
+	"math"
+)
+
+// NoteOverTracked menandai record perangkat yang melebihi pemakaian menurut
+// meter. Teksnya dirender handler dari katalog i18n.
+const NoteOverTracked = "over_tracked"
+
+// Reconcile menghitung kWh "lain-lain/tidak tercatat": selisih pemakaian
+// menurut meter dengan jumlah kWh record perangkat pada periode yang sama.
+func Reconcile(from, to models.MeterReading, tracked *models.UsageSummary) (*models.Reconciliation, error) {
+	if !to.ReadAt.After(from.ReadAt) {
+		return nil, fmt.Errorf("end reading must be later than start reading")
+	}
+	metered := to.CumulativeKWh - from.CumulativeKWh
+	if metered < 0 {
+		return nil, fmt.Errorf("meter reading decreased from %.2f to %.2f kWh", from.CumulativeKWh, to.CumulativeKWh)
+	}
+
+	result := &models.Reconciliation{
+		From:         from,
+		To:           to,
+		MeteredKWh:   round2(metered),
+		TrackedKWh:   round2(tracked.EnergyKWh),
+		RecordCount:  tracked.RecordCount,
+		UntrackedKWh: round2(metered - tracked.EnergyKWh),
+	}
+	if metered > 0 {
+		result.UntrackedPct = round2(result.UntrackedKWh / metered * 100)
+	}
+	if result.UntrackedKWh < 0 {
+		result.NoteCode = NoteOverTracked
+	}
+	return result, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,34 @@
+package billing
+
+import (
+	"daya-listrik-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile(t *testing.T) {
+	start := time.Date(2026, 10, 1, 7, 0, 0, 0, time.UTC)
+	from := models.MeterReading{ID: 1, ReadAt: start, CumulativeKWh: 12000}
+	to := models.MeterReading{ID: 2, ReadAt: start.AddDate(0, 0, 7), CumulativeKWh: 12050}
+
+	result, err := Reconcile(from, to, &models.UsageSummary{RecordCount: 20, EnergyKWh: 38.5})
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, result.MeteredKWh)
+	assert.Equal(t, 11.5, result.UntrackedKWh)
+	assert.Equal(t, 23.0, result.UntrackedPct)
+	assert.Empty(t, result.NoteCode)
+
+	result, err = Reconcile(from, to, &models.UsageSummary{EnergyKWh: 55})
+	assert.NoError(t, err)
+	assert.Equal(t, -5.0, result.UntrackedKWh)
+	assert.Equal(t, NoteOverTracked, result.NoteCode)
+
+	_, err = Reconcile(to, from, &models.UsageSummary{})
+	assert.Error(t, err)
+
+	to.CumulativeKWh = 11000
+	_, err = Reconcile(from, to, &models.UsageSummary{})
+	assert.Error(t, err)
+}
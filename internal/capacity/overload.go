@@ -0,0 +1,128 @@
+package capacity
+
+import (
+	"daya-listrik-api/internal/models"
+	"sort"
+	"time"
+)
+
+// DefaultPowerFactor dipakai bila faktor daya rumah tangga tidak dikonfigurasi.
+const DefaultPowerFactor = 0.85
+
+// Config berisi daya tersambung rumah tangga. MCB trip bila beban serentak
+// (watt) melebihi VA x faktor daya.
+type Config struct {
+	ContractedVA int
+	PowerFactor  float64
+}
+
+// LimitWatts mengembalikan batas beban dalam watt; 0 berarti tidak dibatasi.
+func (c Config) LimitWatts() float64 {
+	pf := c.PowerFactor
+	if pf <= 0 {
+		pf = DefaultPowerFactor
+	}
+	return float64(c.ContractedVA) * pf
+}
+
+// Window adalah rentang waktu saat beban serentak melebihi batas.
+type Window struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	PeakWatts  float64   `json:"peak_watts"`
+	LimitWatts float64   `json:"limit_watts"`
+	RecordIDs  []int     `json:"record_ids"`
+}
+
+type event struct {
+	at     time.Time
+	watts  float64
+	record int
+	start  bool
+}
+
+// Interval mengembalikan rentang [mulai, selesai) pemakaian record. Record
+// tanpa started_at tidak punya interval.
+func Interval(record models.EnergyRecord) (time.Time, time.Time, bool) {
+	if record.StartedAt == nil || record.Duration <= 0 {
+		return time.Time{}, time.Time{}, false
+	}
+	start := *record.StartedAt
+	return start, start.Add(time.Duration(record.Duration * float64(time.Hour))), true
+}
+
+// Overloads mencari rentang waktu saat jumlah daya record yang tumpang tindih
+// melebihi limitWatts.
+func Overloads(records []models.EnergyRecord, limitWatts float64) []Window {
+	windows := []Window{}
+	if limitWatts <= 0 {
+		return windows
+	}
+
+	var events []event
+	for _, record := range records {
+		start, end, ok := Interval(record)
+		if !ok {
+			continue
+		}
+		events = append(events,
+			event{at: start, watts: record.Usage, record: record.ID, start: true},
+			event{at: end, watts: record.Usage, record: record.ID},
+		)
+	}
+	// Pada waktu yang sama, record yang selesai diproses lebih dulu agar
+	// pemakaian yang bersambung tidak dihitung serentak.
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return !events[i].start && events[j].start
+		}
+		return events[i].at.Before(events[j].at)
+	})
+
+	load := 0.0
+	active := map[int]int{}
+	var current *Window
+	for i, ev := range events {
+		if ev.start {
+			load += ev.watts
+			active[ev.record]++
+		} else {
+			load -= ev.watts
+			if active[ev.record]--; active[ev.record] == 0 {
+				delete(active, ev.record)
+			}
+		}
+
+		// Evaluasi beban setelah semua event pada waktu yang sama diproses.
+		if i+1 < len(events) && events[i+1].at.Equal(ev.at) {
+			continue
+		}
+
+		if load > limitWatts {
+			if current == nil {
+				current = &Window{Start: ev.at, LimitWatts: limitWatts}
+			}
+			if load > current.PeakWatts {
+				current.PeakWatts = load
+			}
+			for id := range active {
+				current.RecordIDs = appendUnique(current.RecordIDs, id)
+			}
+		} else if current != nil {
+			current.End = ev.at
+			sort.Ints(current.RecordIDs)
+			windows = append(windows, *current)
+			current = nil
+		}
+	}
+	return windows
+}
+
+func appendUnique(ids []int, id int) []int {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
@@ -0,0 +1,45 @@
+package capacity
+
+import (
+	"daya-listrik-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func at(hour, minute int) *time.Time {
+	t := time.Date(2026, 10, 17, hour, minute, 0, 0, time.UTC)
+	return &t
+}
+
+func TestLimitWatts(t *testing.T) {
+	assert.Equal(t, 1105.0, Config{ContractedVA: 1300}.LimitWatts())
+	assert.Equal(t, 900.0, Config{ContractedVA: 900, PowerFactor: 1}.LimitWatts())
+	assert.Equal(t, 0.0, Config{}.LimitWatts())
+}
+
+func TestOverloads(t *testing.T) {
+	records := []models.EnergyRecord{
+		{ID: 1, Usage: 600, Duration: 2, StartedAt: at(8, 0)},    // 08:00-10:00
+		{ID: 2, Usage: 400, Duration: 1, StartedAt: at(9, 0)},    // 09:00-10:00
+		{ID: 3, Usage: 350, Duration: 0.5, StartedAt: at(9, 15)}, // 09:15-09:45
+		{ID: 4, Usage: 900, Duration: 1, StartedAt: at(10, 0)},   // 10:00-11:00, bersambung dengan 1
+		{ID: 5, Usage: 5000, Duration: 1},                        // tanpa started_at, diabaikan
+	}
+
+	windows := Overloads(records, 1105)
+
+	assert.Len(t, windows, 1)
+	assert.Equal(t, *at(9, 15), windows[0].Start)
+	assert.Equal(t, *at(9, 45), windows[0].End)
+	assert.Equal(t, 1350.0, windows[0].PeakWatts)
+	assert.Equal(t, []int{1, 2, 3}, windows[0].RecordIDs)
+}
+
+func TestOverloads_NoLimit(t *testing.T) {
+	records := []models.EnergyRecord{{ID: 1, Usage: 5000, Duration: 1, StartedAt: at(8, 0)}}
+
+	assert.Empty(t, Overloads(records, 0))
+	assert.Len(t, Overloads(records, 1000), 1)
+}
@@ -0,0 +1,241 @@
+package config
+
+import (
+	"daya-listrik-api/internal/auth"
+	"daya-listrik-api/internal/capacity"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Config adalah seluruh pengaturan server. Nilainya diisi Load dari default,
+// file YAML, .env, environment variable dan flag, lalu dicek dengan Validate.
+type Config struct {
+	Server    Server    `yaml:"server"`
+	Database  Database  `yaml:"database"`
+	Auth      Auth      `yaml:"auth"`
+	Household Household `yaml:"household"`
+	Billing   Billing   `yaml:"billing"`
+	Records   Records   `yaml:"records"`
+}
+
+type Server struct {
+	Port         int           `yaml:"port"`
+	CORSOrigins  []string      `yaml:"cors_origins"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout adalah waktu tunggu request yang sedang berjalan saat
+	// server menerima SIGINT/SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// Addr mengembalikan alamat listen, mis. ":8080".
+func (s Server) Addr() string {
+	return fmt.Sprintf(":%d", s.Port)
+}
+
+type Database struct {
+	// Driver menentukan tempat penyimpanan data. Driver sqlite menyimpan
+	// semua data di file Path tanpa PostgreSQL; driver memory menyimpan
+	// semua data di memori proses sehingga hilang saat server berhenti.
+	Driver string `yaml:"driver"`
+	// Path adalah file database SQLite untuk Driver sqlite.
+	Path            string        `yaml:"path"`
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	Name            string        `yaml:"name"`
+	SSLMode         string        `yaml:"sslmode"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	// QueryTimeout membatasi setiap pemanggilan repository; 0 berarti tanpa
+	// batas.
+	QueryTimeout time.Duration `yaml:"query_timeout"`
+	// AutoMigrate menjalankan migrasi saat server start. Matikan di produksi
+	// bila migrasi dijalankan terpisah dengan cmd/migrate.
+	AutoMigrate bool `yaml:"auto_migrate"`
+	// MigrationsDir memuat migrasi dari disk, bukan yang di-embed di binary,
+	// untuk development.
+	MigrationsDir string `yaml:"migrations_dir"`
+}
+
+// Driver penyimpanan record energi yang didukung.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+var Drivers = []string{DriverPostgres, DriverSQLite, DriverMemory}
+
+// SSLModes adalah nilai sslmode yang didukung lib/pq.
+var SSLModes = []string{"disable", "require", "verify-ca", "verify-full"}
+
+// DSN mengembalikan connection string lib/pq dalam format key=value.
+func (d Database) DSN() string {
+	parts := []string{
+		"host=" + quoteDSN(d.Host),
+		fmt.Sprintf("port=%d", d.Port),
+		"user=" + quoteDSN(d.User),
+		"password=" + quoteDSN(d.Password),
+		"dbname=" + quoteDSN(d.Name),
+		"sslmode=" + quoteDSN(d.SSLMode),
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteDSN memberi kutip pada nilai yang kosong atau mengandung spasi, kutip
+// atau backslash.
+func quoteDSN(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+type Auth struct {
+	JWTSecret  string        `yaml:"jwt_secret"`
+	AccessTTL  time.Duration `yaml:"access_ttl"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl"`
+}
+
+// Household adalah pengaturan awal rumah tangga baru.
+type Household struct {
+	TariffClass  string `yaml:"tariff_class"`
+	ContractedVA int    `yaml:"contracted_va"`
+	Timezone     string `yaml:"timezone"`
+}
+
+type Billing struct {
+	PPJRate     float64 `yaml:"ppj_rate"`
+	PowerFactor float64 `yaml:"power_factor"`
+}
+
+type Records struct {
+	FutureTolerance  time.Duration `yaml:"future_tolerance"`
+	MaxDurationHours float64       `yaml:"max_duration_hours"`
+}
+
+// Default mengembalikan pengaturan bawaan. JWTSecret sengaja kosong sehingga
+// harus diisi sendiri.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:            8080,
+			CORSOrigins:     []string{"http://localhost:5173"},
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Database: Database{
+			Driver:          DriverPostgres,
+			Path:            "daya-listrik.db",
+			Host:            "localhost",
+			Port:            5432,
+			User:            "postgres",
+			Name:            "db_daya_listrik",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			QueryTimeout:    repository.DefaultQueryTimeout,
+			AutoMigrate:     true,
+		},
+		Auth: Auth{
+			AccessTTL:  auth.DefaultAccessTTL,
+			RefreshTTL: auth.DefaultRefreshTTL,
+		},
+		Household: Household{
+			TariffClass: models.DefaultTariffClass,
+			Timezone:    models.DefaultTimezone,
+		},
+		Billing: Billing{
+			PPJRate:     0.10,
+			PowerFactor: capacity.DefaultPowerFactor,
+		},
+		Records: Records{
+			FutureTolerance:  5 * time.Minute,
+			MaxDurationHours: 24,
+		},
+	}
+}
+
+// Validate mengecek semua pengaturan sekaligus dan menormalkan zona waktu ke
+// nama IANA.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	errs = append(errs, c.Database.Validate())
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "PORT must be between 1 and 65535, got %d", c.Server.Port)
+	check(len(c.Server.CORSOrigins) > 0, "CORS_ORIGINS must list at least one origin")
+	for _, origin := range c.Server.CORSOrigins {
+		u, err := url.Parse(origin)
+		check(origin == "*" || (err == nil && u.Scheme != "" && u.Host != ""), "CORS_ORIGINS contains invalid origin %q", origin)
+	}
+	check(c.Server.ReadTimeout >= 0 && c.Server.WriteTimeout >= 0 && c.Server.IdleTimeout >= 0, "server timeouts must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+
+	check(len(c.Auth.JWTSecret) >= 32, "JWT_SECRET must be set to at least 32 characters")
+	check(c.Auth.AccessTTL > 0, "JWT_ACCESS_TTL must be positive")
+	check(c.Auth.RefreshTTL > 0, "JWT_REFRESH_TTL must be positive")
+
+	check(models.ValidTariffClass(c.Household.TariffClass), "unknown TARIFF_CLASS %q", c.Household.TariffClass)
+	check(c.Household.ContractedVA >= 0, "CONTRACTED_VA must not be negative")
+	if loc, err := models.LoadTimezone(c.Household.Timezone); err != nil {
+		errs = append(errs, fmt.Errorf("invalid TIMEZONE: %w", err))
+	} else {
+		c.Household.Timezone = loc.String()
+	}
+
+	check(c.Billing.PPJRate >= 0 && c.Billing.PPJRate <= 1, "PPJ_RATE must be a fraction such as 0.10, got %g", c.Billing.PPJRate)
+	check(c.Billing.PowerFactor > 0 && c.Billing.PowerFactor <= 1, "POWER_FACTOR must be in (0, 1], got %g", c.Billing.PowerFactor)
+
+	check(c.Records.FutureTolerance >= 0, "RECORD_FUTURE_TOLERANCE must not be negative")
+	check(c.Records.MaxDurationHours > 0 && c.Records.MaxDurationHours <= models.MaxRecordDuration, "RECORD_MAX_DURATION_HOURS must be in (0, %d], got %g", models.MaxRecordDuration, c.Records.MaxDurationHours)
+
+	return errors.Join(errs...)
+}
+
+// Validate mengecek pengaturan database saja, untuk perintah seperti
+// cmd/migrate yang tidak butuh pengaturan server lainnya.
+func (d Database) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(slices.Contains(Drivers, d.Driver), "DB_DRIVER must be one of %s, got %q", strings.Join(Drivers, ", "), d.Driver)
+	check(d.Driver != DriverSQLite || d.Path != "", "DB_PATH is required when DB_DRIVER is %s", DriverSQLite)
+	// Pengaturan PostgreSQL hanya dipakai oleh driver postgres.
+	if d.Driver == DriverPostgres {
+		check(d.Host != "", "DB_HOST is required")
+		check(d.Port > 0 && d.Port <= 65535, "DB_PORT must be between 1 and 65535, got %d", d.Port)
+		check(d.User != "", "DB_USER is required")
+		check(d.Name != "", "DB_NAME is required")
+		check(slices.Contains(SSLModes, d.SSLMode), "DB_SSLMODE must be one of %s, got %q", strings.Join(SSLModes, ", "), d.SSLMode)
+	}
+	check(d.MaxOpenConns >= 0, "DB_MAX_OPEN_CONNS must not be negative")
+	check(d.MaxIdleConns >= 0, "DB_MAX_IDLE_CONNS must not be negative")
+	check(d.MaxOpenConns == 0 || d.MaxIdleConns <= d.MaxOpenConns, "DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS")
+	check(d.ConnMaxLifetime >= 0, "DB_CONN_MAX_LIFETIME must not be negative")
+	check(d.QueryTimeout >= 0, "QUERY_TIMEOUT must not be negative")
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// clearEnv mengosongkan semua environment variable pengaturan agar test tidak
+// terpengaruh environment mesin.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	cfg := Default()
+	for _, s := range cfg.settings() {
+		t.Setenv(s.key, "")
+	}
+	t.Setenv("CONFIG_FILE", "")
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET", testSecret)
+
+	cfg, err := Load([]string{"-env-file", filepath.Join(t.TempDir(), "missing.env")})
+
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.Server.Addr())
+	assert.Equal(t, []string{"http://localhost:5173"}, cfg.Server.CORSOrigins)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, "disable", cfg.Database.SSLMode)
+	assert.Equal(t, "Asia/Jakarta", cfg.Household.Timezone)
+	assert.Empty(t, cfg.Database.MigrationsDir, "embedded migrations by default")
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	clearEnv(t)
+	yamlFile := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+  cors_origins: ["https://yaml.example"]
+database:
+  host: yaml-host
+  port: 6432
+  sslmode: require
+auth:
+  jwt_secret: `+testSecret+`
+billing:
+  ppj_rate: 0.05
+`)
+	envFile := writeFile(t, ".env", "DB_HOST=dotenv-host\nDB_USER=dotenv-user\nPPJ_RATE=0.07\n")
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("DB_PORT", "7432")
+	t.Setenv("CORS_ORIGINS", "https://a.example, https://b.example")
+	t.Setenv("AUTO_MIGRATE", "true")
+
+	cfg, err := Load([]string{"-config", yamlFile, "-env-file", envFile, "-db-port", "8432", "-query-timeout", "2s", "-auto-migrate=false"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 9000, cfg.Server.Port, "YAML overrides defaults")
+	assert.Equal(t, "require", cfg.Database.SSLMode)
+	assert.Equal(t, "dotenv-user", cfg.Database.User, ".env overrides YAML")
+	assert.Equal(t, 0.07, cfg.Billing.PPJRate)
+	assert.Equal(t, "env-host", cfg.Database.Host, "env is not overridden by .env")
+	assert.Equal(t, []string{"https://a.example", "https://b.example"}, cfg.Server.CORSOrigins)
+	assert.Equal(t, 8432, cfg.Database.Port, "flags override env")
+	assert.Equal(t, 2*time.Second, cfg.Database.QueryTimeout)
+	assert.False(t, cfg.Database.AutoMigrate)
+}
+
+func TestParse_ReturnsArguments(t *testing.T) {
+	clearEnv(t)
+
+	cfg, rest, err := Parse("migrate", []string{"-env-file", "", "-db-name", "other", "-migrations-dir", "db", "down", "2"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"down", "2"}, rest)
+	assert.Equal(t, "other", cfg.Database.Name)
+	assert.Equal(t, "db", cfg.Database.MigrationsDir)
+	assert.NoError(t, cfg.Database.Validate(), "database settings do not need JWT_SECRET")
+	assert.Error(t, cfg.Validate())
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JWT_SECRET", testSecret)
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", "server:\n  port: 9100\n"))
+
+	cfg, err := Load([]string{"-env-file", ""})
+
+	require.NoError(t, err)
+	assert.Equal(t, 9100, cfg.Server.Port)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want []string
+	}{
+		{"missing config file", nil, []string{"-config", missing}, []string{"error reading config file"}},
+		{"unknown flag", nil, []string{"-nope"}, []string{"flag provided but not defined"}},
+		{"positional argument", nil, []string{"serve"}, []string{`unexpected argument "serve"`}},
+		{
+			"unparsable values",
+			map[string]string{"DB_PORT": "abc", "QUERY_TIMEOUT": "soon"},
+			nil,
+			[]string{`invalid DB_PORT "abc"`, `invalid QUERY_TIMEOUT "soon"`},
+		},
+		{
+			"every invalid setting is reported",
+			map[string]string{"DB_SSLMODE": "maybe", "PORT": "70000", "TIMEZONE": "Mars/Base"},
+			nil,
+			[]string{"JWT_SECRET", "DB_SSLMODE", "PORT must be between", "invalid TIMEZONE"},
+		},
+		{"record duration disabled", map[string]string{"RECORD_MAX_DURATION_HOURS": "0"}, nil, []string{"RECORD_MAX_DURATION_HOURS must be in (0, 24], got 0"}},
+		{"record duration above a day", map[string]string{"RECORD_MAX_DURATION_HOURS": "48"}, nil, []string{"RECORD_MAX_DURATION_HOURS must be in (0, 24], got 48"}},
+		{"unknown driver", map[string]string{"DB_DRIVER": "mysql"}, nil, []string{`DB_DRIVER must be one of postgres, sqlite, memory, got "mysql"`}},
+		{"sqlite without path", map[string]string{"DB_DRIVER": "sqlite"}, []string{"-db-path="}, []string{"DB_PATH is required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := Load(append([]string{"-env-file", ""}, tt.args...))
+
+			require.Error(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestDatabase_Validate_WithoutPostgres(t *testing.T) {
+	d := Default().Database
+	d.Driver = DriverSQLite
+	d.Host, d.User, d.Name, d.SSLMode, d.Port = "", "", "", "", 0
+
+	assert.NoError(t, d.Validate(), "SQLite keeps every table in DB_PATH")
+	d.Driver = DriverMemory
+	assert.NoError(t, d.Validate(), "memory keeps every table in the process")
+	d.Driver = DriverPostgres
+	assert.ErrorContains(t, d.Validate(), "DB_HOST is required")
+}
+
+func TestDatabase_DSN(t *testing.T) {
+	d := Default().Database
+	d.Host = "db"
+	d.Password = `p@ss w'rd\`
+
+	dsn := d.DSN()
+
+	assert.Equal(t, `host=db port=5432 user=postgres password='p@ss w\'rd\\' dbname=db_daya_listrik sslmode=disable`, dsn)
+	d.Password = ""
+	assert.True(t, strings.Contains(d.DSN(), "password=''"))
+}
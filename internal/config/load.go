@@ -0,0 +1,190 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// setting menghubungkan satu field Config dengan environment variable dan
+// flag-nya. Nama flag adalah key dalam huruf kecil dengan "-", mis. DB_HOST
+// menjadi -db-host.
+type setting struct {
+	key   string
+	usage string
+	value any
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"PORT", "HTTP port", &c.Server.Port},
+		{"CORS_ORIGINS", "comma-separated allowed CORS origins", &c.Server.CORSOrigins},
+		{"SERVER_READ_TIMEOUT", "HTTP read timeout", &c.Server.ReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", "HTTP write timeout", &c.Server.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", "HTTP keep-alive idle timeout", &c.Server.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", "how long in-flight requests may finish after SIGINT/SIGTERM", &c.Server.ShutdownTimeout},
+		{"DB_DRIVER", "energy record storage: " + strings.Join(Drivers, " or "), &c.Database.Driver},
+		{"DB_PATH", "SQLite database file for DB_DRIVER=sqlite", &c.Database.Path},
+		{"DB_HOST", "database host", &c.Database.Host},
+		{"DB_PORT", "database port", &c.Database.Port},
+		{"DB_USER", "database user", &c.Database.User},
+		{"DB_PASSWORD", "database password", &c.Database.Password},
+		{"DB_NAME", "database name", &c.Database.Name},
+		{"DB_SSLMODE", "database sslmode", &c.Database.SSLMode},
+		{"DB_MAX_OPEN_CONNS", "maximum open database connections, 0 for unlimited", &c.Database.MaxOpenConns},
+		{"DB_MAX_IDLE_CONNS", "maximum idle database connections", &c.Database.MaxIdleConns},
+		{"DB_CONN_MAX_LIFETIME", "maximum lifetime of a database connection", &c.Database.ConnMaxLifetime},
+		{"QUERY_TIMEOUT", "timeout per repository call, 0 to disable", &c.Database.QueryTimeout},
+		{"AUTO_MIGRATE", "run pending migrations on startup", &c.Database.AutoMigrate},
+		{"MIGRATIONS_DIR", "load migrations from this directory instead of the embedded ones", &c.Database.MigrationsDir},
+		{"JWT_SECRET", "JWT signing key, at least 32 characters", &c.Auth.JWTSecret},
+		{"JWT_ACCESS_TTL", "access token lifetime", &c.Auth.AccessTTL},
+		{"JWT_REFRESH_TTL", "refresh token lifetime", &c.Auth.RefreshTTL},
+		{"TARIFF_CLASS", "tariff class of new households", &c.Household.TariffClass},
+		{"CONTRACTED_VA", "contracted VA of new households, 0 to follow the tariff class", &c.Household.ContractedVA},
+		{"TIMEZONE", "time zone of new households", &c.Household.Timezone},
+		{"PPJ_RATE", "street lighting tax rate", &c.Billing.PPJRate},
+		{"POWER_FACTOR", "power factor for overload detection", &c.Billing.PowerFactor},
+		{"RECORD_FUTURE_TOLERANCE", "how far in the future record timestamps may be", &c.Records.FutureTolerance},
+		{"RECORD_MAX_DURATION_HOURS", "maximum record duration in hours, at most 24", &c.Records.MaxDurationHours},
+	}
+}
+
+func (s setting) flagName() string {
+	return strings.ToLower(strings.ReplaceAll(s.key, "_", "-"))
+}
+
+// set mengisi field dari teks env atau flag sesuai tipe field-nya.
+func (s setting) set(raw string) error {
+	var err error
+	switch target := s.value.(type) {
+	case *string:
+		*target = raw
+	case *int:
+		*target, err = strconv.Atoi(strings.TrimSpace(raw))
+	case *bool:
+		*target, err = strconv.ParseBool(strings.TrimSpace(raw))
+	case *float64:
+		*target, err = strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	case *time.Duration:
+		*target, err = time.ParseDuration(strings.TrimSpace(raw))
+	case *[]string:
+		*target = nil
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*target = append(*target, item)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", s.key, raw)
+	}
+	return nil
+}
+
+// rawFlag menyimpan teks flag apa adanya agar diurai oleh setting.set seperti
+// environment variable. Flag bool boleh ditulis tanpa nilai, mis. -auto-migrate.
+type rawFlag struct {
+	value  string
+	isBool bool
+}
+
+func (f *rawFlag) String() string     { return f.value }
+func (f *rawFlag) Set(v string) error { f.value = v; return nil }
+func (f *rawFlag) IsBoolFlag() bool   { return f.isBool }
+
+// Load membaca pengaturan server lewat Parse dan memvalidasi semuanya. args
+// hanya boleh berisi flag.
+func Load(args []string) (*Config, error) {
+	cfg, rest, err := Parse("server", args)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", rest[0])
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Parse membaca pengaturan dengan urutan prioritas dari yang terendah:
+// Default, file YAML (-config atau CONFIG_FILE), file .env (-env-file),
+// environment variable, lalu flag di args. .env hanya mengisi key yang tidak
+// ada di environment dan boleh tidak ada. Argumen setelah flag dikembalikan
+// sebagai rest. Hasilnya belum divalidasi.
+func Parse(name string, args []string) (cfg *Config, rest []string, err error) {
+	c := Default()
+	cfg = &c
+	settings := cfg.settings()
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := flags.String("config", "", "YAML configuration file (default $CONFIG_FILE)")
+	envFile := flags.String("env-file", ".env", "dotenv file read when present, empty to skip")
+	values := make(map[string]*rawFlag, len(settings))
+	for _, s := range settings {
+		_, isBool := s.value.(*bool)
+		values[s.key] = &rawFlag{isBool: isBool}
+		flags.Var(values[s.key], s.flagName(), s.usage+" ($"+s.key+")")
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	dotenv := map[string]string{}
+	if *envFile != "" {
+		var err error
+		if dotenv, err = godotenv.Read(*envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("error loading %s: %w", *envFile, err)
+		}
+	}
+	// lookup mengutamakan environment variable; nilai kosong dianggap tidak
+	// diisi karena docker-compose meneruskan variabel yang tidak diset sebagai
+	// string kosong.
+	lookup := func(key string) string {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+		return dotenv[key]
+	}
+
+	if *configFile == "" {
+		*configFile = lookup("CONFIG_FILE")
+	}
+	if *configFile != "" {
+		data, err := os.ReadFile(*configFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, nil, fmt.Errorf("error parsing config file %s: %w", *configFile, err)
+		}
+	}
+
+	var errs []error
+	for _, s := range settings {
+		if raw := lookup(s.key); raw != "" {
+			errs = append(errs, s.set(raw))
+		}
+	}
+	flags.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flagName() == f.Name {
+				errs = append(errs, s.set(values[s.key].value))
+			}
+		}
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+	return cfg, flags.Args(), nil
+}
@@ -0,0 +1,457 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"daya-listrik-api/internal/config"
+	"daya-listrik-api/migrations"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration adalah satu versi skema dari pasangan file
+// NNN_nama.up.sql dan NNN_nama.down.sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// Checksum adalah SHA-256 file up; perubahan file yang sudah dijalankan
+	// terdeteksi lewat nilai ini.
+	Checksum string
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// migrationLockID adalah key pg_advisory_lock yang dipegang selama migrasi
+// agar dua replika tidak menjalankan migrasi bersamaan.
+const migrationLockID int64 = 7_340_512_026
+
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+// createSQLiteSchemaMigrations memakai DATETIME agar applied_at terbaca
+// sebagai time.Time oleh driver SQLite.
+const createSQLiteSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// MigrationsFS mengembalikan migrasi yang di-embed di binary, atau folder dir
+// di disk bila diisi (untuk development).
+func MigrationsFS(dir string) fs.FS {
+	if dir == "" {
+		return migrations.FS
+	}
+	log.Printf("Loading migrations from %s instead of the embedded files", dir)
+	return os.DirFS(dir)
+}
+
+// LoadMigrations membaca semua migrasi di root fsys, diurutkan menurut versi.
+// Setiap versi wajib punya file up dan down.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations directory: %v", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".sql" {
+			continue
+		}
+		match := migrationFile.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s, expected NNN_name.up.sql or NNN_name.down.sql", file.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %v", file.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration file %s: %v", file.Name(), err)
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			sum := sha256.Sum256(content)
+			m.Up, m.Checksum = string(content), hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	loaded := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Checksum == "" {
+			return nil, fmt.Errorf("migration %03d_%s has no up file", m.Version, m.Name)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("migration %03d_%s has no down file", m.Version, m.Name)
+		}
+		loaded = append(loaded, *m)
+	}
+	slices.SortFunc(loaded, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return loaded, nil
+}
+
+// AppliedMigration adalah satu baris schema_migrations.
+type AppliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator menjalankan migrasi ke database Postgres, atau SQLite bila Driver
+// adalah config.DriverSQLite.
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []Migration
+	Driver     string
+}
+
+// NewMigrator membuat Migrator dengan migrasi dari fsys.
+func NewMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	loaded, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{DB: db, Migrations: loaded}, nil
+}
+
+// Up menjalankan semua migrasi yang belum tercatat di schema_migrations secara
+// berurutan. Migrasi yang sudah jalan tetapi file-nya berubah atau hilang
+// membatalkan proses sebelum ada migrasi baru yang dijalankan.
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.Migrations) == 0 {
+		return nil
+	}
+	return m.Goto(ctx, m.Migrations[len(m.Migrations)-1].Version)
+}
+
+// Down membatalkan n migrasi terakhir yang sudah dijalankan, dari versi
+// tertinggi.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.check(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(applied) - 1; i >= 0 && i >= len(applied)-n; i-- {
+			if err := m.revert(ctx, conn, m.find(applied[i].Version)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Goto membawa skema ke version: migrasi sampai version yang belum jalan
+// dijalankan, migrasi di atas version dibatalkan. Version 0 membatalkan
+// semuanya.
+func (m *Migrator) Goto(ctx context.Context, version int64) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.check(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(applied) - 1; i >= 0; i-- {
+			if applied[i].Version > version {
+				if err := m.revert(ctx, conn, m.find(applied[i].Version)); err != nil {
+					return err
+				}
+			}
+		}
+
+		done := map[int64]bool{}
+		for _, a := range applied {
+			done[a.Version] = true
+		}
+		for i := range m.Migrations {
+			migration := &m.Migrations[i]
+			if migration.Version > version || done[migration.Version] {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MigrationStatus adalah keadaan satu migrasi untuk perintah status.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Modified berarti file up berubah setelah migrasinya dijalankan.
+	Modified bool
+	// Missing berarti migrasi tercatat di database tetapi file-nya tidak ada.
+	Missing bool
+}
+
+// Status mengembalikan keadaan semua migrasi, termasuk yang tercatat di
+// database tetapi file-nya hilang, diurutkan menurut versi. Status hanya
+// membaca, jadi tidak menunggu migrasi yang sedang berjalan.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.readApplied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]AppliedMigration{}
+	for _, a := range applied {
+		byVersion[a.Version] = a
+	}
+
+	var statuses []MigrationStatus
+	for _, migration := range m.Migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if a, ok := byVersion[migration.Version]; ok {
+			status.Applied, status.AppliedAt = true, a.AppliedAt
+			status.Modified = a.Checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	for _, a := range applied {
+		if m.find(a.Version) == nil {
+			statuses = append(statuses, MigrationStatus{Version: a.Version, Name: a.Name, Applied: true, AppliedAt: a.AppliedAt, Missing: true})
+		}
+	}
+	slices.SortFunc(statuses, func(a, b MigrationStatus) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return statuses, nil
+}
+
+// Verify mengembalikan error bila ada migrasi yang sudah dijalankan tetapi
+// file-nya berubah atau hilang. Seperti Status, Verify hanya membaca.
+func (m *Migrator) Verify(ctx context.Context) error {
+	applied, err := m.readApplied(ctx)
+	if err != nil {
+		return err
+	}
+	return m.compare(applied)
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+			migration.Version, migration.Name, migration.Checksum)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error applying migration %03d_%s: %w", migration.Version, migration.Name, err)
+	}
+	log.Printf("Migration %03d_%s applied", migration.Version, migration.Name)
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error reverting migration %03d_%s: %w", migration.Version, migration.Name, err)
+	}
+	log.Printf("Migration %03d_%s reverted", migration.Version, migration.Name)
+	return nil
+}
+
+// withLock menjalankan fn pada satu koneksi yang memegang advisory lock
+// migrasi dan memastikan tabel schema_migrations ada. File SQLite hanya
+// dipakai satu server, jadi tidak perlu lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Close()
+
+	if m.Driver == config.DriverSQLite {
+		if _, err := conn.ExecContext(ctx, createSQLiteSchemaMigrations); err != nil {
+			return fmt.Errorf("error creating schema_migrations: %w", err)
+		}
+		return fn(conn)
+	}
+
+	// Advisory lock terikat pada sesi, jadi lock dan unlock harus lewat
+	// koneksi yang sama.
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+// check membaca migrasi yang sudah dijalankan, diurutkan menurut versi, dan
+// memastikan setiap migrasi tersebut masih ada dengan checksum yang sama.
+func (m *Migrator) check(ctx context.Context, conn *sql.Conn) ([]AppliedMigration, error) {
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.compare(applied); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// compare memastikan setiap migrasi di applied masih ada dengan checksum yang
+// sama. Semua penyimpangan dilaporkan sekaligus.
+func (m *Migrator) compare(applied []AppliedMigration) error {
+	var errs []error
+	for _, a := range applied {
+		migration := m.find(a.Version)
+		if migration == nil {
+			errs = append(errs, fmt.Errorf("applied migration %03d_%s is missing from the migration files", a.Version, a.Name))
+		} else if migration.Checksum != a.Checksum {
+			errs = append(errs, fmt.Errorf("migration %03d_%s was modified after it was applied (checksum %s, recorded %s)",
+				a.Version, a.Name, migration.Checksum, a.Checksum))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.Migrations {
+		if m.Migrations[i].Version == version {
+			return &m.Migrations[i]
+		}
+	}
+	return nil
+}
+
+// readApplied membaca schema_migrations tanpa advisory lock dan tanpa
+// membuat tabelnya. Tabel yang belum ada berarti belum ada migrasi yang
+// dijalankan.
+func (m *Migrator) readApplied(ctx context.Context) ([]AppliedMigration, error) {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Close()
+
+	query := `SELECT to_regclass('schema_migrations') IS NOT NULL`
+	if m.Driver == config.DriverSQLite {
+		query = `SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`
+	}
+	var exists bool
+	if err := conn.QueryRowContext(ctx, query).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	return appliedMigrations(ctx, conn)
+}
+
+func appliedMigrations(ctx context.Context, conn *sql.Conn) ([]AppliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var a AppliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+		}
+		applied = append(applied, a)
+	}
+	return applied, rows.Err()
+}
+
+// inTx menjalankan fn dalam satu transaksi sehingga migrasi yang gagal tidak
+// meninggalkan skema setengah jadi.
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+var nonWord = regexp.MustCompile(`\W+`)
+
+// CreateMigration membuat pasangan file up dan down kosong di dir dengan
+// versi berikutnya, mis. "add meter photo" menjadi 012_add_meter_photo.up.sql.
+func CreateMigration(dir, name string) ([]string, error) {
+	name = strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, fmt.Errorf("migration name must contain letters or digits")
+	}
+	existing, err := LoadMigrations(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+	var version int64 = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%03d_%s.%s.sql", version, name, direction))
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return paths, fmt.Errorf("error creating migration file: %w", err)
+		}
+		fmt.Fprintf(file, "-- %03d_%s (%s)\n", version, name, direction)
+		if err := file.Close(); err != nil {
+			return paths, fmt.Errorf("error creating migration file: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
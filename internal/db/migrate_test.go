@@ -0,0 +1,386 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"daya-listrik-api/internal/config"
+	"daya-listrik-api/migrations"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	lockQuery   = `SELECT pg_advisory_lock($1)`
+	unlockQuery = `SELECT pg_advisory_unlock($1)`
+	selectQuery = `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`
+	insertQuery = `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`
+	deleteQuery = `DELETE FROM schema_migrations WHERE version = $1`
+	existsQuery = `SELECT to_regclass('schema_migrations') IS NOT NULL`
+)
+
+var appliedAt = time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "create_a", Up: "CREATE TABLE a (id INT)", Down: "DROP TABLE a", Checksum: "sum1"},
+		{Version: 2, Name: "create_b", Up: "CREATE TABLE b (id INT)", Down: "DROP TABLE b", Checksum: "sum2"},
+		{Version: 3, Name: "create_c", Up: "CREATE TABLE c (id INT)", Down: "DROP TABLE c", Checksum: "sum3"},
+	}
+}
+
+// newTestMigrator menyiapkan sqlmock yang mengharapkan lock, pembuatan
+// schema_migrations dan pembacaan migrasi yang sudah dijalankan.
+func newTestMigrator(t *testing.T, applied ...[]driver.Value) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectExec(lockQuery).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(createSchemaMigrations).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"})
+	for _, row := range applied {
+		rows.AddRow(append(row, appliedAt)...)
+	}
+	mock.ExpectQuery(selectQuery).WillReturnRows(rows)
+	return &Migrator{DB: db, Migrations: testMigrations()}, mock
+}
+
+// newReadOnlyMigrator menyiapkan sqlmock untuk Status dan Verify yang hanya
+// membaca schema_migrations, tanpa lock dan tanpa CREATE TABLE.
+func newReadOnlyMigrator(t *testing.T, applied ...[]driver.Value) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	mock.ExpectQuery(existsQuery).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	rows := sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"})
+	for _, row := range applied {
+		rows.AddRow(append(row, appliedAt)...)
+	}
+	mock.ExpectQuery(selectQuery).WillReturnRows(rows)
+	return &Migrator{DB: db, Migrations: testMigrations()}, mock
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(unlockQuery).WithArgs(migrationLockID).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	m, mock := newTestMigrator(t, []driver.Value{1, "create_a", "sum1"})
+	for _, migration := range m.Migrations[1:] {
+		mock.ExpectBegin()
+		mock.ExpectExec(migration.Up).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(insertQuery).WithArgs(migration.Version, migration.Name, migration.Checksum).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	expectUnlock(mock)
+
+	err := m.Up(context.Background())
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_FailureRollsBack(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(m.Migrations[0].Up).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(m.Migrations[1].Up).WillReturnError(errors.New(`relation "b" already exists`))
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	err := m.Up(context.Background())
+
+	assert.ErrorContains(t, err, "error applying migration 002_create_b")
+	assert.NoError(t, mock.ExpectationsWereMet(), "migration 003 must not run after 002 fails")
+}
+
+func TestMigrator_Up_Drift(t *testing.T) {
+	tests := []struct {
+		name    string
+		applied []driver.Value
+		want    string
+	}{
+		{"modified file", []driver.Value{1, "create_a", "other"}, "001_create_a was modified after it was applied"},
+		{"missing file", []driver.Value{9, "create_z", "sum9"}, "applied migration 009_create_z is missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mock := newTestMigrator(t, tt.applied)
+			expectUnlock(mock)
+
+			err := m.Up(context.Background())
+
+			assert.ErrorContains(t, err, tt.want)
+			assert.NoError(t, mock.ExpectationsWereMet(), "no migration runs when applied ones drifted")
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	m, mock := newTestMigrator(t, []driver.Value{1, "create_a", "sum1"}, []driver.Value{2, "create_b", "sum2"})
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE b").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(deleteQuery).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	err := m.Down(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Goto(t *testing.T) {
+	t.Run("down to a lower version", func(t *testing.T) {
+		m, mock := newTestMigrator(t, []driver.Value{1, "create_a", "sum1"}, []driver.Value{2, "create_b", "sum2"}, []driver.Value{3, "create_c", "sum3"})
+		for _, down := range []struct {
+			sql     string
+			version int64
+		}{{"DROP TABLE c", 3}, {"DROP TABLE b", 2}} {
+			mock.ExpectBegin()
+			mock.ExpectExec(down.sql).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(deleteQuery).WithArgs(down.version).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+		expectUnlock(mock)
+
+		err := m.Goto(context.Background(), 1)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("up to a higher version", func(t *testing.T) {
+		m, mock := newTestMigrator(t)
+		for _, migration := range m.Migrations[:2] {
+			mock.ExpectBegin()
+			mock.ExpectExec(migration.Up).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(insertQuery).WithArgs(migration.Version, migration.Name, migration.Checksum).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+		expectUnlock(mock)
+
+		err := m.Goto(context.Background(), 2)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet(), "migration 003 stays pending")
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		m := &Migrator{Migrations: testMigrations()}
+
+		err := m.Goto(context.Background(), 7)
+
+		assert.EqualError(t, err, "unknown migration version 7")
+	})
+}
+
+func TestMigrator_Status(t *testing.T) {
+	m, mock := newReadOnlyMigrator(t, []driver.Value{1, "create_a", "sum1"}, []driver.Value{2, "create_b", "old"}, []driver.Value{4, "create_d", "sum4"})
+
+	statuses, err := m.Status(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []MigrationStatus{
+		{Version: 1, Name: "create_a", Applied: true, AppliedAt: appliedAt},
+		{Version: 2, Name: "create_b", Applied: true, AppliedAt: appliedAt, Modified: true},
+		{Version: 3, Name: "create_c"},
+		{Version: 4, Name: "create_d", Applied: true, AppliedAt: appliedAt, Missing: true},
+	}, statuses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status_NoSchemaMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectQuery(existsQuery).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	m := &Migrator{DB: db, Migrations: testMigrations()}
+
+	statuses, err := m.Status(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	for _, s := range statuses {
+		assert.False(t, s.Applied, "migration %d is pending", s.Version)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet(), "status neither locks nor creates schema_migrations")
+}
+
+func TestMigrator_Verify(t *testing.T) {
+	m, mock := newReadOnlyMigrator(t, []driver.Value{1, "create_a", "old"}, []driver.Value{2, "create_b", "old"})
+
+	err := m.Verify(context.Background())
+
+	assert.ErrorContains(t, err, "001_create_a was modified")
+	assert.ErrorContains(t, err, "002_create_b was modified", "every drifted migration is reported")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_SQLite(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.Path = filepath.Join(t.TempDir(), "records.db")
+	conn, err := ConnectSQLite(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	m, err := NewMigrator(conn, migrations.SQLite)
+	require.NoError(t, err)
+	m.Driver = config.DriverSQLite
+	ctx := context.Background()
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	for _, s := range statuses {
+		assert.True(t, s.Applied, "migration %d is applied by ConnectSQLite", s.Version)
+		assert.False(t, s.AppliedAt.IsZero())
+	}
+
+	require.NoError(t, m.Goto(ctx, 0))
+	var tables int
+	require.NoError(t, conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'energy_records'`).Scan(&tables))
+	assert.Zero(t, tables, "down migrations drop the tables")
+
+	require.NoError(t, m.Up(ctx))
+	assert.NoError(t, m.Verify(ctx))
+}
+
+// TestMigrator_SQLiteUpgrade memastikan file SQLite yang dibuat dengan
+// migrasi 001 saja tetap terbaca setelah migrasi berikutnya, termasuk datanya.
+func TestMigrator_SQLiteUpgrade(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.Path = filepath.Join(t.TempDir(), "records.db")
+	conn, err := OpenSQLite(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	m, err := NewMigrator(conn, migrations.SQLite)
+	require.NoError(t, err)
+	m.Driver = config.DriverSQLite
+	ctx := context.Background()
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	for _, s := range statuses {
+		assert.False(t, s.Applied, "migration %d is pending in a new file", s.Version)
+	}
+	var tables int
+	require.NoError(t, conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_migrations'`).Scan(&tables))
+	assert.Zero(t, tables, "status does not create schema_migrations")
+
+	require.NoError(t, m.Goto(ctx, 1))
+	for _, query := range []string{
+		`INSERT INTO households (id, tariff_class) VALUES (10, 'R-1/900VA')`,
+		`INSERT INTO devices (id, household_id, name, category) VALUES (3, 10, 'Lampu', 'lighting')`,
+		`INSERT INTO energy_records (household_id, date, usage, duration, device, device_id, started_at)
+			VALUES (10, '2024-03-11 10:00:00.000000', 20, 2, 'Lampu', 3, '2024-03-11 08:00:00.000000')`,
+	} {
+		_, err := conn.Exec(query)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, m.Up(ctx))
+	var (
+		class       string
+		date        time.Time
+		deviceID    int
+		maxVA       sql.NullInt64
+		orphanedFKs int
+	)
+	require.NoError(t, conn.QueryRow(`SELECT tariff_class FROM households WHERE id = 10`).Scan(&class))
+	assert.Equal(t, "R-1/900VA", class)
+	require.NoError(t, conn.QueryRow(`SELECT date, device_id FROM energy_records WHERE household_id = 10`).Scan(&date, &deviceID))
+	assert.Equal(t, time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC), date.UTC(), "dates are read as time.Time")
+	assert.Equal(t, 3, deviceID)
+	require.NoError(t, conn.QueryRow(`SELECT max_va FROM tariffs WHERE class = 'R-1/900VA'`).Scan(&maxVA))
+	assert.Equal(t, int64(900), maxVA.Int64)
+	require.NoError(t, conn.QueryRow(`SELECT COUNT(*) FROM pragma_foreign_key_check`).Scan(&orphanedFKs))
+	assert.Zero(t, orphanedFKs)
+	var leftovers int
+	require.NoError(t, conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name LIKE '%_001' OR sql LIKE '%_001%'`).Scan(&leftovers))
+	assert.Zero(t, leftovers, "no table or reference points at the rebuilt tables")
+
+	require.NoError(t, m.Goto(ctx, 1))
+	var records int
+	require.NoError(t, conn.QueryRow(`SELECT COUNT(*) FROM energy_records WHERE device_id = 3`).Scan(&records))
+	assert.Equal(t, 1, records, "reverting keeps the records")
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"001_a.up.sql", "001_a.down.sql"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o600))
+	}
+
+	paths, err := CreateMigration(dir, "Add meter photo!")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "002_add_meter_photo.up.sql"),
+		filepath.Join(dir, "002_add_meter_photo.down.sql"),
+	}, paths)
+	migrations, err := LoadMigrations(os.DirFS(dir))
+	require.NoError(t, err, "created files form a valid migration")
+	assert.Len(t, migrations, 2)
+
+	_, err = CreateMigration(dir, "  ")
+	assert.Error(t, err)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	embedded, err := LoadMigrations(MigrationsFS(""))
+
+	require.NoError(t, err)
+	require.NotEmpty(t, embedded)
+	for i, migration := range embedded {
+		assert.Equal(t, int64(i+1), migration.Version, "versions are sorted without gaps")
+		assert.NotEmpty(t, migration.Down, "migration %d has a down file", migration.Version)
+		assert.Len(t, migration.Checksum, 64)
+	}
+
+	onDisk, err := LoadMigrations(MigrationsFS("../../migrations"))
+	require.NoError(t, err)
+	assert.Equal(t, embedded, onDisk, "the disk override reads the same files")
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{"missing down", []string{"001_a.up.sql"}, "001_a has no down file"},
+		{"missing up", []string{"001_a.down.sql"}, "001_a has no up file"},
+		{"bad name", []string{"create_a.sql"}, "invalid migration file name create_a.sql"},
+		{"duplicate version", []string{"001_a.up.sql", "001_a.down.sql", "001_b.up.sql"}, "duplicate migration version 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o600))
+			}
+
+			_, err := LoadMigrations(os.DirFS(dir))
+
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -1,86 +1,58 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"daya-listrik-api/internal/config"
 	"fmt"
+	"io/fs"
 	"log"
-	"os"
-	"path/filepath"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
-type DatabaseConfig struct {
-	Host     string
-	User     string
-	Password string
-	Name     string
-}
-
-func LoadDatabaseConfig() *DatabaseConfig {
-	err := godotenv.Load()
+// Connect membuka koneksi lewat Open lalu menjalankan migrasi bila
+// cfg.AutoMigrate aktif. Migrasi yang gagal membatalkan startup.
+func Connect(cfg config.Database) (*sql.DB, error) {
+	db, err := Open(cfg)
 	if err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
+		return nil, err
 	}
-
-	return &DatabaseConfig{
-		Host:     os.Getenv("DB_HOST"),
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		Name:     os.Getenv("DB_NAME"),
+	if !cfg.AutoMigrate {
+		log.Println("Auto-migration disabled, run cmd/migrate up before starting the server")
+		return db, nil
 	}
-}
 
-func Connect() (*sql.DB, error) {
-	config := LoadDatabaseConfig()
-    connStr := fmt.Sprintf("user=%s password=%s dbname=%s host=%s sslmode=disable",
-        config.User, config.Password, config.Name, config.Host)
-    db, err := sql.Open("postgres", connStr)
-    if err != nil {
-        return nil, fmt.Errorf("error connecting to database: %v", err)
-    }
-    if err := db.Ping(); err != nil {
-        return nil, fmt.Errorf("error pinging database: %v", err)
-    }
+	if err := RunMigrations(context.Background(), db, MigrationsFS(cfg.MigrationsDir)); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error running migrations: %w", err)
+	}
 
-	RunMigrations(db, "./migrations")
-	
-    return db, nil
+	return db, nil
 }
 
-// RunMigrations menjalankan semua migrasi dari folder yang ditentukan
-func RunMigrations(db *sql.DB, migrationsDir string) error {
-	files, err := os.ReadDir(migrationsDir)
+// Open membuka koneksi PostgreSQL sesuai cfg dan mengatur ukuran pool tanpa
+// menjalankan migrasi.
+func Open(cfg config.Database) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DSN())
 	if err != nil {
-		return fmt.Errorf("error reading migrations directory: %v", err)
+		return nil, fmt.Errorf("error connecting to database: %v", err)
 	}
-
-	for _, file := range files {
-		if filepath.Ext(file.Name()) == ".sql" {
-			migrationFile := filepath.Join(migrationsDir, file.Name())
-			err := executeMigration(db, migrationFile)
-			if err != nil {
-				return fmt.Errorf("error executing migration %s: %v", migrationFile, err)
-			}
-		}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error pinging database: %v", err)
 	}
-
-	return nil
+	return db, nil
 }
 
-// executeMigration membaca dan mengeksekusi query dari file migrasi
-func executeMigration(db *sql.DB, migrationFile string) error {
-	query, err := os.ReadFile(migrationFile)
+// RunMigrations menjalankan semua migrasi dari fsys yang belum dijalankan.
+func RunMigrations(ctx context.Context, db *sql.DB, fsys fs.FS) error {
+	migrator, err := NewMigrator(db, fsys)
 	if err != nil {
-		return fmt.Errorf("error reading migration file %s: %v", migrationFile, err)
+		return err
 	}
-
-	_, err = db.Exec(string(query))
-	if err != nil {
-		return fmt.Errorf("error executing migration: %v", err)
-	}
-
-	log.Printf("Migration %s executed successfully\n", migrationFile)
-	return nil
+	return migrator.Up(ctx)
 }
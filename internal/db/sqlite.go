@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/config"
+	"daya-listrik-api/migrations"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+// ConnectSQLite membuka file SQLite lewat OpenSQLite lalu selalu menjalankan
+// migrasi SQLite. AutoMigrate hanya berlaku untuk Postgres yang bisa dipakai
+// bersama beberapa replika; file SQLite hanya dipakai satu server.
+func ConnectSQLite(cfg config.Database) (*sql.DB, error) {
+	db, err := OpenSQLite(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := NewMigrator(db, SQLiteMigrationsFS(cfg.MigrationsDir))
+	if err == nil {
+		migrator.Driver = config.DriverSQLite
+		err = migrator.Up(context.Background())
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error running SQLite migrations: %w", err)
+	}
+	return db, nil
+}
+
+// SQLiteMigrationsFS mengembalikan migrasi SQLite yang di-embed, atau folder
+// sqlite di dalam dir bila dir diisi (MIGRATIONS_DIR).
+func SQLiteMigrationsFS(dir string) fs.FS {
+	if dir == "" {
+		return migrations.SQLite
+	}
+	return MigrationsFS(filepath.Join(dir, "sqlite"))
+}
+
+// OpenSQLite membuka (atau membuat) file database SQLite di cfg.Path dengan
+// foreign key aktif dan mode WAL agar pembacaan tidak menunggu penulisan.
+func OpenSQLite(cfg config.Database) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", sqliteDSN(cfg.Path))
+	if err != nil {
+		return nil, fmt.Errorf("error opening SQLite database: %v", err)
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error opening SQLite database %s: %v", cfg.Path, err)
+	}
+	return db, nil
+}
+
+// sqliteDSN menyusun URI file untuk driver modernc.org/sqlite. Pragma berlaku
+// untuk setiap koneksi di pool; busy_timeout membuat penulisan bersamaan
+// menunggu lock alih-alih langsung gagal.
+func sqliteDSN(path string) string {
+	pragmas := url.Values{"_pragma": {"foreign_keys(1)", "busy_timeout(5000)", "journal_mode(WAL)"}}
+	return "file:" + (&url.URL{Path: path}).EscapedPath() + "?" + pragmas.Encode()
+}
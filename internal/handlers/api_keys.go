@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// AddAPIKey membuat API key untuk rumah tangga aktif. Key hanya ditampilkan
+// pada respons ini.
+func AddAPIKey(repo repository.APIKeyRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+		userID, _ := requestUserID(w, r)
+
+		var body struct {
+			Label     string     `json:"label"`
+			Scopes    []string   `json:"scopes"`
+			ExpiresAt *time.Time `json:"expires_at"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+
+		key := models.APIKey{
+			HouseholdID: household.ID,
+			Label:       body.Label,
+			Scopes:      body.Scopes,
+			CreatedBy:   userID,
+			ExpiresAt:   body.ExpiresAt,
+		}
+		if err := key.Validate(time.Now()); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.AddAPIKey(r.Context(), &key); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(key)
+	}
+}
+
+func GetAPIKeys(repo repository.APIKeyRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		keys, err := repo.GetAPIKeys(r.Context(), household.ID)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(keys)
+	}
+}
+
+// DeleteAPIKeys mencabut API key; perangkat yang memakainya langsung ditolak.
+func DeleteAPIKeys(repo repository.APIKeyRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		id, err := validateParamId(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.DeleteAPIKey(r.Context(), household.ID, id); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
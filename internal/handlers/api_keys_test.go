@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddAPIKey_Success(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	handler := AddAPIKey(mockRepo)
+
+	mockRepo.On("AddAPIKey", mock.Anything, mock.MatchedBy(func(k *models.APIKey) bool {
+		return k.HouseholdID == testHouseholdID && k.CreatedBy == testUserID && k.Label == "Logger Pi" &&
+			len(k.Scopes) == 1 && k.Scopes[0] == models.ScopeRecordsWrite && k.RevokedAt == nil
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.APIKey).Key = "dlk_rahasia"
+	}).Return(nil)
+
+	w := httptest.NewRecorder()
+	handler(w, withUser(postJSON("/api/keys/add", map[string]any{"label": " Logger Pi ", "revoked_at": time.Now()})))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), "dlk_rahasia")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddAPIKey_Invalid(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	handler := AddAPIKey(mockRepo)
+
+	for name, body := range map[string]map[string]any{
+		"missing label": {"scopes": []string{models.ScopeRecordsWrite}},
+		"unknown scope": {"label": "Plug", "scopes": []string{"records:delete"}},
+		"expired":       {"label": "Plug", "expires_at": time.Now().Add(-time.Hour)},
+	} {
+		w := httptest.NewRecorder()
+		handler(w, withUser(postJSON("/api/keys/add", body)))
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, name)
+	}
+	mockRepo.AssertNotCalled(t, "AddAPIKey", mock.Anything, mock.Anything)
+}
+
+func TestDeleteAPIKeys_Success(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	mockRepo.On("DeleteAPIKey", mock.Anything, testHouseholdID, "1").Return(nil)
+
+	req := mux.SetURLVars(withUser(httptest.NewRequest(http.MethodDelete, "/api/keys/1", nil)), map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+	DeleteAPIKeys(mockRepo)(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"daya-listrik-api/internal/auth"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+type credentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	// InviteCode hanya dipakai saat registrasi untuk langsung bergabung ke
+	// rumah tangga yang mengundang.
+	InviteCode string `json:"invite_code,omitempty"`
+}
+
+type authResponse struct {
+	User *models.User `json:"user"`
+	*auth.TokenPair
+}
+
+// requestUserID mengambil id user yang diisi auth.Middleware. Request tanpa
+// user ditolak dengan 401.
+func requestUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, ok := auth.UserID(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.New(http.StatusUnauthorized, "unauthorized", nil))
+	}
+	return userID, ok
+}
+
+func decodeCredentials(r *http.Request) (credentials, error) {
+	var creds credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		log.Printf("Invalid JSON: %v", err)
+		return creds, problem.New(http.StatusBadRequest, "invalid_json", nil)
+	}
+	creds.Email = strings.TrimSpace(creds.Email)
+	creds.InviteCode = strings.TrimSpace(creds.InviteCode)
+	if !strings.Contains(creds.Email, "@") {
+		return creds, invalidField("email", validate.CodeInvalid, nil)
+	}
+	if creds.Password == "" {
+		return creds, invalidField("password", validate.CodeRequired, nil)
+	}
+	return creds, nil
+}
+
+func writeTokens(w http.ResponseWriter, r *http.Request, cfg auth.Config, user *models.User, status int) {
+	tokens, err := cfg.IssueTokens(user.ID)
+	if err != nil {
+		problem.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(authResponse{User: user, TokenPair: tokens})
+}
+
+// setupHousehold memasukkan user baru ke rumah tangga yang mengundang, atau
+// membuatkan rumah tangga pribadi bila tidak ada kode undangan.
+func setupHousehold(ctx context.Context, repo repository.HouseholdRepositoryInterface, defaults models.Household, userID int, inviteCode string) error {
+	if inviteCode != "" {
+		_, err := repo.RedeemInvitation(ctx, inviteCode, userID)
+		return err
+	}
+	household := models.Household{}
+	household.ApplyDefaults(defaults)
+	return repo.AddHousehold(ctx, userID, &household)
+}
+
+// Register membuat akun baru. Tanpa invite_code, user mendapat rumah tangga
+// pribadi dengan pengaturan dari defaults.
+func Register(repo repository.UserRepositoryInterface, households repository.HouseholdRepositoryInterface, defaults models.Household, cfg auth.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		creds, err := decodeCredentials(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		// Kode undangan dicek sebelum akun dibuat, agar kode yang salah
+		// tidak meninggalkan akun tanpa rumah tangga.
+		if creds.InviteCode != "" {
+			if _, err := households.GetByCodeInvitation(r.Context(), creds.InviteCode); err != nil {
+				problem.Error(w, r, err)
+				return
+			}
+		}
+
+		if len(creds.Password) < auth.MinPasswordLength {
+			problem.Error(w, r, invalidField("password", validate.CodeTooShort, i18n.Params{"min": auth.MinPasswordLength}))
+			return
+		}
+		hash, err := auth.HashPassword(creds.Password)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		user := &models.User{Email: creds.Email, PasswordHash: hash}
+		if err := repo.AddUser(r.Context(), user); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		// Akun sudah tersimpan; bila gagal di sini user masih bisa membuat
+		// atau bergabung ke rumah tangga lewat /api/households.
+		if err := setupHousehold(r.Context(), households, defaults, user.ID, creds.InviteCode); err != nil {
+			log.Printf("Household setup for user %d failed: %v", user.ID, err)
+		}
+
+		writeTokens(w, r, cfg, user, http.StatusCreated)
+	}
+}
+
+func Login(repo repository.UserRepositoryInterface, cfg auth.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		creds, err := decodeCredentials(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		user, err := repo.GetByEmailUser(r.Context(), creds.Email)
+		if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+			problem.Error(w, r, err)
+			return
+		}
+
+		var hash string
+		if user != nil {
+			hash = user.PasswordHash
+		}
+		if !auth.CheckPassword(hash, creds.Password) {
+			problem.Write(w, r, problem.New(http.StatusUnauthorized, "invalid_credentials", nil))
+			return
+		}
+
+		writeTokens(w, r, cfg, user, http.StatusOK)
+	}
+}
+
+func RefreshToken(repo repository.UserRepositoryInterface, cfg auth.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+		if body.RefreshToken == "" {
+			problem.Error(w, r, invalidField("refresh_token", validate.CodeRequired, nil))
+			return
+		}
+
+		userID, err := cfg.ParseToken(body.RefreshToken, auth.TokenRefresh)
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusUnauthorized, "invalid_token", nil))
+			return
+		}
+
+		// User yang sudah dihapus tidak boleh memperpanjang sesinya.
+		user, err := repo.GetByIdUser(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				problem.Write(w, r, problem.New(http.StatusUnauthorized, "invalid_token", nil))
+				return
+			}
+			problem.Error(w, r, err)
+			return
+		}
+
+		writeTokens(w, r, cfg, user, http.StatusOK)
+	}
+}
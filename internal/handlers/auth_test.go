@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"bytes"
+	"daya-listrik-api/internal/auth"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/repository/mocks"
+	"daya-listrik-api/internal/tenant"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testUserID      = 1
+	testHouseholdID = 10
+)
+
+var testAuth = auth.Config{Secret: []byte("test-secret"), AccessTTL: time.Minute, RefreshTTL: time.Hour}
+
+var testHousehold = models.Household{
+	ID:          testHouseholdID,
+	Name:        "Rumah Budi",
+	TariffClass: models.TariffR1_1300VA,
+	Timezone:    models.DefaultTimezone,
+	BillingDay:  1,
+}
+
+// withUser menandai request seolah sudah melewati auth.Middleware dan
+// tenant.Middleware, dengan testHousehold sebagai rumah tangga aktif.
+func withUser(req *http.Request) *http.Request {
+	return inHousehold(req, testHousehold)
+}
+
+func inHousehold(req *http.Request, household models.Household) *http.Request {
+	ctx := auth.WithUserID(req.Context(), testUserID)
+	return req.WithContext(tenant.WithMembership(ctx, models.Membership{Household: household, Role: models.RoleOwner}))
+}
+
+func postJSON(url string, body any) *http.Request {
+	payload, _ := json.Marshal(body)
+	return httptest.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+}
+
+var testHouseholdDefaults = models.Household{
+	Name:        models.DefaultHouseholdName,
+	TariffClass: models.TariffR1_900VA,
+	Timezone:    "Asia/Makassar",
+	BillingDay:  1,
+}
+
+func TestRegister_Success(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	households := new(mocks.MockHouseholdRepository)
+	handler := Register(mockRepo, households, testHouseholdDefaults, testAuth)
+
+	mockRepo.On("AddUser", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+		return u.Email == "budi@example.com" && auth.CheckPassword(u.PasswordHash, "rahasia123")
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.User).ID = 7
+	}).Return(nil)
+	households.On("AddHousehold", mock.Anything, 7, &testHouseholdDefaults).Return(nil)
+
+	w := httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/register", credentials{Email: " budi@example.com ", Password: "rahasia123"}))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp authResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, 7, resp.User.ID)
+	assert.Equal(t, "Bearer", resp.TokenType)
+
+	userID, err := testAuth.ParseToken(resp.AccessToken, auth.TokenAccess)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, userID)
+	assert.NotContains(t, w.Body.String(), "password")
+	mockRepo.AssertExpectations(t)
+	households.AssertExpectations(t)
+}
+
+func TestRegister_WithInvitation(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	households := new(mocks.MockHouseholdRepository)
+	handler := Register(mockRepo, households, testHouseholdDefaults, testAuth)
+
+	households.On("GetByCodeInvitation", mock.Anything, "EXPIRED").Return(nil, repository.ErrInvalidInvitation)
+	w := httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/register", credentials{Email: "siti@example.com", Password: "rahasia123", InviteCode: "EXPIRED"}))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "AddUser", mock.Anything, mock.Anything)
+
+	households.On("GetByCodeInvitation", mock.Anything, "A1B2C3D4E5F60718").Return(&models.HouseholdInvitation{HouseholdID: testHouseholdID, Role: models.RoleEditor}, nil)
+	mockRepo.On("AddUser", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.User).ID = 8
+	}).Return(nil)
+	households.On("RedeemInvitation", mock.Anything, "A1B2C3D4E5F60718", 8).Return(&models.Membership{Household: testHousehold, Role: models.RoleEditor}, nil)
+
+	w = httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/register", credentials{Email: "siti@example.com", Password: "rahasia123", InviteCode: " A1B2C3D4E5F60718 "}))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	households.AssertExpectations(t)
+	households.AssertNotCalled(t, "AddHousehold", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRegister_Errors(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	handler := Register(mockRepo, new(mocks.MockHouseholdRepository), testHouseholdDefaults, testAuth)
+
+	for _, creds := range []credentials{{Email: "budi", Password: "rahasia123"}, {Email: "budi@example.com", Password: "pendek"}} {
+		w := httptest.NewRecorder()
+		handler(w, postJSON("/api/auth/register", creds))
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	}
+	mockRepo.AssertNotCalled(t, "AddUser", mock.Anything, mock.Anything)
+
+	mockRepo.On("AddUser", mock.Anything, mock.Anything).Return(repository.ErrEmailTaken)
+	w := httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/register", credentials{Email: "budi@example.com", Password: "rahasia123"}))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestLogin(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	handler := Login(mockRepo, testAuth)
+
+	hash, _ := auth.HashPassword("rahasia123")
+	mockRepo.On("GetByEmailUser", mock.Anything, "budi@example.com").Return(&models.User{ID: 7, Email: "budi@example.com", PasswordHash: hash}, nil)
+	mockRepo.On("GetByEmailUser", mock.Anything, "siti@example.com").Return(nil, repository.ErrUserNotFound)
+
+	w := httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/login", credentials{Email: "budi@example.com", Password: "rahasia123"}))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/login", credentials{Email: "budi@example.com", Password: "salah12345"}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/login", credentials{Email: "siti@example.com", Password: "rahasia123"}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRefreshToken(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	handler := RefreshToken(mockRepo, testAuth)
+
+	mockRepo.On("GetByIdUser", mock.Anything, 7).Return(&models.User{ID: 7, Email: "budi@example.com"}, nil)
+	mockRepo.On("GetByIdUser", mock.Anything, 8).Return(nil, repository.ErrUserNotFound)
+
+	tokens, _ := testAuth.IssueTokens(7)
+	w := httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/refresh", map[string]string{"refresh_token": tokens.RefreshToken}))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Access token tidak bisa dipakai sebagai refresh token
+	w = httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/refresh", map[string]string{"refresh_token": tokens.AccessToken}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	deleted, _ := testAuth.IssueTokens(8)
+	w = httptest.NewRecorder()
+	handler(w, postJSON("/api/auth/refresh", map[string]string{"refresh_token": deleted.RefreshToken}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestInitializeRoutes_RequiresAuth(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	households := new(mocks.MockHouseholdRepository)
+	r := mux.NewRouter()
+	InitializeRoutes(r, Dependencies{Records: mockRepo, Households: households, Auth: testAuth})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/records", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockRepo.AssertNotCalled(t, "GetRecords", mock.Anything, mock.Anything, mock.Anything)
+
+	// Rumah tangga dipilih otomatis bila user hanya anggota satu rumah tangga
+	households.On("GetHouseholds", mock.Anything, 7).Return([]models.Membership{{Household: testHousehold, Role: models.RoleViewer}}, nil)
+	mockRepo.On("GetRecords", mock.Anything, testHouseholdID, mock.Anything).Return(&repository.RecordPage{Records: []models.EnergyRecord{}}, nil)
+	tokens, _ := testAuth.IssueTokens(7)
+	req := httptest.NewRequest(http.MethodGet, "/api/records", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+
+	// Rumah tangga orang lain ditolak
+	households.On("GetMembership", mock.Anything, 7, 11).Return(nil, repository.ErrNotMember)
+	req = httptest.NewRequest(http.MethodGet, "/api/records", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	req.Header.Set(tenant.Header, "11")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNumberOfCalls(t, "GetRecords", 1)
+}
+
+func TestInitializeRoutes_APIKey(t *testing.T) {
+	records := new(mocks.MockEnergyRecordRepository)
+	keys := new(mocks.MockAPIKeyRepository)
+	r := mux.NewRouter()
+	InitializeRoutes(r, Dependencies{Records: records, APIKeys: keys, Households: new(mocks.MockHouseholdRepository), Auth: testAuth})
+
+	key := &models.APIKey{ID: 1, HouseholdID: testHouseholdID, Scopes: []string{models.ScopeRecordsWrite}}
+	keys.On("AuthenticateAPIKey", mock.Anything, "dlk_plug").Return(key, &testHousehold, nil).Once()
+	records.On("AddRecord", mock.Anything, testHouseholdID, mock.AnythingOfType("*models.EnergyRecord")).Return(nil)
+
+	send := func(method, target string) int {
+		req := postJSON(target, models.EnergyRecord{Device: "AC", Usage: 350, Duration: 1})
+		req.Method = method
+		req.Header.Set("Authorization", "ApiKey dlk_plug")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/records/add"))
+
+	// API key hanya berlaku untuk route yang memasangnya
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/api/records"))
+
+	// Pencabutan langsung berlaku pada request berikutnya
+	keys.On("AuthenticateAPIKey", mock.Anything, "dlk_plug").Return(nil, nil, repository.ErrInvalidAPIKey)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodPost, "/api/records/add"))
+	records.AssertNumberOfCalls(t, "AddRecord", 1)
+}
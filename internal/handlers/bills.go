@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/billing"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// parseMonth membaca parameter month (format YYYY-MM) dan mengembalikan
+// periode tagihan rumah tangga yang dimulai pada bulan tersebut. Tanpa
+// parameter, periode yang sedang berjalan yang dipakai.
+func parseMonth(r *http.Request, household *models.Household) (time.Time, time.Time, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get("month"))
+	if raw == "" {
+		now := time.Now().In(household.Location())
+		from, to := household.BillingPeriod(now.Year(), now.Month())
+		if now.Before(from) {
+			from, to = household.BillingPeriod(now.Year(), now.Month()-1)
+		}
+		return from, to, nil
+	}
+
+	parsed, err := time.Parse("2006-01", raw)
+	if err != nil {
+		return time.Time{}, time.Time{}, problem.InvalidParameter("month", validate.CodeInvalid, nil)
+	}
+	from, to := household.BillingPeriod(parsed.Year(), parsed.Month())
+	return from, to, nil
+}
+
+// householdBilling memakai golongan tarif dan daya rumah tangga aktif; tarif
+// PPJ tetap dari konfigurasi server.
+func householdBilling(cfg billing.Config, household *models.Household) billing.Config {
+	cfg.TariffClass = household.TariffClass
+	cfg.ContractedVA = household.ContractedVA
+	return cfg
+}
+
+func EstimateBill(records repository.EnergyRecordRepositoryInterface, tariffs repository.TariffRepositoryInterface, defaults billing.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+		cfg := householdBilling(defaults, household)
+
+		from, to, err := parseMonth(r, household)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		summary, err := records.SummarizeRecords(r.Context(), household.ID, from, to)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		// Rekening minimum memakai tarif yang berlaku di akhir periode.
+		tariff, err := tariffs.GetEffectiveTariff(r.Context(), cfg.TariffClass, to.Add(-time.Nanosecond))
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		estimate := billing.EstimateBill(cfg, tariff, summary)
+		for i, item := range estimate.Items {
+			estimate.Items[i].Description = i18n.Text(lang, "bill_"+item.Code, item.Params)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(estimate)
+	}
+}
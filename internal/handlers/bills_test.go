@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/billing"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEstimateBill_Success(t *testing.T) {
+	recordRepo := new(mocks.MockEnergyRecordRepository)
+	tariffRepo := new(mocks.MockTariffRepository)
+	handler := EstimateBill(recordRepo, tariffRepo, billing.Config{TariffClass: models.TariffR1_2200VA, PPJRate: 0.10})
+
+	// Golongan tarif dan tanggal tagihan diambil dari rumah tangga aktif
+	household := testHousehold
+	household.TariffClass = models.TariffR1_900VA
+	household.BillingDay = 15
+	from := time.Date(2026, 10, 15, 0, 0, 0, 0, household.Location())
+	to := from.AddDate(0, 1, 0)
+	recordRepo.On("SummarizeRecords", mock.Anything, testHouseholdID, from, to).
+		Return(&models.UsageSummary{From: from, To: to, RecordCount: 12, EnergyKWh: 100, CostIDR: 60500}, nil)
+	tariffRepo.On("GetEffectiveTariff", mock.Anything, models.TariffR1_900VA, mock.AnythingOfType("time.Time")).
+		Return(&models.Tariff{Class: models.TariffR1_900VA, MinVA: 900, PricePerKWh: 605}, nil)
+
+	req := inHousehold(httptest.NewRequest(http.MethodGet, "/api/bills/estimate?month=2026-10", nil), household)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp billing.Estimate
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, "2026-10", resp.Month)
+	assert.Equal(t, 66550.0, resp.Total)
+	assert.Equal(t, "id", w.Header().Get("Content-Language"))
+	assert.Equal(t, "Biaya pemakaian energi", resp.Items[0].Description)
+	assert.Equal(t, "Pajak Penerangan Jalan", resp.Items[1].Description)
+
+	// Deskripsi item mengikuti Accept-Language
+	req = inHousehold(httptest.NewRequest(http.MethodGet, "/api/bills/estimate?month=2026-10", nil), household)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	w = httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	resp = billing.Estimate{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, "Energy charge", resp.Items[0].Description)
+	assert.Equal(t, "Street lighting tax (PPJ)", resp.Items[1].Description)
+	recordRepo.AssertExpectations(t)
+	tariffRepo.AssertExpectations(t)
+}
+
+func TestEstimateBill_MinimumCharge(t *testing.T) {
+	recordRepo := new(mocks.MockEnergyRecordRepository)
+	tariffRepo := new(mocks.MockTariffRepository)
+	handler := EstimateBill(recordRepo, tariffRepo, billing.Config{})
+
+	recordRepo.On("SummarizeRecords", mock.Anything, testHouseholdID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return(&models.UsageSummary{RecordCount: 1, EnergyKWh: 5, CostIDR: 7224}, nil)
+	tariffRepo.On("GetEffectiveTariff", mock.Anything, testHousehold.TariffClass, mock.AnythingOfType("time.Time")).
+		Return(&models.Tariff{Class: testHousehold.TariffClass, MinVA: 1300, PricePerKWh: 1444.70}, nil)
+
+	for lang, description := range map[string]string{
+		"id": "Rekening minimum (40 jam nyala)",
+		"en": "Minimum charge (40 hours of use)",
+	} {
+		req := withUser(httptest.NewRequest(http.MethodGet, "/api/bills/estimate?month=2026-10", nil))
+		req.Header.Set("Accept-Language", lang)
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp billing.Estimate
+		json.NewDecoder(w.Body).Decode(&resp)
+		assert.Equal(t, billing.ItemMinimumCharge, resp.Items[0].Code)
+		assert.Equal(t, description, resp.Items[0].Description)
+	}
+}
+
+func TestEstimateBill_InvalidMonth(t *testing.T) {
+	handler := EstimateBill(new(mocks.MockEnergyRecordRepository), new(mocks.MockTariffRepository), billing.Config{})
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/bills/estimate?month=10-2026", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
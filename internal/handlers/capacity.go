@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"daya-listrik-api/internal/capacity"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// parseTimeParamIn menerima waktu RFC3339 atau tanggal YYYY-MM-DD. Tanggal
+// dibaca sebagai tengah malam di zona waktu loc, biasanya zona waktu rumah
+// tangga aktif.
+func parseTimeParamIn(r *http.Request, key string, fallback time.Time, loc *time.Location) (time.Time, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", raw, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, problem.InvalidParameter(key, validate.CodeInvalid, nil)
+}
+
+type overloadResponse struct {
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	ContractedVA int               `json:"contracted_va"`
+	PowerFactor  float64           `json:"power_factor"`
+	LimitWatts   float64           `json:"limit_watts"`
+	Overloads    []capacity.Window `json:"overloads"`
+}
+
+// householdCapacity memakai daya tersambung rumah tangga aktif; faktor daya
+// tetap dari konfigurasi server.
+func householdCapacity(cfg capacity.Config, household *models.Household) capacity.Config {
+	cfg.ContractedVA = household.EffectiveVA()
+	return cfg
+}
+
+func GetOverloads(repo repository.EnergyRecordRepositoryInterface, defaults capacity.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+		cfg := householdCapacity(defaults, household)
+
+		now, loc := time.Now(), household.Location()
+		from, err := parseTimeParamIn(r, "from", now.AddDate(0, 0, -7), loc)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+		to, err := parseTimeParamIn(r, "to", now, loc)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+		if !to.After(from) {
+			problem.Write(w, r, problem.InvalidParameter("to", validate.CodeOutOfRange, nil))
+			return
+		}
+
+		records, err := repo.GetActiveRecords(r.Context(), household.ID, from, to)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(overloadResponse{
+			From:         from,
+			To:           to,
+			ContractedVA: cfg.ContractedVA,
+			PowerFactor:  cfg.PowerFactor,
+			LimitWatts:   cfg.LimitWatts(),
+			Overloads:    capacity.Overloads(records, cfg.LimitWatts()),
+		})
+	}
+}
+
+// capacityWarnings mengecek apakah record yang akan disimpan membuat beban
+// serentak melewati batas daya tersambung, dengan pesan dalam bahasa lang.
+// excludeID adalah id record yang sedang diubah: versi lamanya di database
+// tidak ikut dihitung. 0 berarti tidak ada, yaitu record baru. Kegagalan
+// pengecekan hanya dicatat di log karena peringatan ini tidak boleh
+// menggagalkan penyimpanan record.
+func capacityWarnings(ctx context.Context, lang string, repo repository.EnergyRecordRepositoryInterface, cfg capacity.Config, household *models.Household, record models.EnergyRecord, excludeID int) []string {
+	cfg = householdCapacity(cfg, household)
+	start, end, ok := capacity.Interval(record)
+	if !ok || cfg.LimitWatts() <= 0 {
+		return nil
+	}
+
+	active, err := repo.GetActiveRecords(ctx, household.ID, start, end)
+	if err != nil {
+		log.Printf("Capacity check skipped: %v", err)
+		return nil
+	}
+	existing := make([]models.EnergyRecord, 0, len(active)+1)
+	for _, other := range active {
+		if excludeID == 0 || other.ID != excludeID {
+			existing = append(existing, other)
+		}
+	}
+
+	// Record dikenali dari id-nya di jendela beban, jadi id dari body request
+	// tidak dipakai.
+	record.ID = excludeID
+	var warnings []string
+	for _, window := range capacity.Overloads(append(existing, record), cfg.LimitWatts()) {
+		for _, id := range window.RecordIDs {
+			if id == record.ID {
+				warnings = append(warnings, i18n.Text(lang, "capacity_overload", i18n.Params{
+					"peak":         math.Round(window.PeakWatts),
+					"start":        window.Start.Format(time.RFC3339),
+					"end":          window.End.Format(time.RFC3339),
+					"limit":        math.Round(window.LimitWatts),
+					"va":           cfg.ContractedVA,
+					"power_factor": cfg.PowerFactor,
+				}))
+				break
+			}
+		}
+	}
+	return warnings
+}
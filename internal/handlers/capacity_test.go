@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"daya-listrik-api/internal/capacity"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetOverloads_Success(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := GetOverloads(mockRepo, capacity.Config{ContractedVA: 2200, PowerFactor: 1})
+
+	// Batas daya diambil dari rumah tangga aktif, bukan dari konfigurasi server
+	household := testHousehold
+	household.ContractedVA = 900
+
+	from := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	start := from.Add(19 * time.Hour)
+	mockRepo.On("GetActiveRecords", mock.Anything, testHouseholdID, from, to).Return([]models.EnergyRecord{
+		{ID: 1, Usage: 600, Duration: 1, StartedAt: &start},
+		{ID: 2, Usage: 400, Duration: 1, StartedAt: &start},
+	}, nil)
+
+	req := inHousehold(httptest.NewRequest(http.MethodGet, "/api/capacity/overloads?from=2026-10-17T00:00:00Z&to=2026-10-18T00:00:00Z", nil), household)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp overloadResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, 900.0, resp.LimitWatts)
+	assert.Len(t, resp.Overloads, 1)
+	assert.Equal(t, 1000.0, resp.Overloads[0].PeakWatts)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetOverloads_HouseholdTimezone(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := GetOverloads(mockRepo, capacity.Config{PowerFactor: 1})
+
+	household := testHousehold
+	household.Timezone = "Asia/Makassar"
+	from := time.Date(2026, 10, 16, 16, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	mockRepo.On("GetActiveRecords", mock.Anything, testHouseholdID,
+		mock.MatchedBy(from.Equal), mock.MatchedBy(to.Equal)).Return([]models.EnergyRecord{}, nil)
+
+	req := inHousehold(httptest.NewRequest(http.MethodGet, "/api/capacity/overloads?from=2026-10-17&to=2026-10-18", nil), household)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetOverloads_InvalidRange(t *testing.T) {
+	handler := GetOverloads(new(mocks.MockEnergyRecordRepository), capacity.Config{ContractedVA: 900})
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/capacity/overloads?from=2026-10-18&to=2026-10-17", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAddRecord_CapacityWarning(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := AddRecord(mockRepo, capacity.Config{ContractedVA: 1300, PowerFactor: 0.85}, DefaultRecordRules())
+
+	start := time.Now().UTC().Add(-24 * time.Hour).Truncate(time.Minute)
+	existingStart := start.Add(-30 * time.Minute)
+	mockRepo.On("GetActiveRecords", mock.Anything, testHouseholdID, start, start.Add(time.Hour)).Return([]models.EnergyRecord{
+		{ID: 5, Usage: 800, Duration: 2, StartedAt: &existingStart},
+	}, nil)
+	mockRepo.On("AddRecord", mock.Anything, testHouseholdID, mock.AnythingOfType("*models.EnergyRecord")).Return(nil)
+
+	body, _ := json.Marshal(models.EnergyRecord{Device: "Setrika", Usage: 400, Duration: 1, StartedAt: &start})
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader(body)))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp recordResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Len(t, resp.Warnings, 1)
+	assert.Contains(t, resp.Warnings[0], "Beban serentak mencapai 1200 W")
+	assert.Contains(t, resp.Warnings[0], "batas 1105 W (1300 VA x 0.85)")
+	mockRepo.AssertExpectations(t)
+
+	req = withUser(httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader(body)))
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	w = httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	resp = recordResponse{}
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Len(t, resp.Warnings, 1)
+	assert.Contains(t, resp.Warnings[0], "concurrent load reaches 1200 W")
+	assert.Contains(t, resp.Warnings[0], "above the 1105 W limit (1300 VA x 0.85)")
+}
+
+func TestCapacityWarnings_ExcludeID(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	cfg := capacity.Config{ContractedVA: 1300, PowerFactor: 0.85}
+
+	start := time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)
+	mockRepo.On("GetActiveRecords", mock.Anything, testHouseholdID, start, start.Add(time.Hour)).Return([]models.EnergyRecord{
+		{ID: 5, Usage: 800, Duration: 1, StartedAt: &start},
+	}, nil)
+	record := models.EnergyRecord{ID: 5, Usage: 900, Duration: 1, StartedAt: &start}
+
+	// Versi lama record 5 tidak dihitung bersama versi barunya
+	assert.Empty(t, capacityWarnings(context.Background(), "id", mockRepo, cfg, &testHousehold, record, 5))
+
+	// Tanpa excludeID, record 5 di database adalah record lain yang
+	// bersamaan dengan record baru, apa pun id di body request
+	assert.Len(t, capacityWarnings(context.Background(), "id", mockRepo, cfg, &testHousehold, record, 0), 1)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func validateDevice(device *models.Device) error {
+	var v validate.Validator
+	device.Name = strings.TrimSpace(device.Name)
+	device.Category = strings.TrimSpace(device.Category)
+	device.Room = strings.TrimSpace(device.Room)
+	v.Required("name", device.Name)
+	v.MaxLength("name", device.Name, models.MaxNameLength)
+	v.MaxLength("category", device.Category, 50)
+	v.MaxLength("room", device.Room, models.MaxNameLength)
+
+	ceiling := models.WattageCeiling(device.Category)
+	v.Check(device.RatedWattage >= 0 && device.RatedWattage <= ceiling, "rated_wattage", validate.CodeOutOfRange, i18n.Params{"max": ceiling})
+	return v.Err()
+}
+
+func AddDevice(repo repository.DeviceRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		var device models.Device
+		if err := json.NewDecoder(r.Body).Decode(&device); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+
+		if err := validateDevice(&device); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.AddDevice(r.Context(), household.ID, &device); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(device)
+	}
+}
+
+func GetDevices(repo repository.DeviceRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		devices, err := repo.GetDevices(r.Context(), household.ID)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(devices)
+	}
+}
+
+func DeleteDevices(repo repository.DeviceRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		id, err := validateParamId(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.DeleteDevice(r.Context(), household.ID, id); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func UpdateDevices(repo repository.DeviceRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		id, err := validateParamId(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		idInt, _ := strconv.Atoi(id)
+
+		var device models.Device
+		if err := json.NewDecoder(r.Body).Decode(&device); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+		device.ID = idInt
+
+		if err := validateDevice(&device); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.UpdateDevice(r.Context(), household.ID, &device); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(device)
+	}
+}
+
+func GetByIdDevices(repo repository.DeviceRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		id, err := validateParamId(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		device, err := repo.GetByIdDevice(r.Context(), household.ID, id)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(device)
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddDevice_Success(t *testing.T) {
+	mockRepo := new(mocks.MockDeviceRepository)
+	handler := AddDevice(mockRepo)
+
+	body, _ := json.Marshal(models.Device{Name: " AC ", RatedWattage: 350})
+
+	mockRepo.On("AddDevice", mock.Anything, testHouseholdID, mock.MatchedBy(func(d *models.Device) bool {
+		return d.Name == "AC"
+	})).Return(nil)
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/devices/add", bytes.NewReader(body)))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddDevice_MissingName(t *testing.T) {
+	mockRepo := new(mocks.MockDeviceRepository)
+	handler := AddDevice(mockRepo)
+
+	body, _ := json.Marshal(models.Device{RatedWattage: 350})
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/devices/add", bytes.NewReader(body)))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "AddDevice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddDevice_WattageCeiling(t *testing.T) {
+	mockRepo := new(mocks.MockDeviceRepository)
+	handler := AddDevice(mockRepo)
+
+	mockRepo.On("AddDevice", mock.Anything, testHouseholdID, mock.AnythingOfType("*models.Device")).Return(nil).Once()
+
+	for category, status := range map[string]int{"lighting": http.StatusUnprocessableEntity, "Cooling": http.StatusCreated} {
+		body, _ := json.Marshal(models.Device{Name: "AC Split", RatedWattage: 1500, Category: category})
+
+		w := httptest.NewRecorder()
+		handler(w, withUser(httptest.NewRequest(http.MethodPost, "/api/devices/add", bytes.NewReader(body))))
+		assert.Equal(t, status, w.Code, category)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDevices_Success(t *testing.T) {
+	mockRepo := new(mocks.MockDeviceRepository)
+	handler := GetDevices(mockRepo)
+
+	devices := []models.Device{{ID: 1, Name: "Lamp", RatedWattage: 20}}
+	mockRepo.On("GetDevices", mock.Anything, testHouseholdID).Return(devices, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/devices", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []models.Device
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, devices, resp)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetByIdDevices_Success(t *testing.T) {
+	mockRepo := new(mocks.MockDeviceRepository)
+	handler := GetByIdDevices(mockRepo)
+
+	device := &models.Device{ID: 1, Name: "TV", RatedWattage: 90}
+	mockRepo.On("GetByIdDevice", mock.Anything, testHouseholdID, "1").Return(device, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/devices/1", nil))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.Device
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, *device, resp)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateDevices_Success(t *testing.T) {
+	mockRepo := new(mocks.MockDeviceRepository)
+	handler := UpdateDevices(mockRepo)
+
+	device := models.Device{ID: 1, Name: "Fan", RatedWattage: 60}
+	body, _ := json.Marshal(device)
+
+	mockRepo.On("UpdateDevice", mock.Anything, testHouseholdID, mock.AnythingOfType("*models.Device")).Return(nil)
+
+	req := withUser(httptest.NewRequest(http.MethodPut, "/api/devices/1", bytes.NewReader(body)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.Device
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, device, resp)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteDevices_Success(t *testing.T) {
+	mockRepo := new(mocks.MockDeviceRepository)
+	handler := DeleteDevices(mockRepo)
+
+	mockRepo.On("DeleteDevice", mock.Anything, testHouseholdID, "1").Return(nil)
+
+	req := withUser(httptest.NewRequest(http.MethodDelete, "/api/devices/1", nil))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRepo.AssertExpectations(t)
+}
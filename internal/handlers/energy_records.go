@@ -1,99 +1,442 @@
 package handlers
 
 import (
+	"daya-listrik-api/internal/apikey"
+	"daya-listrik-api/internal/auth"
+	"daya-listrik-api/internal/authz"
+	"daya-listrik-api/internal/billing"
+	"daya-listrik-api/internal/capacity"
+	"daya-listrik-api/internal/i18n"
 	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
 	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/tenant"
+	"daya-listrik-api/internal/validate"
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
-func InitializeRoutes(r *mux.Router, repo repository.EnergyRecordRepositoryInterface) {
-	const routeApiRecordsAdd = "/api/records/add"
+// Dependencies berisi repository dan konfigurasi yang dibutuhkan oleh semua handler.
+type Dependencies struct {
+	Records repository.EnergyRecordRepositoryInterface
+	Devices repository.DeviceRepositoryInterface
+	Tariffs repository.TariffRepositoryInterface
+	Tokens  repository.TokenPurchaseRepositoryInterface
+	Meters  repository.MeterReadingRepositoryInterface
+	// Billing dan Capacity hanya menyumbang PPJ dan faktor daya; golongan
+	// tarif dan daya tersambung diambil dari rumah tangga aktif.
+	Billing     billing.Config
+	Capacity    capacity.Config
+	RecordRules RecordRules
+	Users       repository.UserRepositoryInterface
+	Auth        auth.Config
+	Households  repository.HouseholdRepositoryInterface
+	APIKeys     repository.APIKeyRepositoryInterface
+	// HouseholdDefaults mengisi field yang kosong saat rumah tangga dibuat.
+	HouseholdDefaults models.Household
+}
+
+// InitializeRoutes mendaftarkan semua route. Selain /api/auth, setiap route
+// membutuhkan access token, dan data rumah tangga hanya bisa diakses anggotanya.
+// Izin tiap route dipasang dengan authz.Authorizer.Require; POST
+// /api/records/add juga menerima API key.
+func InitializeRoutes(r *mux.Router, deps Dependencies) {
+	authorizer := &authz.Authorizer{Users: deps.Users}
+	require := authorizer.Require
+
+	r.HandleFunc("/api/auth/register", Register(deps.Users, deps.Households, deps.HouseholdDefaults, deps.Auth)).Methods("POST")
+	r.HandleFunc("/api/auth/login", Login(deps.Users, deps.Auth)).Methods("POST")
+	r.HandleFunc("/api/auth/refresh", RefreshToken(deps.Users, deps.Auth)).Methods("POST")
+
+	withUser := func(next http.Handler) http.Handler {
+		return auth.Middleware(deps.Auth)(tenant.Middleware(deps.Households)(next))
+	}
+	r.Handle("/api/records/add", apikey.Middleware(deps.APIKeys, models.ScopeRecordsWrite, withUser)(
+		require(authz.RecordsWrite, AddRecord(deps.Records, deps.Capacity, deps.RecordRules)))).Methods("POST")
+
+	protected := r.NewRoute().Subrouter()
+	protected.Use(auth.Middleware(deps.Auth))
+	registerHouseholdRoutes(protected, deps, authorizer)
+
+	const routeApiTariffsAdd = "/api/tariffs/add"
+	const routeApiTariffs = "/api/tariffs"
+	const routeApiTariffsId = "/api/tariffs/{id}"
+
+	protected.HandleFunc(routeApiTariffs, GetTariffs(deps.Tariffs)).Methods("GET")
+	protected.Handle(routeApiTariffsAdd, require(authz.TariffsAdmin, AddTariff(deps.Tariffs))).Methods("POST")
+	protected.HandleFunc(routeApiTariffsId, GetByIdTariffs(deps.Tariffs)).Methods("GET")
+
+	scoped := protected.NewRoute().Subrouter()
+	scoped.Use(tenant.Middleware(deps.Households))
+	registerProtectedRoutes(scoped, deps, require)
+}
+
+// registerHouseholdRoutes mendaftarkan pengelolaan rumah tangga. Route dengan
+// {id} memakai rumah tangga dari path, bukan dari header X-Household-ID, dan
+// izinnya dicek terhadap peran user di rumah tangga tersebut.
+func registerHouseholdRoutes(r *mux.Router, deps Dependencies, authorizer *authz.Authorizer) {
+	const routeApiHouseholdsAdd = "/api/households/add"
+	const routeApiHouseholds = "/api/households"
+	const routeApiHouseholdsJoin = "/api/households/join"
+	const routeApiHouseholdsId = "/api/households/{id}"
+	const routeApiHouseholdsInvitations = "/api/households/{id}/invitations"
+	const routeApiHouseholdsMembers = "/api/households/{id}/members"
+	const routeApiHouseholdsMembersId = "/api/households/{id}/members/{user_id}"
+
+	r.HandleFunc(routeApiHouseholds, GetHouseholds(deps.Households)).Methods("GET")
+	r.HandleFunc(routeApiHouseholdsAdd, AddHousehold(deps.Households, deps.HouseholdDefaults)).Methods("POST")
+	r.HandleFunc(routeApiHouseholdsJoin, JoinHousehold(deps.Households)).Methods("POST")
+
+	require := authorizer.Require
+	household := r.NewRoute().Subrouter()
+	household.Use(tenant.PathMiddleware(deps.Households, "id"))
+	household.Handle(routeApiHouseholdsId, require(authz.HouseholdAdmin, DeleteHouseholds(deps.Households))).Methods("DELETE")
+	household.Handle(routeApiHouseholdsId, require(authz.HouseholdAdmin, UpdateHouseholds(deps.Households))).Methods("PUT")
+	household.Handle(routeApiHouseholdsId, require(authz.HouseholdRead, GetByIdHouseholds())).Methods("GET")
+	household.Handle(routeApiHouseholdsInvitations, require(authz.HouseholdAdmin, AddInvitation(deps.Households))).Methods("POST")
+	household.Handle(routeApiHouseholdsMembers, require(authz.HouseholdRead, GetHouseholdMembers(deps.Households))).Methods("GET")
+	household.Handle(routeApiHouseholdsMembersId, require(authz.HouseholdAdmin, UpdateHouseholdMembers(deps.Households))).Methods("PUT")
+	household.Handle(routeApiHouseholdsMembersId, authorizer.RequireOrSelf(authz.HouseholdAdmin, "user_id", DeleteHouseholdMembers(deps.Households))).Methods("DELETE")
+}
+
+// registerProtectedRoutes mendaftarkan route yang datanya dibatasi pada rumah
+// tangga aktif.
+func registerProtectedRoutes(r *mux.Router, deps Dependencies, require func(authz.Permission, http.Handler) *authz.Guard) {
 	const routeApiRecord = "/api/records"
 	const routeApiRecordsId = "/api/records/{id}"
 
-	r.HandleFunc(routeApiRecord, GetRecords(repo)).Methods("GET")
-	r.HandleFunc(routeApiRecordsAdd, AddRecord(repo)).Methods("POST")
-	r.HandleFunc(routeApiRecordsId, DeleteRecords(repo)).Methods("DELETE")
-	r.HandleFunc(routeApiRecordsId, UpdateRecords(repo)).Methods("PUT")
-	r.HandleFunc(routeApiRecordsId, GetByIdRecords(repo)).Methods("GET")
+	r.Handle(routeApiRecord, require(authz.RecordsRead, GetRecords(deps.Records))).Methods("GET")
+	r.Handle(routeApiRecordsId, require(authz.RecordsWrite, DeleteRecords(deps.Records))).Methods("DELETE")
+	r.Handle(routeApiRecordsId, require(authz.RecordsWrite, UpdateRecords(deps.Records, deps.RecordRules))).Methods("PUT")
+	r.Handle(routeApiRecordsId, require(authz.RecordsRead, GetByIdRecords(deps.Records))).Methods("GET")
+
+	const routeApiDevicesAdd = "/api/devices/add"
+	const routeApiDevices = "/api/devices"
+	const routeApiDevicesId = "/api/devices/{id}"
+
+	r.Handle(routeApiDevices, require(authz.RecordsRead, GetDevices(deps.Devices))).Methods("GET")
+	r.Handle(routeApiDevicesAdd, require(authz.RecordsWrite, AddDevice(deps.Devices))).Methods("POST")
+	r.Handle(routeApiDevicesId, require(authz.RecordsWrite, DeleteDevices(deps.Devices))).Methods("DELETE")
+	r.Handle(routeApiDevicesId, require(authz.RecordsWrite, UpdateDevices(deps.Devices))).Methods("PUT")
+	r.Handle(routeApiDevicesId, require(authz.RecordsRead, GetByIdDevices(deps.Devices))).Methods("GET")
+
+	r.Handle("/api/bills/estimate", require(authz.RecordsRead, EstimateBill(deps.Records, deps.Tariffs, deps.Billing))).Methods("GET")
+	r.Handle("/api/capacity/overloads", require(authz.RecordsRead, GetOverloads(deps.Records, deps.Capacity))).Methods("GET")
+	r.Handle("/api/stats/usage", require(authz.RecordsRead, GetUsageStats(deps.Records))).Methods("GET")
+
+	const routeApiTokensAdd = "/api/tokens/add"
+	const routeApiTokens = "/api/tokens"
+	const routeApiTokensBalance = "/api/tokens/balance"
+	const routeApiTokensId = "/api/tokens/{id}"
+
+	r.Handle(routeApiTokens, require(authz.RecordsRead, GetTokenPurchases(deps.Tokens))).Methods("GET")
+	r.Handle(routeApiTokensAdd, require(authz.RecordsWrite, AddTokenPurchase(deps.Tokens))).Methods("POST")
+	r.Handle(routeApiTokensBalance, require(authz.RecordsRead, GetTokenBalance(deps.Tokens, deps.Records))).Methods("GET")
+	r.Handle(routeApiTokensId, require(authz.RecordsWrite, DeleteTokenPurchases(deps.Tokens))).Methods("DELETE")
+	r.Handle(routeApiTokensId, require(authz.RecordsRead, GetByIdTokenPurchases(deps.Tokens))).Methods("GET")
+
+	const routeApiMeterReadingsAdd = "/api/meter-readings/add"
+	const routeApiMeterReadings = "/api/meter-readings"
+	const routeApiMeterReadingsReconcile = "/api/meter-readings/reconcile"
+	const routeApiMeterReadingsId = "/api/meter-readings/{id}"
+
+	r.Handle(routeApiMeterReadings, require(authz.RecordsRead, GetMeterReadings(deps.Meters))).Methods("GET")
+	r.Handle(routeApiMeterReadingsAdd, require(authz.RecordsWrite, AddMeterReading(deps.Meters))).Methods("POST")
+	r.Handle(routeApiMeterReadingsReconcile, require(authz.RecordsRead, ReconcileMeterReadings(deps.Meters, deps.Records))).Methods("GET")
+	r.Handle(routeApiMeterReadingsId, require(authz.RecordsWrite, DeleteMeterReadings(deps.Meters))).Methods("DELETE")
+	r.Handle(routeApiMeterReadingsId, require(authz.RecordsRead, GetByIdMeterReadings(deps.Meters))).Methods("GET")
+
+	const routeApiKeysAdd = "/api/keys/add"
+	const routeApiKeys = "/api/keys"
+	const routeApiKeysId = "/api/keys/{id}"
+
+	r.Handle(routeApiKeys, require(authz.HouseholdAdmin, GetAPIKeys(deps.APIKeys))).Methods("GET")
+	r.Handle(routeApiKeysAdd, require(authz.HouseholdAdmin, AddAPIKey(deps.APIKeys))).Methods("POST")
+	r.Handle(routeApiKeysId, require(authz.HouseholdAdmin, DeleteAPIKeys(deps.APIKeys))).Methods("DELETE")
 }
 
-func validateEnergyRecord(record *models.EnergyRecord) error {
-	if record.Usage <= 0 {
-		return fmt.Errorf("usage is required and must be greater than 0")
+// RecordRules berisi aturan validasi record yang bisa dikonfigurasi.
+type RecordRules struct {
+	// FutureTolerance adalah batas toleransi date/started_at di masa depan,
+	// untuk mengakomodasi jam perangkat klien yang sedikit maju.
+	FutureTolerance time.Duration
+	// MaxDuration memperketat models.MaxRecordDuration; 0 atau nilai yang
+	// lebih besar memakai batas tersebut.
+	MaxDuration float64
+}
+
+func DefaultRecordRules() RecordRules {
+	return RecordRules{FutureTolerance: 5 * time.Minute, MaxDuration: 24}
+}
+
+// MaxRecordBodyBytes adalah ukuran maksimum body request satu record.
+const MaxRecordBodyBytes = 16 << 10
+
+// validateEnergyRecord mengecek semua field record sekaligus. Batas daya per
+// kategori perangkat dicek repository setelah perangkatnya diketahui.
+func validateEnergyRecord(record *models.EnergyRecord, rules RecordRules) error {
+	var v validate.Validator
+	v.Check(record.Usage > 0, "usage", validate.CodeRequired, nil)
+	v.Check(record.Usage <= models.MaxDeviceWattage, "usage", validate.CodeOutOfRange, i18n.Params{"max": models.MaxDeviceWattage})
+
+	device := strings.TrimSpace(record.Device)
+	v.Check(device != "" || record.DeviceID != nil, "device", validate.CodeRequired, nil)
+	v.MaxLength("device", device, models.MaxNameLength)
+
+	v.Check(record.Duration > 0, "duration", validate.CodeRequired, nil)
+	maxDuration := float64(models.MaxRecordDuration)
+	if rules.MaxDuration > 0 && rules.MaxDuration < maxDuration {
+		maxDuration = rules.MaxDuration
+	}
+	v.Check(record.Duration <= maxDuration, "duration", validate.CodeOutOfRange, i18n.Params{"max": maxDuration})
+
+	latest := time.Now().Add(rules.FutureTolerance)
+	v.Check(!record.Date.After(latest), "date", validate.CodeInFuture, nil)
+	if record.StartedAt != nil {
+		v.Check(!record.StartedAt.After(latest), "started_at", validate.CodeInFuture, nil)
+		// Record yang di-backfill dengan started_at saja dicatat pada
+		// tanggal mulai pemakaiannya.
+		if record.Date.IsZero() {
+			record.Date = *record.StartedAt
+		}
+	}
+	return v.Err()
+}
+
+// decodeStrict membaca body JSON ke dst seperti json.Decoder, tetapi menolak
+// body yang lebih besar dari maxBytes, field yang tidak dikenal, dan data
+// setelah objek JSON. Respons error sudah ditulis bila hasilnya false.
+func decodeStrict(w http.ResponseWriter, r *http.Request, dst any, maxBytes int64) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBytes))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(dst)
+	if err == nil {
+		if _, next := dec.Token(); next != io.EOF {
+			err = errors.New("unexpected data after JSON object")
+		}
+	}
+	if err == nil {
+		return true
 	}
-	if record.Device == "" {
-		return fmt.Errorf("device is required")
+
+	var tooLarge *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &tooLarge):
+		problem.Write(w, r, problem.New(http.StatusRequestEntityTooLarge, "body_too_large", i18n.Params{"max": maxBytes}))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field, _ := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		problem.Write(w, r, problem.New(http.StatusBadRequest, "unknown_field", nil,
+			validate.NewFieldError(field, validate.CodeUnknownField, nil)))
+	case errors.As(err, &typeErr):
+		problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_json", nil,
+			validate.NewFieldError(typeErr.Field, validate.CodeInvalidType, i18n.Params{"type": typeErr.Type.String()})))
+	default:
+		problem.InvalidJSON(w, r, err)
 	}
-	return nil
+	return false
 }
 
 func validateParamId(r *http.Request) (string, error) {
 	id := strings.TrimSpace(mux.Vars(r)["id"])
 
 	if _, err := strconv.Atoi(id); err != nil {
-		return "", fmt.Errorf("invalid param id")
+		return "", problem.InvalidParameter("id", validate.CodeInvalid, nil)
 	}
 
 	return id, nil
 }
 
-func AddRecord(repo repository.EnergyRecordRepositoryInterface) http.HandlerFunc {
+// invalidField membuat error validasi untuk satu field body request.
+func invalidField(field, code string, params i18n.Params) error {
+	return validate.Errors{validate.NewFieldError(field, code, params)}
+}
+
+// parseRecordQuery membaca filter, sort (mis. "energy_wh" atau "-energy_wh"
+// untuk urutan menurun) dan paginasi (limit, offset atau cursor) dari query string.
+// Tanggal from/to dibaca di zona waktu loc.
+func parseRecordQuery(r *http.Request, loc *time.Location) (repository.RecordQuery, error) {
+	q := repository.RecordQuery{Limit: repository.DefaultRecordLimit}
+	params := r.URL.Query()
+
+	for key, target := range map[string]**float64{
+		"min_usage":     &q.MinUsage,
+		"max_usage":     &q.MaxUsage,
+		"min_energy_wh": &q.MinEnergyWh,
+		"max_energy_wh": &q.MaxEnergyWh,
+	} {
+		raw := strings.TrimSpace(params.Get(key))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return q, problem.InvalidParameter(key, validate.CodeInvalid, nil)
+		}
+		*target = &value
+	}
+
+	for key, target := range map[string]**time.Time{"from": &q.From, "to": &q.To} {
+		value, err := parseTimeParamIn(r, key, time.Time{}, loc)
+		if err != nil {
+			return q, err
+		}
+		if !value.IsZero() {
+			*target = &value
+		}
+	}
+	if q.From != nil && q.To != nil && !q.To.After(*q.From) {
+		return q, problem.InvalidParameter("to", validate.CodeOutOfRange, nil)
+	}
+
+	q.Device = strings.TrimSpace(params.Get("device"))
+	if raw := strings.TrimSpace(params.Get("device_id")); raw != "" {
+		deviceID, err := strconv.Atoi(raw)
+		if err != nil {
+			return q, problem.InvalidParameter("device_id", validate.CodeInvalid, nil)
+		}
+		q.DeviceID = &deviceID
+	}
+
+	if sort := strings.TrimSpace(params.Get("sort")); sort != "" {
+		q.SortDesc = strings.HasPrefix(sort, "-")
+		q.SortBy = strings.TrimPrefix(sort, "-")
+		if !repository.ValidRecordSort(q.SortBy) {
+			return q, problem.InvalidParameter("sort", validate.CodeInvalid, i18n.Params{"value": q.SortBy})
+		}
+	}
+
+	if raw := strings.TrimSpace(params.Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > repository.MaxRecordLimit {
+			return q, problem.InvalidParameter("limit", validate.CodeOutOfRange, i18n.Params{"min": 1, "max": repository.MaxRecordLimit})
+		}
+		q.Limit = limit
+	}
+	if raw := strings.TrimSpace(params.Get("offset")); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return q, problem.InvalidParameter("offset", validate.CodeInvalid, nil)
+		}
+		q.Offset = offset
+	}
+	if cursor := strings.TrimSpace(params.Get("cursor")); cursor != "" {
+		if q.Offset > 0 {
+			return q, problem.InvalidParameter("cursor", validate.CodeConflict, nil)
+		}
+		afterID, err := repository.DecodeRecordCursor(cursor)
+		if err != nil {
+			return q, problem.InvalidParameter("cursor", validate.CodeInvalid, nil)
+		}
+		q.AfterID = &afterID
+	}
+
+	return q, nil
+}
+
+// recordListResponse adalah envelope GET /api/records. next_cursor hanya
+// diisi bila masih ada halaman berikutnya.
+type recordListResponse struct {
+	Data       []models.EnergyRecord `json:"data"`
+	Total      int                   `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+// recordResponse adalah record beserta peringatan yang tidak menggagalkan
+// request, mis. beban melebihi daya tersambung.
+type recordResponse struct {
+	models.EnergyRecord
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func AddRecord(repo repository.EnergyRecordRepositoryInterface, limits capacity.Config, rules RecordRules) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
 		var record models.EnergyRecord
-		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
-			log.Printf("Invalid JSON: %v", err)
-			http.Error(w, "Input tidak valid. Pastikan semua nilai benar.", http.StatusBadRequest)
+		if !decodeStrict(w, r, &record, MaxRecordBodyBytes) {
 			return
 		}
 
-		if err := validateEnergyRecord(&record); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if err := validateEnergyRecord(&record, rules); err != nil {
+			problem.Error(w, r, err)
 			return
 		}
 
-		if err := repo.AddRecord(&record); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		warnings := capacityWarnings(r.Context(), lang, repo, limits, household, record, 0)
+
+		if err := repo.AddRecord(r.Context(), household.ID, &record); err != nil {
+			problem.Error(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
 		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(record)
+		json.NewEncoder(w).Encode(recordResponse{EnergyRecord: record, Warnings: warnings})
 	}
 }
 
 func GetRecords(repo repository.EnergyRecordRepositoryInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		records, err := repo.GetRecords()
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		query, err := parseRecordQuery(r, household.Location())
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		page, err := repo.GetRecords(r.Context(), household.ID, query)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			problem.Error(w, r, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(records)
+		json.NewEncoder(w).Encode(recordListResponse{
+			Data:       page.Records,
+			Total:      page.Total,
+			Limit:      query.Limit,
+			Offset:     query.Offset,
+			NextCursor: page.NextCursor,
+		})
 	}
 }
 
 func DeleteRecords(repo repository.EnergyRecordRepositoryInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
 		id, err := validateParamId(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			problem.Error(w, r, err)
 			return
 		}
 
-		if err := repo.DeleteRecord(id); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if err := repo.DeleteRecord(r.Context(), household.ID, id); err != nil {
+			problem.Error(w, r, err)
 			return
 		}
 
@@ -101,11 +444,16 @@ func DeleteRecords(repo repository.EnergyRecordRepositoryInterface) http.Handler
 	}
 }
 
-func UpdateRecords(repo repository.EnergyRecordRepositoryInterface) http.HandlerFunc {
+func UpdateRecords(repo repository.EnergyRecordRepositoryInterface, rules RecordRules) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
 		id, err := validateParamId(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			problem.Error(w, r, err)
 			return
 		}
 
@@ -113,18 +461,17 @@ func UpdateRecords(repo repository.EnergyRecordRepositoryInterface) http.Handler
 
 		var record models.EnergyRecord
 		record.ID = idInt
-		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if !decodeStrict(w, r, &record, MaxRecordBodyBytes) {
 			return
 		}
 
-		if err := validateEnergyRecord(&record); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if err := validateEnergyRecord(&record, rules); err != nil {
+			problem.Error(w, r, err)
 			return
 		}
 
-		if err := repo.UpdateRecord(&record); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if err := repo.UpdateRecord(r.Context(), household.ID, &record); err != nil {
+			problem.Error(w, r, err)
 			return
 		}
 
@@ -136,15 +483,20 @@ func UpdateRecords(repo repository.EnergyRecordRepositoryInterface) http.Handler
 
 func GetByIdRecords(repo repository.EnergyRecordRepositoryInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
 		id, err := validateParamId(r)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			problem.Error(w, r, err)
 			return
 		}
 
-		record, err := repo.GetByIdRecord(id)
+		record, err := repo.GetByIdRecord(r.Context(), household.ID, id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			problem.Error(w, r, err)
 			return
 		}
 
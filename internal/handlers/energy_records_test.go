@@ -2,31 +2,40 @@ package handlers
 
 import (
 	"bytes"
+	"context"
+	"daya-listrik-api/internal/capacity"
 	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
 	"daya-listrik-api/internal/repository/mocks"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAddRecord_Success(t *testing.T) {
 	mockRepo := new(mocks.MockEnergyRecordRepository)
-	handler := AddRecord(mockRepo)
+	handler := AddRecord(mockRepo, capacity.Config{}, DefaultRecordRules())
 
 	record := models.EnergyRecord{
-		Device: "AC",
-		Usage:  100,
+		Device:   "AC",
+		Usage:    100,
+		Duration: 2,
 	}
 	body, _ := json.Marshal(record)
 
-	mockRepo.On("AddRecord", mock.AnythingOfType("*models.EnergyRecord")).Return(nil)
+	mockRepo.On("AddRecord", mock.Anything, testHouseholdID, mock.AnythingOfType("*models.EnergyRecord")).Return(nil)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader(body))
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader(body)))
 	w := httptest.NewRecorder()
 	handler(w, req)
 
@@ -41,16 +50,19 @@ func TestGetRecords_Success(t *testing.T) {
 	records := []models.EnergyRecord{
 		{ID: 1, Device: "Lamp", Usage: 20},
 	}
-	mockRepo.On("GetRecords").Return(records, nil)
+	mockRepo.On("GetRecords", mock.Anything, testHouseholdID, repository.RecordQuery{Limit: repository.DefaultRecordLimit}).
+		Return(&repository.RecordPage{Records: records, Total: 1}, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/records", nil)
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/records", nil))
 	w := httptest.NewRecorder()
 	handler(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	var resp []models.EnergyRecord
+	var resp recordListResponse
 	json.NewDecoder(w.Body).Decode(&resp)
-	assert.Equal(t, records, resp)
+	assert.Equal(t, records, resp.Data)
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, repository.DefaultRecordLimit, resp.Limit)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -59,9 +71,9 @@ func TestGetByIdRecords_Success(t *testing.T) {
 	handler := GetByIdRecords(mockRepo)
 
 	record := &models.EnergyRecord{ID: 1, Device: "TV", Usage: 50}
-	mockRepo.On("GetByIdRecord", "1").Return(record, nil)
+	mockRepo.On("GetByIdRecord", mock.Anything, testHouseholdID, "1").Return(record, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/records/1", nil)
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/records/1", nil))
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -75,14 +87,14 @@ func TestGetByIdRecords_Success(t *testing.T) {
 
 func TestUpdateRecords_Success(t *testing.T) {
 	mockRepo := new(mocks.MockEnergyRecordRepository)
-	handler := UpdateRecords(mockRepo)
+	handler := UpdateRecords(mockRepo, DefaultRecordRules())
 
-	record := models.EnergyRecord{ID: 1, Device: "Fan", Usage: 60}
+	record := models.EnergyRecord{ID: 1, Device: "Fan", Usage: 60, Duration: 1.5}
 	body, _ := json.Marshal(record)
 
-	mockRepo.On("UpdateRecord", mock.AnythingOfType("*models.EnergyRecord")).Return(nil)
+	mockRepo.On("UpdateRecord", mock.Anything, testHouseholdID, mock.AnythingOfType("*models.EnergyRecord")).Return(nil)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/records/1", bytes.NewReader(body))
+	req := withUser(httptest.NewRequest(http.MethodPut, "/api/records/1", bytes.NewReader(body)))
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -98,9 +110,9 @@ func TestDeleteRecords_Success(t *testing.T) {
 	mockRepo := new(mocks.MockEnergyRecordRepository)
 	handler := DeleteRecords(mockRepo)
 
-	mockRepo.On("DeleteRecord", "1").Return(nil)
+	mockRepo.On("DeleteRecord", mock.Anything, testHouseholdID, "1").Return(nil)
 
-	req := httptest.NewRequest(http.MethodDelete, "/api/records/1", nil)
+	req := withUser(httptest.NewRequest(http.MethodDelete, "/api/records/1", nil))
 	req = mux.SetURLVars(req, map[string]string{"id": "1"})
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -108,3 +120,476 @@ func TestDeleteRecords_Success(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestRecords_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	mockRepo.On("GetByIdRecord", mock.Anything, testHouseholdID, "99").Return((*models.EnergyRecord)(nil), repository.NotFound("energy record", "99"))
+	mockRepo.On("DeleteRecord", mock.Anything, testHouseholdID, "99").Return(repository.NotFound("energy record", "99"))
+
+	for method, handler := range map[string]http.HandlerFunc{
+		http.MethodGet:    GetByIdRecords(mockRepo),
+		http.MethodDelete: DeleteRecords(mockRepo),
+	} {
+		req := withUser(httptest.NewRequest(method, "/api/records/99", nil))
+		req = mux.SetURLVars(req, map[string]string{"id": "99"})
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code, method)
+		assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+		var resp problem.Problem
+		json.NewDecoder(w.Body).Decode(&resp)
+		assert.Equal(t, "energy_record_not_found", resp.Code)
+		assert.Equal(t, "/api/records/99", resp.Instance)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddRecord_ValidationProblem(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := AddRecord(mockRepo, capacity.Config{}, DefaultRecordRules())
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader([]byte(`{"device":"AC"}`))))
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	var resp problem.Problem
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, "validation_failed", resp.Code)
+	assert.Equal(t, []repository.FieldError{
+		{Field: "usage", Code: "required", Message: "usage is required and must be greater than 0"},
+		{Field: "duration", Code: "required", Message: "duration is required and must be greater than 0"},
+	}, resp.Errors)
+
+	// Tanpa Accept-Language pesan ditulis dalam bahasa Indonesia.
+	req = withUser(httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader([]byte(`{"device":"AC"}`))))
+	w = httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, "id", w.Header().Get("Content-Language"))
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, "usage wajib diisi dan harus lebih dari 0; duration wajib diisi dan harus lebih dari 0", resp.Detail)
+	assert.Equal(t, "usage wajib diisi dan harus lebih dari 0", resp.Errors[0].Message)
+}
+
+func TestAddRecord_ReportsAllFields(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := AddRecord(mockRepo, capacity.Config{}, DefaultRecordRules())
+
+	record := models.EnergyRecord{Device: strings.Repeat("a", 101), Usage: 20000, Duration: 30}
+	body, _ := json.Marshal(record)
+	w := httptest.NewRecorder()
+	handler(w, withUser(httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader(body))))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp problem.Problem
+	json.NewDecoder(w.Body).Decode(&resp)
+	var fields []string
+	for _, f := range resp.Errors {
+		fields = append(fields, f.Field)
+	}
+	assert.Equal(t, []string{"usage", "device", "duration"}, fields)
+	mockRepo.AssertNotCalled(t, "AddRecord", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecords_StrictBody(t *testing.T) {
+	cases := map[string]struct {
+		body   string
+		status int
+		code   string
+	}{
+		"unknown field":  {`{"device":"AC","usage":100,"duration":1,"watts":100}`, http.StatusBadRequest, "unknown_field"},
+		"wrong type":     {`{"device":"AC","usage":"100","duration":1}`, http.StatusBadRequest, "invalid_json"},
+		"trailing data":  {`{"device":"AC","usage":100,"duration":1}{}`, http.StatusBadRequest, "invalid_json"},
+		"oversized body": {`{"device":"AC","usage":100,"duration":1,"device":"` + strings.Repeat("a", MaxRecordBodyBytes) + `"}`, http.StatusRequestEntityTooLarge, "body_too_large"},
+	}
+
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handlers := map[string]http.HandlerFunc{
+		http.MethodPost: AddRecord(mockRepo, capacity.Config{}, DefaultRecordRules()),
+		http.MethodPut:  UpdateRecords(mockRepo, DefaultRecordRules()),
+	}
+	for name, tc := range cases {
+		for method, handler := range handlers {
+			req := withUser(httptest.NewRequest(method, "/api/records/1", strings.NewReader(tc.body)))
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, tc.status, w.Code, "%s %s", method, name)
+			var resp problem.Problem
+			json.NewDecoder(w.Body).Decode(&resp)
+			assert.Equal(t, tc.code, resp.Code, "%s %s", method, name)
+		}
+	}
+	mockRepo.AssertNotCalled(t, "AddRecord", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateRecord", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRecords_RequestContext(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := GetRecords(mockRepo)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/records", nil))
+	ctx, cancel := context.WithCancel(req.Context())
+	req = req.WithContext(ctx)
+
+	// Klien memutus koneksi saat query berjalan; repository menerima context
+	// request sehingga query ikut dibatalkan.
+	mockRepo.On("GetRecords", ctx, testHouseholdID, mock.Anything).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(nil, context.Canceled)
+
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, problem.StatusClientClosedRequest, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRecords_EnergyFilter(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := GetRecords(mockRepo)
+
+	minWh := 500.0
+	mockRepo.On("GetRecords", mock.Anything, testHouseholdID, repository.RecordQuery{MinEnergyWh: &minWh, SortBy: "energy_wh", SortDesc: true, Limit: repository.DefaultRecordLimit}).
+		Return(&repository.RecordPage{Records: []models.EnergyRecord{}}, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/records?min_energy_wh=500&sort=-energy_wh", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRecords_InvalidSort(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := GetRecords(mockRepo)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/records?sort=secret", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "GetRecords", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRecords_Pagination(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := GetRecords(mockRepo)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	afterID := 40
+	mockRepo.On("GetRecords", mock.Anything, testHouseholdID, repository.RecordQuery{
+		From: &from, Device: "AC", SortBy: "date", SortDesc: true, Limit: 2, AfterID: &afterID,
+	}).Return(&repository.RecordPage{
+		Records:    []models.EnergyRecord{{ID: 39}, {ID: 35}},
+		Total:      10,
+		NextCursor: repository.EncodeRecordCursor(35),
+	}, nil)
+
+	cursor := repository.EncodeRecordCursor(40)
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/records?from=2026-10-01T00:00:00Z&device=AC&sort=-date&limit=2&cursor="+cursor, nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp recordListResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Len(t, resp.Data, 2)
+	assert.Equal(t, 10, resp.Total)
+	assert.Equal(t, repository.EncodeRecordCursor(35), resp.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRecords_HouseholdTimezone(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := GetRecords(mockRepo)
+
+	// Tanggal dibaca sebagai tengah malam WIT, bukan zona waktu server
+	household := testHousehold
+	household.Timezone = "Asia/Jayapura"
+	from := time.Date(2026, 9, 30, 15, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	mockRepo.On("GetRecords", mock.Anything, testHouseholdID, mock.MatchedBy(func(q repository.RecordQuery) bool {
+		return q.From != nil && q.From.Equal(from) && q.To != nil && q.To.Equal(to)
+	})).Return(&repository.RecordPage{Records: []models.EnergyRecord{}}, nil)
+
+	req := inHousehold(httptest.NewRequest(http.MethodGet, "/api/records?from=2026-10-01&to=2026-10-02", nil), household)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRecords_InvalidPagination(t *testing.T) {
+	for _, query := range []string{"limit=0", "limit=501", "offset=-1", "cursor=!!", "offset=5&cursor=NDA", "from=2026-10-02&to=2026-10-01"} {
+		t.Run(query, func(t *testing.T) {
+			mockRepo := new(mocks.MockEnergyRecordRepository)
+			handler := GetRecords(mockRepo)
+
+			req := withUser(httptest.NewRequest(http.MethodGet, "/api/records?"+query, nil))
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockRepo.AssertNotCalled(t, "GetRecords", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAddRecord_BackfilledStartedAt(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	handler := AddRecord(mockRepo, capacity.Config{}, DefaultRecordRules())
+
+	yesterday := time.Now().AddDate(0, 0, -1).Truncate(time.Second)
+	body, _ := json.Marshal(models.EnergyRecord{Device: "AC", Usage: 350, Duration: 6, StartedAt: &yesterday})
+
+	mockRepo.On("GetActiveRecords", mock.Anything, testHouseholdID, mock.Anything, mock.Anything).Return([]models.EnergyRecord{}, nil)
+	mockRepo.On("AddRecord", mock.Anything, testHouseholdID, mock.MatchedBy(func(r *models.EnergyRecord) bool {
+		return r.Date.Equal(yesterday)
+	})).Return(nil)
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader(body)))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddRecord_InvalidDates(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	cases := map[string]models.EnergyRecord{
+		"future date":       {Device: "AC", Usage: 350, Duration: 1, Date: tomorrow},
+		"future started_at": {Device: "AC", Usage: 350, Duration: 1, StartedAt: &tomorrow},
+		"duration over max": {Device: "AC", Usage: 350, Duration: 25},
+	}
+
+	for name, record := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(mocks.MockEnergyRecordRepository)
+			handler := AddRecord(mockRepo, capacity.Config{}, DefaultRecordRules())
+
+			body, _ := json.Marshal(record)
+			req := withUser(httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader(body)))
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			mockRepo.AssertNotCalled(t, "AddRecord", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAddRecord_MaxDuration(t *testing.T) {
+	cases := []struct {
+		name     string
+		rules    float64
+		duration float64
+		status   int
+	}{
+		{"config tightens the cap", 12, 13, http.StatusUnprocessableEntity},
+		{"within tightened cap", 12, 12, http.StatusCreated},
+		{"config cannot loosen the cap", 48, 25, http.StatusUnprocessableEntity},
+		{"zero keeps the cap", 0, 25, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockEnergyRecordRepository)
+			mockRepo.On("AddRecord", mock.Anything, testHouseholdID, mock.Anything).Return(nil)
+			rules := DefaultRecordRules()
+			rules.MaxDuration = tc.rules
+			handler := AddRecord(mockRepo, capacity.Config{}, rules)
+
+			body, _ := json.Marshal(models.EnergyRecord{Device: "AC", Usage: 350, Duration: tc.duration})
+			w := httptest.NewRecorder()
+			handler(w, withUser(httptest.NewRequest(http.MethodPost, "/api/records/add", bytes.NewReader(body))))
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+// serveRecords mengirim request ke route record yang memakai repository di
+// memori, sehingga alur CRUD diuji dari HTTP sampai penyimpanan.
+func serveRecords(repo repository.EnergyRecordRepositoryInterface, method, target string, body any) *httptest.ResponseRecorder {
+	r := mux.NewRouter()
+	r.HandleFunc("/api/records/add", AddRecord(repo, capacity.Config{}, DefaultRecordRules())).Methods("POST")
+	r.HandleFunc("/api/records", GetRecords(repo)).Methods("GET")
+	r.HandleFunc("/api/records/{id}", GetByIdRecords(repo)).Methods("GET")
+	r.HandleFunc("/api/records/{id}", UpdateRecords(repo, DefaultRecordRules())).Methods("PUT")
+	r.HandleFunc("/api/records/{id}", DeleteRecords(repo)).Methods("DELETE")
+
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, withUser(httptest.NewRequest(method, target, &payload)))
+	return w
+}
+
+// newMemoryRecords membuat store memori dengan satu rumah tangga R-1/1300VA
+// beserta ownernya.
+func newMemoryRecords(t *testing.T) (repository.Repositories, models.Household) {
+	t.Helper()
+	repos := repository.NewMemoryRepositories(repository.NewMemoryStore())
+	ctx := context.Background()
+	user := &models.User{Email: "budi@example.com", PasswordHash: "hash"}
+	require.NoError(t, repos.Users.AddUser(ctx, user))
+	household := testHousehold
+	require.NoError(t, repos.Households.AddHousehold(ctx, user.ID, &household))
+	return repos, household
+}
+
+// serveMemory menjalankan request ke handler record, perangkat, rumah tangga
+// dan tarif yang memakai repos, dengan household sebagai rumah tangga aktif.
+func serveMemory(repos repository.Repositories, household models.Household, method, target string, body any) *httptest.ResponseRecorder {
+	r := mux.NewRouter()
+	r.HandleFunc("/api/records/add", AddRecord(repos.Records, capacity.Config{}, DefaultRecordRules())).Methods("POST")
+	r.HandleFunc("/api/records", GetRecords(repos.Records)).Methods("GET")
+	r.HandleFunc("/api/records/{id}", GetByIdRecords(repos.Records)).Methods("GET")
+	r.HandleFunc("/api/records/{id}", UpdateRecords(repos.Records, DefaultRecordRules())).Methods("PUT")
+	r.HandleFunc("/api/records/{id}", DeleteRecords(repos.Records)).Methods("DELETE")
+	r.HandleFunc("/api/devices/add", AddDevice(repos.Devices)).Methods("POST")
+	r.HandleFunc("/api/households/{id}", UpdateHouseholds(repos.Households)).Methods("PUT")
+	r.HandleFunc("/api/tariffs/add", AddTariff(repos.Tariffs)).Methods("POST")
+
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, inHousehold(httptest.NewRequest(method, target, &payload), household))
+	return w
+}
+
+func TestRecords_MemoryCRUD(t *testing.T) {
+	repos, household := newMemoryRecords(t)
+	date := time.Date(2026, 10, 1, 19, 0, 0, 0, time.UTC)
+
+	w := serveMemory(repos, household, http.MethodPost, "/api/records/add", map[string]any{"device": "AC", "usage": 350, "duration": 2, "date": date})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var added recordResponse
+	json.NewDecoder(w.Body).Decode(&added)
+	assert.Equal(t, 1, added.ID)
+	assert.Equal(t, models.Cost(0.7, 1444.70), added.CostIDR)
+
+	w = serveMemory(repos, household, http.MethodGet, "/api/records/1", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got models.EnergyRecord
+	json.NewDecoder(w.Body).Decode(&got)
+	assert.Equal(t, "AC", got.Device)
+	assert.True(t, date.Equal(got.Date))
+
+	w = serveMemory(repos, household, http.MethodPut, "/api/records/1", map[string]any{"device": "ac", "usage": 300, "duration": 1})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serveMemory(repos, household, http.MethodGet, "/api/records/1", nil)
+	json.NewDecoder(w.Body).Decode(&got)
+	assert.Equal(t, 300.0, got.Usage)
+	assert.Equal(t, "AC", got.Device, "devices are matched case-insensitively")
+	assert.Equal(t, added.DeviceID, got.DeviceID)
+	assert.True(t, date.Equal(got.Date), "update keeps the date")
+
+	w = serveMemory(repos, household, http.MethodGet, "/api/records", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list recordListResponse
+	json.NewDecoder(w.Body).Decode(&list)
+	assert.Equal(t, 1, list.Total)
+	if assert.Len(t, list.Data, 1) {
+		assert.Equal(t, 1, list.Data[0].ID)
+	}
+
+	w = serveMemory(repos, household, http.MethodDelete, "/api/records/1", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		w = serveMemory(repos, household, method, "/api/records/1", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, method)
+		var resp problem.Problem
+		json.NewDecoder(w.Body).Decode(&resp)
+		assert.Equal(t, "energy_record_not_found", resp.Code, method)
+	}
+	w = serveMemory(repos, household, http.MethodPut, "/api/records/1", map[string]any{"device": "AC", "usage": 300, "duration": 1})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRecords_MemoryPagination(t *testing.T) {
+	repos, household := newMemoryRecords(t)
+	for _, usage := range []float64{60, 350, 100, 200, 15} {
+		w := serveMemory(repos, household, http.MethodPost, "/api/records/add", map[string]any{"device": "Lampu", "usage": usage, "duration": 1})
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	var pages [][]float64
+	target := "/api/records?sort=-usage&limit=2"
+	for {
+		w := serveMemory(repos, household, http.MethodGet, target, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp recordListResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		assert.Equal(t, 5, resp.Total)
+		var usages []float64
+		for _, record := range resp.Data {
+			usages = append(usages, record.Usage)
+		}
+		pages = append(pages, usages)
+		if resp.NextCursor == "" {
+			break
+		}
+		target = "/api/records?sort=-usage&limit=2&cursor=" + resp.NextCursor
+	}
+	assert.Equal(t, [][]float64{{350, 200}, {100, 60}, {15}}, pages)
+}
+
+func TestRecords_MemoryRegisteredDevice(t *testing.T) {
+	repos, household := newMemoryRecords(t)
+
+	w := serveMemory(repos, household, http.MethodPost, "/api/devices/add", map[string]any{"name": "Lampu Teras", "category": "lighting", "rated_wattage": 20})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var device models.Device
+	json.NewDecoder(w.Body).Decode(&device)
+
+	w = serveMemory(repos, household, http.MethodPost, "/api/records/add", map[string]any{"device_id": device.ID, "usage": 600, "duration": 1})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, "lighting devices are capped at 500 W")
+
+	w = serveMemory(repos, household, http.MethodPost, "/api/records/add", map[string]any{"device": "lampu teras", "usage": 20, "duration": 1})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var added recordResponse
+	json.NewDecoder(w.Body).Decode(&added)
+	if assert.NotNil(t, added.DeviceID) {
+		assert.Equal(t, device.ID, *added.DeviceID, "records by name reuse the registered device")
+	}
+	assert.Equal(t, "Lampu Teras", added.Device)
+}
+
+func TestRecords_MemoryLiveTariffs(t *testing.T) {
+	repos, household := newMemoryRecords(t)
+	date := time.Date(2026, 10, 1, 19, 0, 0, 0, time.UTC)
+
+	w := serveMemory(repos, household, http.MethodPost, "/api/records/add", map[string]any{"device": "AC", "usage": 1000, "duration": 1, "date": date})
+	require.Equal(t, http.StatusCreated, w.Code)
+	cost := func() float64 {
+		w := serveMemory(repos, household, http.MethodGet, "/api/records/1", nil)
+		var got recordResponse
+		json.NewDecoder(w.Body).Decode(&got)
+		return got.CostIDR
+	}
+	assert.Equal(t, models.Cost(1, 1444.70), cost())
+
+	household.TariffClass = models.TariffR1_900VA
+	w = serveMemory(repos, household, http.MethodPut, fmt.Sprintf("/api/households/%d", household.ID), household)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.Cost(1, 605), cost(), "tariff class changes reprice records")
+
+	w = serveMemory(repos, household, http.MethodPost, "/api/tariffs/add", map[string]any{"class": models.TariffR1_900VA, "min_va": 900, "max_va": 900, "price_per_kwh": 700, "effective_from": time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, models.Cost(1, 700), cost(), "new tariffs apply from effective_from")
+}
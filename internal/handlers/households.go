@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/tenant"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// DefaultInvitationTTL adalah masa berlaku kode undangan bila tidak diisi.
+	DefaultInvitationTTL = 7 * 24 * time.Hour
+	MaxInvitationTTL     = 30 * 24 * time.Hour
+)
+
+// requestHousehold mengambil rumah tangga aktif yang diisi tenant.Middleware.
+func requestHousehold(w http.ResponseWriter, r *http.Request) (*models.Household, bool) {
+	membership, ok := tenant.FromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.New(http.StatusForbidden, "household_required", nil))
+		return nil, false
+	}
+	return &membership.Household, true
+}
+
+// requestMembership mengambil rumah tangga dari path {id} beserta peran user,
+// yang diisi tenant.PathMiddleware.
+func requestMembership(w http.ResponseWriter, r *http.Request) (*models.Membership, bool) {
+	membership, ok := tenant.FromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.New(http.StatusForbidden, "household_required", nil))
+		return nil, false
+	}
+	return &membership, true
+}
+
+// AddHousehold membuat rumah tangga baru dengan user sebagai owner. Field yang
+// tidak diisi memakai defaults.
+func AddHousehold(repo repository.HouseholdRepositoryInterface, defaults models.Household) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := requestUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var household models.Household
+		if err := json.NewDecoder(r.Body).Decode(&household); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+
+		household.ApplyDefaults(defaults)
+		if err := household.Validate(); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.AddHousehold(r.Context(), userID, &household); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(models.Membership{Household: household, Role: models.RoleOwner})
+	}
+}
+
+func GetHouseholds(repo repository.HouseholdRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := requestUserID(w, r)
+		if !ok {
+			return
+		}
+
+		memberships, err := repo.GetHouseholds(r.Context(), userID)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(memberships)
+	}
+}
+
+func GetByIdHouseholds() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		membership, ok := requestMembership(w, r)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(membership)
+	}
+}
+
+func UpdateHouseholds(repo repository.HouseholdRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		membership, ok := requestMembership(w, r)
+		if !ok {
+			return
+		}
+
+		var household models.Household
+		if err := json.NewDecoder(r.Body).Decode(&household); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+		household.ID = membership.Household.ID
+
+		if err := household.Validate(); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.UpdateHousehold(r.Context(), &household); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(household)
+	}
+}
+
+// DeleteHouseholds menghapus rumah tangga beserta semua record, perangkat,
+// token dan pembacaan meternya.
+func DeleteHouseholds(repo repository.HouseholdRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		membership, ok := requestMembership(w, r)
+		if !ok {
+			return
+		}
+
+		if err := repo.DeleteHousehold(r.Context(), membership.Household.ID); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type invitationRequest struct {
+	Role           string `json:"role"`
+	ExpiresInHours int    `json:"expires_in_hours"`
+}
+
+// AddInvitation membuat kode undangan sekali pakai. Kode hanya ditampilkan
+// pada respons ini.
+func AddInvitation(repo repository.HouseholdRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		membership, ok := requestMembership(w, r)
+		if !ok {
+			return
+		}
+		userID, _ := requestUserID(w, r)
+
+		var req invitationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+		if req.Role == "" {
+			req.Role = models.RoleViewer
+		}
+		if !models.ValidRole(req.Role) {
+			problem.Error(w, r, invalidField("role", validate.CodeInvalid, i18n.Params{"values": models.Roles}))
+			return
+		}
+		ttl := DefaultInvitationTTL
+		if req.ExpiresInHours != 0 {
+			ttl = time.Duration(req.ExpiresInHours) * time.Hour
+			if ttl < time.Hour || ttl > MaxInvitationTTL {
+				problem.Error(w, r, invalidField("expires_in_hours", validate.CodeOutOfRange, i18n.Params{"min": 1, "max": int(MaxInvitationTTL.Hours())}))
+				return
+			}
+		}
+
+		invitation := models.HouseholdInvitation{
+			HouseholdID: membership.Household.ID,
+			Role:        req.Role,
+			CreatedBy:   userID,
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+		if err := repo.AddInvitation(r.Context(), &invitation); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(invitation)
+	}
+}
+
+func JoinHousehold(repo repository.HouseholdRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := requestUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var body struct {
+			Code string `json:"code"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+		if strings.TrimSpace(body.Code) == "" {
+			problem.Error(w, r, invalidField("code", validate.CodeRequired, nil))
+			return
+		}
+
+		membership, err := repo.RedeemInvitation(r.Context(), body.Code, userID)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(membership)
+	}
+}
+
+func GetHouseholdMembers(repo repository.HouseholdRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		membership, ok := requestMembership(w, r)
+		if !ok {
+			return
+		}
+
+		members, err := repo.GetMembers(r.Context(), membership.Household.ID)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(members)
+	}
+}
+
+func validateMemberParam(r *http.Request) (int, error) {
+	userID, err := strconv.Atoi(strings.TrimSpace(mux.Vars(r)["user_id"]))
+	if err != nil {
+		return 0, problem.InvalidParameter("user_id", validate.CodeInvalid, nil)
+	}
+	return userID, nil
+}
+
+func UpdateHouseholdMembers(repo repository.HouseholdRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		membership, ok := requestMembership(w, r)
+		if !ok {
+			return
+		}
+
+		memberID, err := validateMemberParam(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		var body struct {
+			Role string `json:"role"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+		if !models.ValidRole(body.Role) {
+			problem.Error(w, r, invalidField("role", validate.CodeInvalid, i18n.Params{"values": models.Roles}))
+			return
+		}
+
+		if err := repo.UpdateMemberRole(r.Context(), membership.Household.ID, memberID, body.Role); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]any{"user_id": memberID, "role": body.Role})
+	}
+}
+
+// DeleteHouseholdMembers mengeluarkan anggota. Izin dicek di route: owner
+// boleh mengeluarkan siapa saja, anggota lain hanya boleh keluar sendiri.
+func DeleteHouseholdMembers(repo repository.HouseholdRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		membership, ok := requestMembership(w, r)
+		if !ok {
+			return
+		}
+
+		memberID, err := validateMemberParam(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.DeleteMember(r.Context(), membership.Household.ID, memberID); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/repository/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// householdRequest membuat request atas nama testUserID, owner testHousehold,
+// dengan variabel path.
+func householdRequest(method, target string, body any, vars map[string]string) *http.Request {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	return mux.SetURLVars(withUser(httptest.NewRequest(method, target, &buf)), vars)
+}
+
+func TestAddHousehold_Success(t *testing.T) {
+	mockRepo := new(mocks.MockHouseholdRepository)
+	handler := AddHousehold(mockRepo, testHouseholdDefaults)
+
+	mockRepo.On("AddHousehold", mock.Anything, testUserID, mock.MatchedBy(func(h *models.Household) bool {
+		return h.Name == "Kos" && h.TariffClass == models.TariffR1_900VA && h.Timezone == "Asia/Makassar"
+	})).Return(nil)
+
+	w := httptest.NewRecorder()
+	handler(w, householdRequest(http.MethodPost, "/api/households/add", models.Household{Name: "Kos"}, nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp models.Membership
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, models.RoleOwner, resp.Role)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddHousehold_Invalid(t *testing.T) {
+	mockRepo := new(mocks.MockHouseholdRepository)
+	handler := AddHousehold(mockRepo, testHouseholdDefaults)
+
+	for _, household := range []models.Household{{BillingDay: 31}, {Timezone: "Mars/Olympus"}} {
+		w := httptest.NewRecorder()
+		handler(w, householdRequest(http.MethodPost, "/api/households/add", household, nil))
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	}
+	mockRepo.AssertNotCalled(t, "AddHousehold", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateHouseholds_Success(t *testing.T) {
+	mockRepo := new(mocks.MockHouseholdRepository)
+	handler := UpdateHouseholds(mockRepo)
+
+	mockRepo.On("UpdateHousehold", mock.Anything, mock.MatchedBy(func(h *models.Household) bool {
+		return h.ID == testHouseholdID && h.TariffClass == models.TariffR1_900VA
+	})).Return(nil)
+
+	household := testHousehold
+	household.ID = 99
+	household.TariffClass = models.TariffR1_900VA
+	w := httptest.NewRecorder()
+	handler(w, householdRequest(http.MethodPut, "/api/households/10", household, map[string]string{"id": "10"}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddInvitation_Success(t *testing.T) {
+	mockRepo := new(mocks.MockHouseholdRepository)
+	handler := AddInvitation(mockRepo)
+
+	mockRepo.On("AddInvitation", mock.Anything, mock.MatchedBy(func(inv *models.HouseholdInvitation) bool {
+		return inv.HouseholdID == testHouseholdID && inv.Role == models.RoleViewer && inv.CreatedBy == testUserID &&
+			time.Until(inv.ExpiresAt) > 47*time.Hour && time.Until(inv.ExpiresAt) <= 48*time.Hour
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.HouseholdInvitation).Code = "A1B2C3D4E5F60718"
+	}).Return(nil)
+
+	w := httptest.NewRecorder()
+	handler(w, householdRequest(http.MethodPost, "/api/households/10/invitations", invitationRequest{ExpiresInHours: 48}, map[string]string{"id": "10"}))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), "A1B2C3D4E5F60718")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddInvitation_InvalidRequest(t *testing.T) {
+	for name, body := range map[string]invitationRequest{
+		"unknown role": {Role: "admin"},
+		"ttl too long": {ExpiresInHours: 24 * 31},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(mocks.MockHouseholdRepository)
+			handler := AddInvitation(mockRepo)
+
+			w := httptest.NewRecorder()
+			handler(w, householdRequest(http.MethodPost, "/api/households/10/invitations", body, map[string]string{"id": "10"}))
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			mockRepo.AssertNotCalled(t, "AddInvitation", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestJoinHousehold(t *testing.T) {
+	mockRepo := new(mocks.MockHouseholdRepository)
+	handler := JoinHousehold(mockRepo)
+
+	mockRepo.On("RedeemInvitation", mock.Anything, "A1B2C3D4E5F60718", testUserID).Return(&models.Membership{Household: testHousehold, Role: models.RoleEditor}, nil)
+	mockRepo.On("RedeemInvitation", mock.Anything, "USED", testUserID).Return(nil, repository.ErrInvalidInvitation)
+	mockRepo.On("RedeemInvitation", mock.Anything, "AGAIN", testUserID).Return(nil, repository.ErrAlreadyMember)
+
+	cases := map[string]int{"A1B2C3D4E5F60718": http.StatusCreated, "USED": http.StatusUnprocessableEntity, "AGAIN": http.StatusConflict, " ": http.StatusUnprocessableEntity}
+	for code, status := range cases {
+		w := httptest.NewRecorder()
+		handler(w, householdRequest(http.MethodPost, "/api/households/join", map[string]string{"code": code}, nil))
+		assert.Equal(t, status, w.Code, code)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateHouseholdMembers_LastOwner(t *testing.T) {
+	mockRepo := new(mocks.MockHouseholdRepository)
+	handler := UpdateHouseholdMembers(mockRepo)
+
+	mockRepo.On("UpdateMemberRole", mock.Anything, testHouseholdID, testUserID, models.RoleViewer).Return(repository.ErrLastOwner)
+
+	w := httptest.NewRecorder()
+	handler(w, householdRequest(http.MethodPut, "/api/households/10/members/1", map[string]string{"role": models.RoleViewer}, map[string]string{"id": "10", "user_id": "1"}))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteHouseholdMembers(t *testing.T) {
+	mockRepo := new(mocks.MockHouseholdRepository)
+	handler := DeleteHouseholdMembers(mockRepo)
+
+	mockRepo.On("DeleteMember", mock.Anything, testHouseholdID, 2).Return(nil)
+
+	w := httptest.NewRecorder()
+	handler(w, householdRequest(http.MethodDelete, "/api/households/10/members/2", nil, map[string]string{"id": "10", "user_id": "2"}))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/billing"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func validateMeterReading(reading *models.MeterReading) error {
+	var v validate.Validator
+	v.Check(reading.CumulativeKWh >= 0, "cumulative_kwh", validate.CodeOutOfRange, nil)
+	v.Check(!reading.ReadAt.After(time.Now()), "read_at", validate.CodeInFuture, nil)
+	reading.PhotoRef = strings.TrimSpace(reading.PhotoRef)
+	return v.Err()
+}
+
+func AddMeterReading(repo repository.MeterReadingRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		var reading models.MeterReading
+		if err := json.NewDecoder(r.Body).Decode(&reading); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+
+		if err := validateMeterReading(&reading); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.AddMeterReading(r.Context(), household.ID, &reading); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(reading)
+	}
+}
+
+func GetMeterReadings(repo repository.MeterReadingRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		readings, err := repo.GetMeterReadings(r.Context(), household.ID)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(readings)
+	}
+}
+
+func GetByIdMeterReadings(repo repository.MeterReadingRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		id, err := validateParamId(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		reading, err := repo.GetByIdMeterReading(r.Context(), household.ID, id)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(reading)
+	}
+}
+
+func DeleteMeterReadings(repo repository.MeterReadingRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		id, err := validateParamId(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.DeleteMeterReading(r.Context(), household.ID, id); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// reconcileReadings mengambil pasangan pembacaan dari from_id dan to_id, atau
+// dua pembacaan terakhir bila keduanya tidak diisi.
+func reconcileReadings(repo repository.MeterReadingRepositoryInterface, householdID int, r *http.Request) (*models.MeterReading, *models.MeterReading, error) {
+	fromID := strings.TrimSpace(r.URL.Query().Get("from_id"))
+	toID := strings.TrimSpace(r.URL.Query().Get("to_id"))
+
+	if fromID == "" && toID == "" {
+		latest, err := repo.GetLatestMeterReadings(r.Context(), householdID, 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(latest) < 2 {
+			return nil, nil, problem.New(http.StatusUnprocessableEntity, "not_enough_meter_readings", nil)
+		}
+		return &latest[1], &latest[0], nil
+	}
+
+	var invalid []validate.FieldError
+	for _, param := range []struct{ name, value string }{{"from_id", fromID}, {"to_id", toID}} {
+		if _, err := strconv.Atoi(param.value); err != nil {
+			invalid = append(invalid, validate.NewFieldError(param.name, validate.CodeInvalid, nil))
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, nil, problem.New(http.StatusBadRequest, "invalid_parameter", nil, invalid...)
+	}
+
+	from, err := repo.GetByIdMeterReading(r.Context(), householdID, fromID)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := repo.GetByIdMeterReading(r.Context(), householdID, toID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+func ReconcileMeterReadings(readings repository.MeterReadingRepositoryInterface, records repository.EnergyRecordRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		from, to, err := reconcileReadings(readings, household.ID, r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+		if !to.ReadAt.After(from.ReadAt) {
+			problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, "invalid_reading_order", nil))
+			return
+		}
+
+		tracked, err := records.SummarizeRecords(r.Context(), household.ID, from.ReadAt, to.ReadAt)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		// Urutan pembacaan sudah dicek, jadi error di sini berarti angka meter turun.
+		result, err := billing.Reconcile(*from, *to, tracked)
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, "reconcile_failed", i18n.Params{"from": from.CumulativeKWh, "to": to.CumulativeKWh}))
+			return
+		}
+		lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		if result.NoteCode != "" {
+			result.OverTrackedNote = i18n.Text(lang, result.NoteCode, nil)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"daya-listrik-api/internal/billing"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddMeterReading_Success(t *testing.T) {
+	mockRepo := new(mocks.MockMeterReadingRepository)
+	handler := AddMeterReading(mockRepo)
+
+	body, _ := json.Marshal(models.MeterReading{CumulativeKWh: 12000.5, PhotoRef: "meter/1.jpg"})
+
+	mockRepo.On("AddMeterReading", mock.Anything, testHouseholdID, mock.AnythingOfType("*models.MeterReading")).Return(nil)
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/meter-readings/add", bytes.NewReader(body)))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetMeterReadings_Success(t *testing.T) {
+	mockRepo := new(mocks.MockMeterReadingRepository)
+	handler := GetMeterReadings(mockRepo)
+
+	mockRepo.On("GetMeterReadings", mock.Anything, testHouseholdID).Return([]models.MeterReading{{ID: 1, CumulativeKWh: 12000}}, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/meter-readings", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteMeterReadings_Success(t *testing.T) {
+	mockRepo := new(mocks.MockMeterReadingRepository)
+	handler := DeleteMeterReadings(mockRepo)
+
+	mockRepo.On("DeleteMeterReading", mock.Anything, testHouseholdID, "1").Return(nil)
+
+	req := withUser(httptest.NewRequest(http.MethodDelete, "/api/meter-readings/1", nil))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReconcileMeterReadings_LatestPair(t *testing.T) {
+	readingRepo := new(mocks.MockMeterReadingRepository)
+	recordRepo := new(mocks.MockEnergyRecordRepository)
+	handler := ReconcileMeterReadings(readingRepo, recordRepo)
+
+	start := time.Date(2026, 10, 1, 7, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+	readingRepo.On("GetLatestMeterReadings", mock.Anything, testHouseholdID, 2).Return([]models.MeterReading{
+		{ID: 2, ReadAt: end, CumulativeKWh: 12050},
+		{ID: 1, ReadAt: start, CumulativeKWh: 12000},
+	}, nil)
+	recordRepo.On("SummarizeRecords", mock.Anything, testHouseholdID, start, end).Return(&models.UsageSummary{RecordCount: 10, EnergyKWh: 40}, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/meter-readings/reconcile", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.Reconciliation
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, 10.0, resp.UntrackedKWh)
+	assert.Equal(t, 20.0, resp.UntrackedPct)
+	assert.Empty(t, resp.NoteCode)
+	assert.Empty(t, resp.OverTrackedNote)
+	readingRepo.AssertExpectations(t)
+	recordRepo.AssertExpectations(t)
+}
+
+func TestReconcileMeterReadings_OverTrackedNote(t *testing.T) {
+	readingRepo := new(mocks.MockMeterReadingRepository)
+	recordRepo := new(mocks.MockEnergyRecordRepository)
+	handler := ReconcileMeterReadings(readingRepo, recordRepo)
+
+	start := time.Date(2026, 10, 1, 7, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+	readingRepo.On("GetLatestMeterReadings", mock.Anything, testHouseholdID, 2).Return([]models.MeterReading{
+		{ID: 2, ReadAt: end, CumulativeKWh: 12050},
+		{ID: 1, ReadAt: start, CumulativeKWh: 12000},
+	}, nil)
+	recordRepo.On("SummarizeRecords", mock.Anything, testHouseholdID, start, end).Return(&models.UsageSummary{RecordCount: 10, EnergyKWh: 55}, nil)
+
+	for lang, note := range map[string]string{
+		"id": "Record perangkat melebihi pemakaian menurut meter; periksa durasi dan daya perangkat",
+		"en": "device records exceed metered consumption; check durations and wattages",
+	} {
+		req := withUser(httptest.NewRequest(http.MethodGet, "/api/meter-readings/reconcile", nil))
+		req.Header.Set("Accept-Language", lang)
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, lang, w.Header().Get("Content-Language"))
+		var resp models.Reconciliation
+		json.NewDecoder(w.Body).Decode(&resp)
+		assert.Equal(t, billing.NoteOverTracked, resp.NoteCode)
+		assert.Equal(t, note, resp.OverTrackedNote)
+	}
+}
+
+func TestReconcileMeterReadings_NotEnoughReadings(t *testing.T) {
+	readingRepo := new(mocks.MockMeterReadingRepository)
+	handler := ReconcileMeterReadings(readingRepo, new(mocks.MockEnergyRecordRepository))
+
+	readingRepo.On("GetLatestMeterReadings", mock.Anything, testHouseholdID, 2).Return([]models.MeterReading{{ID: 1}}, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/meter-readings/reconcile", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestReconcileMeterReadings_InvalidIDs(t *testing.T) {
+	cases := map[string][]string{
+		"?from_id=1":         {"to_id"},
+		"?from_id=1&to_id=x": {"to_id"},
+		"?from_id=x&to_id=2": {"from_id"},
+		"?to_id=2":           {"from_id"},
+		"?from_id=a&to_id=b": {"from_id", "to_id"},
+	}
+
+	for query, fields := range cases {
+		t.Run(query, func(t *testing.T) {
+			readingRepo := new(mocks.MockMeterReadingRepository)
+			handler := ReconcileMeterReadings(readingRepo, new(mocks.MockEnergyRecordRepository))
+
+			req := withUser(httptest.NewRequest(http.MethodGet, "/api/meter-readings/reconcile"+query, nil))
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var resp problem.Problem
+			json.NewDecoder(w.Body).Decode(&resp)
+			var got []string
+			for _, f := range resp.Errors {
+				got = append(got, f.Field)
+			}
+			assert.Equal(t, fields, got)
+			readingRepo.AssertNotCalled(t, "GetByIdMeterReading", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"daya-listrik-api/internal/authz"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// routePublic tidak membutuhkan login.
+	routePublic authz.Permission = "public"
+	// routeAuthenticated cukup login.
+	routeAuthenticated authz.Permission = "authenticated"
+)
+
+// routeMatrix adalah izin yang diharapkan untuk setiap route. Route baru harus
+// ditambahkan di sini.
+var routeMatrix = map[string]authz.Permission{
+	"POST /api/auth/register": routePublic,
+	"POST /api/auth/login":    routePublic,
+	"POST /api/auth/refresh":  routePublic,
+
+	"GET /api/households":                           routeAuthenticated,
+	"POST /api/households/add":                      routeAuthenticated,
+	"POST /api/households/join":                     routeAuthenticated,
+	"GET /api/households/{id}":                      authz.HouseholdRead,
+	"PUT /api/households/{id}":                      authz.HouseholdAdmin,
+	"DELETE /api/households/{id}":                   authz.HouseholdAdmin,
+	"POST /api/households/{id}/invitations":         authz.HouseholdAdmin,
+	"GET /api/households/{id}/members":              authz.HouseholdRead,
+	"PUT /api/households/{id}/members/{user_id}":    authz.HouseholdAdmin,
+	"DELETE /api/households/{id}/members/{user_id}": authz.HouseholdAdmin,
+	"GET /api/tariffs":                              routeAuthenticated,
+	"GET /api/tariffs/{id}":                         routeAuthenticated,
+	"POST /api/tariffs/add":                         authz.TariffsAdmin,
+	"GET /api/records":                              authz.RecordsRead,
+	"POST /api/records/add":                         authz.RecordsWrite,
+	"GET /api/records/{id}":                         authz.RecordsRead,
+	"PUT /api/records/{id}":                         authz.RecordsWrite,
+	"DELETE /api/records/{id}":                      authz.RecordsWrite,
+	"GET /api/devices":                              authz.RecordsRead,
+	"POST /api/devices/add":                         authz.RecordsWrite,
+	"GET /api/devices/{id}":                         authz.RecordsRead,
+	"PUT /api/devices/{id}":                         authz.RecordsWrite,
+	"DELETE /api/devices/{id}":                      authz.RecordsWrite,
+	"GET /api/bills/estimate":                       authz.RecordsRead,
+	"GET /api/capacity/overloads":                   authz.RecordsRead,
+	"GET /api/stats/usage":                          authz.RecordsRead,
+	"GET /api/tokens":                               authz.RecordsRead,
+	"POST /api/tokens/add":                          authz.RecordsWrite,
+	"GET /api/tokens/balance":                       authz.RecordsRead,
+	"GET /api/tokens/{id}":                          authz.RecordsRead,
+	"DELETE /api/tokens/{id}":                       authz.RecordsWrite,
+	"GET /api/meter-readings":                       authz.RecordsRead,
+	"POST /api/meter-readings/add":                  authz.RecordsWrite,
+	"GET /api/meter-readings/reconcile":             authz.RecordsRead,
+	"GET /api/meter-readings/{id}":                  authz.RecordsRead,
+	"DELETE /api/meter-readings/{id}":               authz.RecordsWrite,
+	"GET /api/keys":                                 authz.HouseholdAdmin,
+	"POST /api/keys/add":                            authz.HouseholdAdmin,
+	"DELETE /api/keys/{id}":                         authz.HouseholdAdmin,
+}
+
+type routePrincipal struct {
+	userID  int
+	role    string
+	isAdmin bool
+}
+
+var routePrincipals = []routePrincipal{
+	{userID: 1, role: models.RoleOwner},
+	{userID: 2, role: models.RoleEditor},
+	{userID: 3, role: models.RoleViewer},
+	{userID: 4, role: models.RoleViewer, isAdmin: true},
+}
+
+func (p routePrincipal) allowed(permission authz.Permission) bool {
+	if permission == authz.TariffsAdmin {
+		return p.isAdmin
+	}
+	return slices.Contains(authz.RolePermissions(p.role), permission)
+}
+
+// newMatrixRouter membuat router lengkap di atas memory store berisi
+// rumah tangga 1 dengan anggota routePrincipals, sehingga route yang lolos
+// pemeriksaan izin dijalankan sampai selesai. Store baru dibuat untuk setiap
+// router karena sebagian route mengubah atau menghapus data.
+func newMatrixRouter(t *testing.T) *mux.Router {
+	t.Helper()
+	store := repository.NewMemoryStore()
+	repos := repository.NewMemoryRepositories(store)
+	ctx := context.Background()
+
+	household := &models.Household{Name: "Rumah", TariffClass: models.TariffR1_1300VA, Timezone: models.DefaultTimezone, BillingDay: 1}
+	for _, p := range routePrincipals {
+		user := &models.User{Email: fmt.Sprintf("user%d@example.com", p.userID), PasswordHash: "hash"}
+		require.NoError(t, repos.Users.AddUser(ctx, user))
+		require.Equal(t, p.userID, user.ID)
+		if p.isAdmin {
+			require.NoError(t, store.SetAdmin(ctx, user.Email, true))
+		}
+		if p.role == models.RoleOwner {
+			require.NoError(t, repos.Households.AddHousehold(ctx, user.ID, household))
+			continue
+		}
+		invitation := &models.HouseholdInvitation{HouseholdID: household.ID, Role: p.role, CreatedBy: 1, ExpiresAt: time.Now().Add(time.Hour)}
+		require.NoError(t, repos.Households.AddInvitation(ctx, invitation))
+		_, err := repos.Households.RedeemInvitation(ctx, invitation.Code, user.ID)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, household.ID)
+
+	r := mux.NewRouter()
+	InitializeRoutes(r, Dependencies{
+		Records:     repos.Records,
+		Devices:     repos.Devices,
+		Tariffs:     repos.Tariffs,
+		Tokens:      repos.Tokens,
+		Meters:      repos.Meters,
+		RecordRules: DefaultRecordRules(),
+		Users:       repos.Users,
+		Households:  repos.Households,
+		APIKeys:     repos.APIKeys,
+		Auth:        testAuth,
+	})
+	return r
+}
+
+// serveMatrix menjalankan route dari routeMatrix di router baru.
+func serveMatrix(t *testing.T, route string, token string) *httptest.ResponseRecorder {
+	method, template, _ := strings.Cut(route, " ")
+	return serveRoute(t, newMatrixRouter(t), method, strings.NewReplacer("{id}", "1", "{user_id}", "1").Replace(template), token)
+}
+
+// serveRoute menjalankan request dengan body "{}". Panic di handler membuat
+// test gagal, bukan dianggap lolos.
+func serveRoute(t *testing.T, r *mux.Router, method, target, token string) (w *httptest.ResponseRecorder) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader("{}"))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w = httptest.NewRecorder()
+	defer func() {
+		if err := recover(); err != nil {
+			t.Errorf("%s %s panicked: %v", method, target, err)
+		}
+	}()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouteMatrix_CoversEveryRoute(t *testing.T) {
+	var registered []string
+	newMatrixRouter(t).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, _ := route.GetMethods()
+		for _, method := range methods {
+			registered = append(registered, method+" "+template)
+		}
+		return nil
+	})
+
+	var expected []string
+	for route := range routeMatrix {
+		expected = append(expected, route)
+	}
+	assert.ElementsMatch(t, expected, registered)
+}
+
+func TestRouteMatrix_Permissions(t *testing.T) {
+	for route, permission := range routeMatrix {
+		t.Run(route, func(t *testing.T) {
+			if permission == routePublic {
+				return
+			}
+			assert.Equal(t, http.StatusUnauthorized, serveMatrix(t, route, "").Code, "without token")
+
+			if permission == routeAuthenticated {
+				return
+			}
+			for _, p := range routePrincipals {
+				tokens, _ := testAuth.IssueTokens(p.userID)
+				w := serveMatrix(t, route, tokens.AccessToken)
+
+				if p.allowed(permission) {
+					assert.NotEqual(t, http.StatusForbidden, w.Code, "user %d (%s) should be allowed", p.userID, p.role)
+					assert.Less(t, w.Code, http.StatusInternalServerError, "user %d (%s): %s", p.userID, p.role, w.Body)
+					continue
+				}
+				assert.Equal(t, http.StatusForbidden, w.Code, "user %d (%s) should be forbidden", p.userID, p.role)
+				var resp problem.Problem
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, "forbidden", resp.Code)
+				assert.Equal(t, string(permission), resp.Permission)
+			}
+		})
+	}
+}
+
+func TestHouseholdRoutes_NotMember(t *testing.T) {
+	r := newMatrixRouter(t)
+	tokens, _ := testAuth.IssueTokens(1)
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		w := serveRoute(t, r, method, "/api/households/99", tokens.AccessToken)
+		assert.Equal(t, http.StatusNotFound, w.Code, method)
+	}
+}
+
+func TestHouseholdRoutes_MemberLeaves(t *testing.T) {
+	r := newMatrixRouter(t)
+	tokens, _ := testAuth.IssueTokens(3)
+
+	// Viewer tidak boleh mengeluarkan anggota lain, tetapi boleh keluar sendiri
+	w := serveRoute(t, r, http.MethodDelete, "/api/households/1/members/2", tokens.AccessToken)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serveRoute(t, r, http.MethodDelete, "/api/households/1/members/3", tokens.AccessToken)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/stats"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type usageStatsResponse struct {
+	Bucket   string               `json:"bucket"`
+	GroupBy  string               `json:"group_by,omitempty"`
+	Timezone string               `json:"timezone"`
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Buckets  []models.UsageBucket `json:"buckets"`
+}
+
+// GetUsageStats mengembalikan pemakaian per hari, minggu atau bulan menurut
+// jam dinding zona waktu rumah tangga, dengan bucket kosong bernilai nol.
+func GetUsageStats(repo repository.EnergyRecordRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+		loc := household.Location()
+
+		params := r.URL.Query()
+
+		bucket := strings.TrimSpace(params.Get("bucket"))
+		if bucket == "" {
+			bucket = models.BucketDay
+		}
+		if !models.ValidBucket(bucket) {
+			problem.Write(w, r, problem.InvalidParameter("bucket", validate.CodeInvalid, i18n.Params{"values": models.Buckets}))
+			return
+		}
+
+		groupBy := strings.TrimSpace(params.Get("group_by"))
+		if groupBy != "" && groupBy != "device" {
+			problem.Write(w, r, problem.InvalidParameter("group_by", validate.CodeInvalid, i18n.Params{"values": []string{"device"}}))
+			return
+		}
+
+		now := time.Now().In(loc)
+		from, err := parseTimeParamIn(r, "from", now.AddDate(0, 0, -30), loc)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+		to, err := parseTimeParamIn(r, "to", now, loc)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+		if !to.After(from) {
+			problem.Write(w, r, problem.InvalidParameter("to", validate.CodeOutOfRange, nil))
+			return
+		}
+
+		from, to = stats.Align(from, to, bucket, loc)
+		starts := stats.Starts(from, to, bucket, loc)
+		if len(starts) > stats.MaxBuckets {
+			problem.Write(w, r, problem.InvalidParameter("from", validate.CodeOutOfRange, i18n.Params{"max": stats.MaxBuckets}))
+			return
+		}
+
+		rows, err := repo.GetUsageBuckets(r.Context(), household.ID, repository.UsageBucketQuery{
+			From:          from,
+			To:            to,
+			Bucket:        bucket,
+			GroupByDevice: groupBy == "device",
+			Location:      loc,
+		})
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(usageStatsResponse{
+			Bucket:   bucket,
+			GroupBy:  groupBy,
+			Timezone: loc.String(),
+			From:     from,
+			To:       to,
+			Buckets:  stats.Fill(rows, starts, groupBy == "device"),
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/repository/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetUsageStats_Success(t *testing.T) {
+	mockRepo := new(mocks.MockEnergyRecordRepository)
+	wib := testHousehold.Location()
+	handler := GetUsageStats(mockRepo)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, wib)
+	to := time.Date(2026, 10, 4, 0, 0, 0, 0, wib)
+	mockRepo.On("GetUsageBuckets", mock.Anything, testHouseholdID, repository.UsageBucketQuery{
+		From: from, To: to, Bucket: models.BucketDay, GroupByDevice: true, Location: wib,
+	}).Return([]models.UsageBucket{
+		{Start: from.AddDate(0, 0, 1), Device: "AC", RecordCount: 2, EnergyKWh: 5.6, CostIDR: 8090.32},
+	}, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/stats/usage?bucket=day&group_by=device&from=2026-10-01&to=2026-10-03T18:00:00%2B07:00", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp usageStatsResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, "Asia/Jakarta", resp.Timezone)
+	assert.Len(t, resp.Buckets, 3)
+	assert.Equal(t, 0.0, resp.Buckets[0].EnergyKWh)
+	assert.Equal(t, 5.6, resp.Buckets[1].EnergyKWh)
+	assert.Equal(t, "AC", resp.Buckets[2].Device)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetUsageStats_InvalidParams(t *testing.T) {
+	for _, query := range []string{"bucket=hour", "group_by=room", "from=kemarin", "from=2026-10-02&to=2026-10-01", "from=2000-01-01&to=2026-01-01"} {
+		t.Run(query, func(t *testing.T) {
+			mockRepo := new(mocks.MockEnergyRecordRepository)
+			handler := GetUsageStats(mockRepo)
+
+			req := withUser(httptest.NewRequest(http.MethodGet, "/api/stats/usage?"+query, nil))
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockRepo.AssertNotCalled(t, "GetUsageBuckets", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+func validateTariff(tariff *models.Tariff) error {
+	var v validate.Validator
+	v.Check(models.ValidTariffClass(tariff.Class), "class", validate.CodeInvalid, i18n.Params{"values": models.TariffClasses})
+	v.Check(tariff.PricePerKWh > 0, "price_per_kwh", validate.CodeRequired, nil)
+	v.Check(tariff.MinVA > 0, "min_va", validate.CodeRequired, nil)
+	v.Check(tariff.MaxVA == nil || *tariff.MaxVA >= tariff.MinVA, "max_va", validate.CodeOutOfRange, nil)
+	v.Check(!tariff.EffectiveFrom.IsZero(), "effective_from", validate.CodeRequired, nil)
+	return v.Err()
+}
+
+func AddTariff(repo repository.TariffRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var tariff models.Tariff
+		if err := json.NewDecoder(r.Body).Decode(&tariff); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+
+		if err := validateTariff(&tariff); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.AddTariff(r.Context(), &tariff); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(tariff)
+	}
+}
+
+func GetTariffs(repo repository.TariffRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		class := strings.TrimSpace(r.URL.Query().Get("class"))
+		if class != "" && !models.ValidTariffClass(class) {
+			problem.Write(w, r, problem.InvalidParameter("class", validate.CodeInvalid, i18n.Params{"values": models.TariffClasses}))
+			return
+		}
+
+		tariffs, err := repo.GetTariffs(r.Context(), class)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(tariffs)
+	}
+}
+
+func GetByIdTariffs(repo repository.TariffRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := validateParamId(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		tariff, err := repo.GetByIdTariff(r.Context(), id)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(tariff)
+	}
+}
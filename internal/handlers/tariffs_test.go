@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddTariff_Success(t *testing.T) {
+	mockRepo := new(mocks.MockTariffRepository)
+	handler := AddTariff(mockRepo)
+
+	tariff := models.Tariff{
+		Class:         models.TariffR1_2200VA,
+		MinVA:         2200,
+		PricePerKWh:   1500,
+		EffectiveFrom: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	body, _ := json.Marshal(tariff)
+
+	mockRepo.On("AddTariff", mock.Anything, mock.AnythingOfType("*models.Tariff")).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tariffs/add", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddTariff_UnknownClass(t *testing.T) {
+	mockRepo := new(mocks.MockTariffRepository)
+	handler := AddTariff(mockRepo)
+
+	body, _ := json.Marshal(models.Tariff{Class: "B-2", MinVA: 1300, PricePerKWh: 1500, EffectiveFrom: time.Now()})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tariffs/add", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "AddTariff", mock.Anything, mock.Anything)
+}
+
+func TestGetTariffs_Success(t *testing.T) {
+	mockRepo := new(mocks.MockTariffRepository)
+	handler := GetTariffs(mockRepo)
+
+	tariffs := []models.Tariff{{ID: 1, Class: models.TariffR2, MinVA: 3500, PricePerKWh: 1699.53}}
+	mockRepo.On("GetTariffs", mock.Anything, models.TariffR2).Return(tariffs, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tariffs?class=R-2", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []models.Tariff
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, tariffs, resp)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetByIdTariffs_Success(t *testing.T) {
+	mockRepo := new(mocks.MockTariffRepository)
+	handler := GetByIdTariffs(mockRepo)
+
+	tariff := &models.Tariff{ID: 1, Class: models.TariffR1_450VA, MinVA: 450, PricePerKWh: 415}
+	mockRepo.On("GetByIdTariff", mock.Anything, "1").Return(tariff, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tariffs/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"daya-listrik-api/internal/billing"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var tokenNumberPattern = regexp.MustCompile(`^[0-9]{20}$`)
+
+// normalizeTokenNumber membuang spasi dan tanda hubung, sehingga token yang
+// ditulis "1234-5678-9012-3456-7890" tetap diterima.
+func normalizeTokenNumber(token string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(token)
+}
+
+func validateTokenPurchase(purchase *models.TokenPurchase) error {
+	var v validate.Validator
+	purchase.TokenNumber = normalizeTokenNumber(purchase.TokenNumber)
+	v.Check(tokenNumberPattern.MatchString(purchase.TokenNumber), "token_number", validate.CodeInvalid, nil)
+	v.Check(purchase.AmountPaid > 0, "amount_paid", validate.CodeRequired, nil)
+	v.Check(purchase.AdminFee >= 0 && purchase.AdminFee < purchase.AmountPaid, "admin_fee", validate.CodeOutOfRange, nil)
+	v.Check(purchase.KWhCredited > 0, "kwh_credited", validate.CodeRequired, nil)
+	v.Check(!purchase.PurchasedAt.After(time.Now()), "purchased_at", validate.CodeInFuture, nil)
+	return v.Err()
+}
+
+func AddTokenPurchase(repo repository.TokenPurchaseRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		var purchase models.TokenPurchase
+		if err := json.NewDecoder(r.Body).Decode(&purchase); err != nil {
+			problem.InvalidJSON(w, r, err)
+			return
+		}
+
+		if err := validateTokenPurchase(&purchase); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.AddTokenPurchase(r.Context(), household.ID, &purchase); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(purchase)
+	}
+}
+
+func GetTokenPurchases(repo repository.TokenPurchaseRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		purchases, err := repo.GetTokenPurchases(r.Context(), household.ID)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(purchases)
+	}
+}
+
+func GetByIdTokenPurchases(repo repository.TokenPurchaseRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		id, err := validateParamId(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		purchase, err := repo.GetByIdTokenPurchase(r.Context(), household.ID, id)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(purchase)
+	}
+}
+
+func DeleteTokenPurchases(repo repository.TokenPurchaseRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		id, err := validateParamId(r)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		if err := repo.DeleteTokenPurchase(r.Context(), household.ID, id); err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func GetTokenBalance(tokens repository.TokenPurchaseRepositoryInterface, records repository.EnergyRecordRepositoryInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		household, ok := requestHousehold(w, r)
+		if !ok {
+			return
+		}
+
+		totals, err := tokens.GetTokenTotals(r.Context(), household.ID)
+		if err != nil {
+			problem.Error(w, r, err)
+			return
+		}
+
+		now := time.Now()
+		var consumed, recent float64
+		burnStart := billing.BurnRateStart(totals.FirstPurchase, now)
+		if totals.PurchaseCount > 0 {
+			sinceFirst, err := records.SummarizeRecords(r.Context(), household.ID, totals.FirstPurchase, now)
+			if err != nil {
+				problem.Error(w, r, err)
+				return
+			}
+			window, err := records.SummarizeRecords(r.Context(), household.ID, burnStart, now)
+			if err != nil {
+				problem.Error(w, r, err)
+				return
+			}
+			consumed, recent = sinceFirst.EnergyKWh, window.EnergyKWh
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(billing.ProjectBalance(totals, consumed, recent, burnStart, now))
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository/mocks"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddTokenPurchase_Success(t *testing.T) {
+	mockRepo := new(mocks.MockTokenPurchaseRepository)
+	handler := AddTokenPurchase(mockRepo)
+
+	body, _ := json.Marshal(models.TokenPurchase{
+		TokenNumber: "1234-5678-9012-3456-7890",
+		AmountPaid:  102500,
+		AdminFee:    2500,
+		KWhCredited: 69.2,
+	})
+
+	mockRepo.On("AddTokenPurchase", mock.Anything, testHouseholdID, mock.MatchedBy(func(p *models.TokenPurchase) bool {
+		return p.TokenNumber == "12345678901234567890"
+	})).Return(nil)
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/tokens/add", bytes.NewReader(body)))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddTokenPurchase_InvalidToken(t *testing.T) {
+	mockRepo := new(mocks.MockTokenPurchaseRepository)
+	handler := AddTokenPurchase(mockRepo)
+
+	body, _ := json.Marshal(models.TokenPurchase{TokenNumber: "1234", AmountPaid: 20000, KWhCredited: 13})
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/tokens/add", bytes.NewReader(body)))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockRepo.AssertNotCalled(t, "AddTokenPurchase", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTokenPurchases_Success(t *testing.T) {
+	mockRepo := new(mocks.MockTokenPurchaseRepository)
+	handler := GetTokenPurchases(mockRepo)
+
+	mockRepo.On("GetTokenPurchases", mock.Anything, testHouseholdID).Return([]models.TokenPurchase{{ID: 1, TokenNumber: "12345678901234567890"}}, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/tokens", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteTokenPurchases_Success(t *testing.T) {
+	mockRepo := new(mocks.MockTokenPurchaseRepository)
+	handler := DeleteTokenPurchases(mockRepo)
+
+	mockRepo.On("DeleteTokenPurchase", mock.Anything, testHouseholdID, "1").Return(nil)
+
+	req := withUser(httptest.NewRequest(http.MethodDelete, "/api/tokens/1", nil))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTokenBalance_Success(t *testing.T) {
+	tokenRepo := new(mocks.MockTokenPurchaseRepository)
+	recordRepo := new(mocks.MockEnergyRecordRepository)
+	handler := GetTokenBalance(tokenRepo, recordRepo)
+
+	first := time.Now().AddDate(0, 0, -14)
+	tokenRepo.On("GetTokenTotals", mock.Anything, testHouseholdID).Return(&models.TokenTotals{
+		PurchaseCount: 1, KWhCredited: 100, FirstPurchase: first, LatestPurchase: first,
+	}, nil)
+	recordRepo.On("SummarizeRecords", mock.Anything, testHouseholdID, first, mock.AnythingOfType("time.Time")).
+		Return(&models.UsageSummary{EnergyKWh: 60}, nil).Once()
+	recordRepo.On("SummarizeRecords", mock.Anything, testHouseholdID, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return(&models.UsageSummary{EnergyKWh: 28}, nil).Once()
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/tokens/balance", nil))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.TokenBalance
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, 40.0, resp.KWhRemaining)
+	assert.InDelta(t, 4.0, resp.BurnRateKWhDay, 0.001)
+	assert.NotNil(t, resp.PredictedEmpty)
+	tokenRepo.AssertExpectations(t)
+	recordRepo.AssertExpectations(t)
+}
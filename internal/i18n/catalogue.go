@@ -0,0 +1,353 @@
+package i18n
+
+// catalogue berisi pesan per kode error. Kunci "<field>.<code>" dipakai untuk
+// pesan validasi yang khusus untuk satu field.
+var catalogue = map[string]map[string]string{
+	// Respons problem
+	"validation_failed": {
+		ID: "Input tidak valid. Pastikan semua nilai benar.",
+		EN: "input is not valid",
+	},
+	"invalid_parameter": {
+		ID: "Parameter tidak valid",
+		EN: "invalid parameter",
+	},
+	"invalid_json": {
+		ID: "Body request bukan JSON yang valid",
+		EN: "request body is not valid JSON",
+	},
+	"body_too_large": {
+		ID: "Body request tidak boleh melebihi {max} byte",
+		EN: "request body must not exceed {max} bytes",
+	},
+	"internal_error": {
+		ID: "Terjadi kesalahan tak terduga",
+		EN: "an unexpected error occurred",
+	},
+	"request_canceled": {
+		ID: "Request dibatalkan oleh klien",
+		EN: "request canceled by client",
+	},
+	"database_unavailable": {
+		ID: "Database sedang tidak tersedia, silakan coba lagi",
+		EN: "database is temporarily unavailable, please retry",
+	},
+	"unauthorized": {
+		ID: "Autentikasi diperlukan",
+		EN: "authentication required",
+	},
+	"invalid_token": {
+		ID: "Token tidak valid atau sudah kedaluwarsa",
+		EN: "invalid or expired token",
+	},
+	"invalid_credentials": {
+		ID: "Email atau password salah",
+		EN: "invalid email or password",
+	},
+	"invalid_api_key": {
+		ID: "API key tidak valid, kedaluwarsa atau sudah dicabut",
+		EN: "invalid, expired or revoked api key",
+	},
+	"forbidden": {
+		ID: "Tidak memiliki izin {permission}",
+		EN: "missing permission {permission}",
+	},
+	"household_required": {
+		ID: "Buat atau gabung ke household terlebih dahulu",
+		EN: "create or join a household first",
+	},
+	"household_header_required": {
+		ID: "Header {header} wajib diisi bila Anda anggota beberapa household",
+		EN: "{header} header is required when you belong to several households",
+	},
+	"invalid_household_header": {
+		ID: "Header {header} tidak valid",
+		EN: "invalid {header} header",
+	},
+	"not_household_member": {
+		ID: "Anda bukan anggota household ini",
+		EN: "not a member of this household",
+	},
+	"not_enough_meter_readings": {
+		ID: "Dibutuhkan minimal dua pembacaan meter",
+		EN: "at least two meter readings are required",
+	},
+	"invalid_reading_order": {
+		ID: "Pembacaan akhir harus lebih baru dari pembacaan awal",
+		EN: "to reading must be later than from reading",
+	},
+	"reconcile_failed": {
+		ID: "Angka meter turun dari {from} ke {to} kWh",
+		EN: "meter reading decreased from {from} to {to} kWh",
+	},
+
+	// Error repository
+	"device_not_found": {
+		ID: "Perangkat dengan ID {id} tidak ditemukan",
+		EN: "device with ID {id} not found",
+	},
+	"energy_record_not_found": {
+		ID: "Catatan energi dengan ID {id} tidak ditemukan",
+		EN: "energy record with ID {id} not found",
+	},
+	"meter_reading_not_found": {
+		ID: "Pembacaan meter dengan ID {id} tidak ditemukan",
+		EN: "meter reading with ID {id} not found",
+	},
+	"token_purchase_not_found": {
+		ID: "Pembelian token dengan ID {id} tidak ditemukan",
+		EN: "token purchase with ID {id} not found",
+	},
+	"tariff_not_found": {
+		ID: "Tarif dengan ID {id} tidak ditemukan",
+		EN: "tariff with ID {id} not found",
+	},
+	"household_not_found": {
+		ID: "Household dengan ID {id} tidak ditemukan",
+		EN: "household with ID {id} not found",
+	},
+	"api_key_not_found": {
+		ID: "API key dengan ID {id} tidak ditemukan",
+		EN: "api key with ID {id} not found",
+	},
+	"user_not_found": {
+		ID: "Pengguna tidak ditemukan",
+		EN: "user not found",
+	},
+	"household_member_not_found": {
+		ID: "Bukan anggota household ini",
+		EN: "not a member of this household",
+	},
+	"tariff_not_in_effect": {
+		ID: "Tidak ada tarif {class} yang berlaku pada {date}",
+		EN: "no {class} tariff in effect on {date}",
+	},
+	"email_taken": {
+		ID: "Email sudah terdaftar",
+		EN: "email already registered",
+	},
+	"device_name_taken": {
+		ID: "Nama perangkat sudah dipakai",
+		EN: "device name already exists",
+	},
+	"last_owner": {
+		ID: "Household harus memiliki minimal satu pemilik",
+		EN: "household must keep at least one owner",
+	},
+	"already_member": {
+		ID: "Anda sudah menjadi anggota household ini",
+		EN: "already a member of this household",
+	},
+	"invalid_invitation": {
+		ID: "Kode undangan tidak valid atau sudah kedaluwarsa",
+		EN: "invalid or expired invitation code",
+	},
+
+	// Peringatan respons
+	"capacity_overload": {
+		ID: "Beban serentak mencapai {peak} W antara {start} dan {end}, melebihi batas {limit} W ({va} VA x {power_factor})",
+		EN: "concurrent load reaches {peak} W between {start} and {end}, above the {limit} W limit ({va} VA x {power_factor})",
+	},
+	"over_tracked": {
+		ID: "Record perangkat melebihi pemakaian menurut meter; periksa durasi dan daya perangkat",
+		EN: "device records exceed metered consumption; check durations and wattages",
+	},
+
+	// Item tagihan
+	"bill_energy_charge": {
+		ID: "Biaya pemakaian energi",
+		EN: "Energy charge",
+	},
+	"bill_minimum_charge": {
+		ID: "Rekening minimum ({hours} jam nyala)",
+		EN: "Minimum charge ({hours} hours of use)",
+	},
+	"bill_ppj": {
+		ID: "Pajak Penerangan Jalan",
+		EN: "Street lighting tax (PPJ)",
+	},
+	"bill_stamp_duty": {
+		ID: "Bea meterai",
+		EN: "Stamp duty",
+	},
+
+	// Kode field umum
+	"required": {
+		ID: "{field} wajib diisi",
+		EN: "{field} is required",
+	},
+	"invalid": {
+		ID: "{field} tidak valid",
+		EN: "{field} is invalid",
+	},
+	"out_of_range": {
+		ID: "{field} harus antara {min} dan {max}",
+		EN: "{field} must be between {min} and {max}",
+	},
+	"too_long": {
+		ID: "{field} tidak boleh lebih dari {max} karakter",
+		EN: "{field} must not exceed {max} characters",
+	},
+	"too_short": {
+		ID: "{field} minimal {min} karakter",
+		EN: "{field} must be at least {min} characters",
+	},
+	"in_future": {
+		ID: "{field} tidak boleh di masa depan",
+		EN: "{field} must not be in the future",
+	},
+	"not_found": {
+		ID: "{field} {value} tidak ditemukan",
+		EN: "{field} {value} not found",
+	},
+	"unknown_field": {
+		ID: "Field {field} tidak dikenal",
+		EN: "unknown field {field}",
+	},
+	"invalid_type": {
+		ID: "{field} harus bertipe {type}",
+		EN: "{field} must be of type {type}",
+	},
+	"conflict": {
+		ID: "{field} bertentangan dengan parameter lain",
+		EN: "{field} conflicts with another parameter",
+	},
+
+	// Pesan khusus field
+	"usage.required": {
+		ID: "usage wajib diisi dan harus lebih dari 0",
+		EN: "usage is required and must be greater than 0",
+	},
+	"usage.out_of_range": {
+		ID: "usage tidak boleh melebihi {max} W",
+		EN: "usage must not exceed {max} W",
+	},
+	"duration.required": {
+		ID: "duration wajib diisi dan harus lebih dari 0",
+		EN: "duration is required and must be greater than 0",
+	},
+	"duration.out_of_range": {
+		ID: "duration tidak boleh melebihi {max} jam",
+		EN: "duration must not exceed {max} hours",
+	},
+	"device.required": {
+		ID: "device atau device_id wajib diisi",
+		EN: "device or device_id is required",
+	},
+	"device_id.not_found": {
+		ID: "Perangkat dengan ID {value} tidak ditemukan",
+		EN: "device with ID {value} not found",
+	},
+	"rated_wattage.out_of_range": {
+		ID: "rated_wattage harus antara 0 dan {max} W untuk kategori ini",
+		EN: "rated_wattage must be between 0 and {max} W for this category",
+	},
+	"amount_paid.required": {
+		ID: "amount_paid wajib diisi dan harus lebih dari 0",
+		EN: "amount_paid is required and must be greater than 0",
+	},
+	"kwh_credited.required": {
+		ID: "kwh_credited wajib diisi dan harus lebih dari 0",
+		EN: "kwh_credited is required and must be greater than 0",
+	},
+	"admin_fee.out_of_range": {
+		ID: "admin_fee harus antara 0 dan amount_paid",
+		EN: "admin_fee must be between 0 and amount_paid",
+	},
+	"token_number.invalid": {
+		ID: "token_number harus 20 digit",
+		EN: "token_number must be 20 digits",
+	},
+	"price_per_kwh.required": {
+		ID: "price_per_kwh wajib diisi dan harus lebih dari 0",
+		EN: "price_per_kwh is required and must be greater than 0",
+	},
+	"min_va.required": {
+		ID: "min_va wajib diisi dan harus lebih dari 0",
+		EN: "min_va is required and must be greater than 0",
+	},
+	"max_va.out_of_range": {
+		ID: "max_va tidak boleh kurang dari min_va",
+		EN: "max_va must not be less than min_va",
+	},
+	"class.invalid": {
+		ID: "class harus salah satu dari {values}",
+		EN: "class must be one of {values}",
+	},
+	"tariff_class.invalid": {
+		ID: "tariff_class harus salah satu dari {values}",
+		EN: "tariff_class must be one of {values}",
+	},
+	"cumulative_kwh.out_of_range": {
+		ID: "cumulative_kwh tidak boleh negatif",
+		EN: "cumulative_kwh must not be negative",
+	},
+	"contracted_va.out_of_range": {
+		ID: "contracted_va tidak boleh negatif",
+		EN: "contracted_va must not be negative",
+	},
+	"timezone.invalid": {
+		ID: "Zona waktu {value} tidak dikenal",
+		EN: "unknown time zone {value}",
+	},
+	"scopes.invalid": {
+		ID: "Scope {value} tidak dikenal, pilih salah satu dari {values}",
+		EN: "unknown scope {value}, expected one of {values}",
+	},
+	"expires_at.invalid": {
+		ID: "expires_at harus di masa depan",
+		EN: "expires_at must be in the future",
+	},
+	"role.invalid": {
+		ID: "role harus salah satu dari {values}",
+		EN: "role must be one of {values}",
+	},
+	"email.invalid": {
+		ID: "Email yang valid wajib diisi",
+		EN: "a valid email is required",
+	},
+	"month.invalid": {
+		ID: "month tidak valid, format yang diharapkan YYYY-MM",
+		EN: "invalid month, expected format YYYY-MM",
+	},
+	"from.invalid": {
+		ID: "from tidak valid, gunakan RFC3339 atau YYYY-MM-DD",
+		EN: "invalid from, expected RFC3339 or YYYY-MM-DD",
+	},
+	"from.out_of_range": {
+		ID: "Rentang terlalu besar, maksimal {max} bucket",
+		EN: "range too large, at most {max} buckets",
+	},
+	"to.invalid": {
+		ID: "to tidak valid, gunakan RFC3339 atau YYYY-MM-DD",
+		EN: "invalid to, expected RFC3339 or YYYY-MM-DD",
+	},
+	"to.out_of_range": {
+		ID: "to harus lebih akhir dari from",
+		EN: "to must be later than from",
+	},
+	"from_id.invalid": {
+		ID: "from_id harus berupa ID yang valid",
+		EN: "from_id must be a valid ID",
+	},
+	"to_id.invalid": {
+		ID: "to_id harus berupa ID yang valid",
+		EN: "to_id must be a valid ID",
+	},
+	"sort.invalid": {
+		ID: "Kolom sort {value} tidak dikenal",
+		EN: "invalid sort column {value}",
+	},
+	"bucket.invalid": {
+		ID: "bucket harus salah satu dari {values}",
+		EN: "bucket must be one of {values}",
+	},
+	"group_by.invalid": {
+		ID: "group_by harus salah satu dari {values}",
+		EN: "group_by must be one of {values}",
+	},
+	"cursor.conflict": {
+		ID: "Gunakan offset atau cursor, tidak keduanya",
+		EN: "use either offset or cursor, not both",
+	},
+}
@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bahasa yang didukung. Kode mengikuti subtag utama Accept-Language.
+const (
+	ID = "id"
+	EN = "en"
+)
+
+// Default dipakai bila Accept-Language kosong atau tidak ada bahasa yang
+// didukung.
+const Default = ID
+
+// Params adalah nilai untuk placeholder {nama} di pesan katalog.
+type Params map[string]any
+
+// Negotiate memilih bahasa dari header Accept-Language berdasarkan bobot q,
+// mis. "en-US,en;q=0.9,id;q=0.8" menghasilkan "en".
+func Negotiate(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang != ID && lang != EN {
+			continue
+		}
+		q := 1.0
+		if raw, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// Message merender pesan code dalam bahasa lang. ok bernilai false bila code
+// tidak ada di katalog.
+func Message(lang, code string, params Params) (string, bool) {
+	texts, ok := catalogue[code]
+	if !ok {
+		return "", false
+	}
+	text, ok := texts[lang]
+	if !ok {
+		text = texts[Default]
+	}
+	return render(text, params), true
+}
+
+// FieldMessage merender pesan untuk field input. Pesan khusus "<field>.<code>"
+// dipakai bila ada, selain itu pesan umum code dengan {field} terisi.
+func FieldMessage(lang, field, code string, params Params) string {
+	withField := Params{"field": field}
+	for k, v := range params {
+		withField[k] = v
+	}
+	if msg, ok := Message(lang, field+"."+code, withField); ok {
+		return msg
+	}
+	if msg, ok := Message(lang, code, withField); ok {
+		return msg
+	}
+	return field + ": " + code
+}
+
+// Text sama dengan Message, tetapi mengembalikan code bila tidak ada di katalog.
+func Text(lang, code string, params Params) string {
+	if msg, ok := Message(lang, code, params); ok {
+		return msg
+	}
+	return code
+}
+
+func render(text string, params Params) string {
+	if len(params) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", format(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func format(v any) string {
+	switch v := v.(type) {
+	case []string:
+		return strings.Join(v, ", ")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
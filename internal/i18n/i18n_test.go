@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                          ID,
+		"en":                        EN,
+		"en-US,en;q=0.9":            EN,
+		"id-ID,id;q=0.9,en;q=0.8":   ID,
+		"fr-FR,en;q=0.5,id;q=0.4":   EN,
+		"en;q=0.2,id;q=0.8":         ID,
+		"fr, de":                    ID,
+		"en;q=0":                    ID,
+		"EN-gb":                     EN,
+		"en;q=abc, id;q=0.1":        ID,
+		"*":                         ID,
+		"  en-AU ; q=0.7 , ja;q=1 ": EN,
+	}
+	for header, want := range cases {
+		assert.Equal(t, want, Negotiate(header), header)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	msg, ok := Message(EN, "body_too_large", Params{"max": 16384})
+	assert.True(t, ok)
+	assert.Equal(t, "request body must not exceed 16384 bytes", msg)
+
+	msg, _ = Message(ID, "role.invalid", Params{"values": []string{"owner", "viewer"}})
+	assert.Equal(t, "role harus salah satu dari owner, viewer", msg)
+
+	_, ok = Message(EN, "no_such_code", nil)
+	assert.False(t, ok)
+	assert.Equal(t, "no_such_code", Text(EN, "no_such_code", nil))
+}
+
+func TestFieldMessage(t *testing.T) {
+	// Pesan khusus field didahulukan dari pesan umum kode.
+	assert.Equal(t, "usage is required and must be greater than 0", FieldMessage(EN, "usage", "required", nil))
+	assert.Equal(t, "name wajib diisi", FieldMessage(ID, "name", "required", nil))
+	assert.Equal(t, "duration must not exceed 24 hours", FieldMessage(EN, "duration", "out_of_range", Params{"max": 24.0}))
+	assert.Equal(t, "watts: mystery", FieldMessage(EN, "watts", "mystery", nil))
+}
+
+var placeholder = regexp.MustCompile(`\{\w+\}`)
+
+func TestCatalogue_Complete(t *testing.T) {
+	for code, texts := range catalogue {
+		id, en := texts[ID], texts[EN]
+		if assert.NotEmpty(t, id, code) && assert.NotEmpty(t, en, code) {
+			idParams := placeholder.FindAllString(id, -1)
+			enParams := placeholder.FindAllString(en, -1)
+			slices.Sort(idParams)
+			slices.Sort(enParams)
+			assert.Equal(t, enParams, idParams, "placeholders of %s", code)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/validate"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scope API key.
+const (
+	ScopeRecordsWrite = "records:write"
+)
+
+var apiKeyScopes = []string{ScopeRecordsWrite}
+
+func ValidScope(scope string) bool {
+	return slices.Contains(apiKeyScopes, scope)
+}
+
+// APIKey dipakai perangkat tanpa login interaktif, misalnya smart plug atau
+// logger Raspberry Pi, untuk mengirim data ke satu rumah tangga.
+type APIKey struct {
+	ID          int    `json:"id"`
+	HouseholdID int    `json:"household_id"`
+	Label       string `json:"label"`
+	// Prefix adalah awal key yang boleh ditampilkan untuk mengenali key.
+	Prefix string `json:"prefix"`
+	// Key hanya terisi pada respons pembuatan key.
+	Key        string     `json:"key,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  int        `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Validate merapikan label dan scope. Tanpa scope, key hanya boleh menulis
+// record.
+func (k *APIKey) Validate(now time.Time) error {
+	var v validate.Validator
+	k.Label = strings.TrimSpace(k.Label)
+	v.Required("label", k.Label)
+	v.MaxLength("label", k.Label, MaxNameLength)
+	if len(k.Scopes) == 0 {
+		k.Scopes = []string{ScopeRecordsWrite}
+	}
+	for _, scope := range k.Scopes {
+		v.Check(ValidScope(scope), "scopes", validate.CodeInvalid, i18n.Params{"value": scope, "values": apiKeyScopes})
+	}
+	v.Check(k.ExpiresAt == nil || k.ExpiresAt.After(now), "expires_at", validate.CodeInvalid, nil)
+	return v.Err()
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
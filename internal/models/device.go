@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type Device struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	RatedWattage float64   `json:"rated_wattage"`
+	Category     string    `json:"category"`
+	Room         string    `json:"room"`
+	Notes        string    `json:"notes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// MaxNameLength sama dengan kolom VARCHAR(100) untuk nama perangkat, rumah
+// tangga, ruangan dan label API key.
+const MaxNameLength = 100
+
+// MaxDeviceWattage adalah daya maksimum satu perangkat rumah tangga, dipakai
+// untuk kategori yang tidak dikenal atau kosong.
+const MaxDeviceWattage = 10000
+
+// deviceWattageCeilings adalah daya maksimum yang masih masuk akal untuk satu
+// perangkat di tiap kategori. Nilai di atasnya hampir pasti salah ketik,
+// misalnya Wh yang diisi sebagai W.
+var deviceWattageCeilings = map[string]float64{
+	"lighting":      500,
+	"entertainment": 1500,
+	"computing":     2000,
+	"pump":          2500,
+	"laundry":       3000,
+	"kitchen":       3500,
+	"heating":       3500,
+	"cooling":       5000,
+}
+
+// WattageCeiling mengembalikan daya maksimum perangkat untuk kategori
+// tersebut (tidak peka huruf besar/kecil).
+func WattageCeiling(category string) float64 {
+	if ceiling, ok := deviceWattageCeilings[strings.ToLower(strings.TrimSpace(category))]; ok {
+		return ceiling
+	}
+	return MaxDeviceWattage
+}
@@ -0,0 +1,19 @@
+package models
+
+// EnergyWh menghitung energi (Wh) dari daya (watt) dan durasi pemakaian (jam).
+// Semua fitur yang butuh angka energi (biaya, tagihan, statistik) memakai
+// fungsi ini agar hasilnya konsisten.
+func EnergyWh(usage, duration float64) float64 {
+	return usage * duration
+}
+
+// WhToKWh mengonversi watt-hour ke kilowatt-hour.
+func WhToKWh(wh float64) float64 {
+	return wh / 1000
+}
+
+// ComputeEnergy mengisi field turunan energy_wh dan energy_kwh.
+func (r *EnergyRecord) ComputeEnergy() {
+	r.EnergyWh = EnergyWh(r.Usage, r.Duration)
+	r.EnergyKWh = WhToKWh(r.EnergyWh)
+}
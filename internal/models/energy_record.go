@@ -2,10 +2,20 @@ package models
 
 import "time"
 
+// MaxRecordDuration adalah durasi maksimum satu record dalam jam. Record yang
+// lebih panjang harus dipecah per hari agar tagihan dan statistik harian
+// tetap benar.
+const MaxRecordDuration = 24
+
 type EnergyRecord struct {
-	ID        int       `json:"id"`
-	Date      time.Time `json:"date"`
-	Usage     float64   `json:"usage"`
-	Duration  float64   `json:"duration"`
-	Device    string    `json:"device"`
-}
\ No newline at end of file
+	ID        int        `json:"id"`
+	Date      time.Time  `json:"date"`
+	Usage     float64    `json:"usage"`
+	Duration  float64    `json:"duration"`
+	Device    string     `json:"device"`
+	DeviceID  *int       `json:"device_id,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	EnergyWh  float64    `json:"energy_wh"`
+	EnergyKWh float64    `json:"energy_kwh"`
+	CostIDR   float64    `json:"cost_idr"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeEnergy(t *testing.T) {
+	record := EnergyRecord{Usage: 350, Duration: 8}
+	record.ComputeEnergy()
+
+	assert.Equal(t, 2800.0, record.EnergyWh)
+	assert.Equal(t, 2.8, record.EnergyKWh)
+}
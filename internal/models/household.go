@@ -0,0 +1,137 @@
+package models
+
+import (
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/validate"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Peran anggota rumah tangga.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// Roles adalah semua peran anggota, dari yang paling luas izinnya.
+var Roles = []string{RoleOwner, RoleEditor, RoleViewer}
+
+// DefaultHouseholdName dipakai untuk rumah tangga yang dibuat otomatis saat
+// registrasi.
+const DefaultHouseholdName = "Rumah"
+
+func ValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// Household adalah satu rumah/meter PLN. Semua record, perangkat, token dan
+// pembacaan meter dimiliki oleh satu rumah tangga.
+type Household struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	TariffClass string `json:"tariff_class"`
+	// ContractedVA adalah daya tersambung; 0 berarti ikut daya golongan tarif.
+	ContractedVA int    `json:"contracted_va"`
+	Timezone     string `json:"timezone"`
+	// BillingDay adalah tanggal awal periode tagihan (1-28).
+	BillingDay int       `json:"billing_day"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// EffectiveVA mengembalikan daya tersambung, atau daya nominal golongan tarif
+// bila belum diisi.
+func (h *Household) EffectiveVA() int {
+	if h.ContractedVA > 0 {
+		return h.ContractedVA
+	}
+	return TariffClassVA(h.TariffClass)
+}
+
+// Location memuat zona waktu rumah tangga. Zona yang tidak dikenal
+// diperlakukan sebagai DefaultTimezone.
+func (h *Household) Location() *time.Location {
+	if loc, err := LoadTimezone(h.Timezone); err == nil {
+		return loc
+	}
+	if loc, err := LoadTimezone(DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// ApplyDefaults mengisi field yang kosong dari defaults.
+func (h *Household) ApplyDefaults(defaults Household) {
+	if strings.TrimSpace(h.Name) == "" {
+		h.Name = defaults.Name
+	}
+	if h.TariffClass == "" {
+		h.TariffClass = defaults.TariffClass
+	}
+	if h.ContractedVA == 0 {
+		h.ContractedVA = defaults.ContractedVA
+	}
+	if h.Timezone == "" {
+		h.Timezone = defaults.Timezone
+	}
+	if h.BillingDay == 0 {
+		h.BillingDay = defaults.BillingDay
+	}
+}
+
+// Validate mengecek field rumah tangga dan menormalkan timezone ke nama IANA.
+// Semua field yang gagal dikembalikan sekaligus sebagai validate.Errors.
+func (h *Household) Validate() error {
+	var v validate.Validator
+	h.Name = strings.TrimSpace(h.Name)
+	h.Address = strings.TrimSpace(h.Address)
+	v.Required("name", h.Name)
+	v.MaxLength("name", h.Name, MaxNameLength)
+	v.Check(ValidTariffClass(h.TariffClass), "tariff_class", validate.CodeInvalid, i18n.Params{"values": TariffClasses})
+	v.Check(h.ContractedVA >= 0, "contracted_va", validate.CodeOutOfRange, nil)
+	if loc, err := LoadTimezone(h.Timezone); err != nil {
+		v.Add("timezone", validate.CodeInvalid, i18n.Params{"value": h.Timezone})
+	} else {
+		h.Timezone = loc.String()
+	}
+	v.Check(h.BillingDay >= 1 && h.BillingDay <= 28, "billing_day", validate.CodeOutOfRange, i18n.Params{"min": 1, "max": 28})
+	return v.Err()
+}
+
+// BillingPeriod mengembalikan periode tagihan [from, to) yang dimulai pada
+// billing day bulan month, menurut jam dinding rumah tangga.
+func (h *Household) BillingPeriod(year int, month time.Month) (time.Time, time.Time) {
+	day := h.BillingDay
+	if day < 1 {
+		day = 1
+	}
+	from := time.Date(year, month, day, 0, 0, 0, 0, h.Location())
+	return from, from.AddDate(0, 1, 0)
+}
+
+// Membership adalah keanggotaan user pada satu rumah tangga.
+type Membership struct {
+	Household Household `json:"household"`
+	Role      string    `json:"role"`
+}
+
+type HouseholdMember struct {
+	UserID   int       `json:"user_id"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// HouseholdInvitation adalah undangan sekali pakai. Code hanya diisi saat
+// undangan dibuat; yang disimpan hanya hash-nya.
+type HouseholdInvitation struct {
+	ID          int       `json:"id"`
+	HouseholdID int       `json:"household_id"`
+	Code        string    `json:"code,omitempty"`
+	Role        string    `json:"role"`
+	CreatedBy   int       `json:"created_by"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
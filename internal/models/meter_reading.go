@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// MeterReading adalah angka stand kWh meter pada suatu waktu.
+type MeterReading struct {
+	ID            int       `json:"id"`
+	ReadAt        time.Time `json:"read_at"`
+	CumulativeKWh float64   `json:"cumulative_kwh"`
+	PhotoRef      string    `json:"photo_ref,omitempty"`
+}
+
+// Reconciliation membandingkan pemakaian menurut meter dengan jumlah record
+// perangkat pada periode di antara dua pembacaan meter. NoteCode adalah kode
+// catatan yang stabil untuk klien; OverTrackedNote adalah teksnya dalam bahasa
+// request.
+type Reconciliation struct {
+	From            MeterReading `json:"from"`
+	To              MeterReading `json:"to"`
+	MeteredKWh      float64      `json:"metered_kwh"`
+	TrackedKWh      float64      `json:"tracked_kwh"`
+	RecordCount     int          `json:"record_count"`
+	UntrackedKWh    float64      `json:"untracked_kwh"`
+	UntrackedPct    float64      `json:"untracked_pct"`
+	NoteCode        string       `json:"note_code,omitempty"`
+	OverTrackedNote string       `json:"note,omitempty"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Golongan tarif rumah tangga PLN.
+const (
+	TariffR1_450VA    = "R-1/450VA"
+	TariffR1_900VA    = "R-1/900VA"
+	TariffR1_900VARTM = "R-1/900VA-RTM"
+	TariffR1_1300VA   = "R-1/1300VA"
+	TariffR1_2200VA   = "R-1/2200VA"
+	TariffR2          = "R-2"
+	TariffR3          = "R-3"
+
+	DefaultTariffClass = TariffR1_1300VA
+)
+
+var TariffClasses = []string{
+	TariffR1_450VA,
+	TariffR1_900VA,
+	TariffR1_900VARTM,
+	TariffR1_1300VA,
+	TariffR1_2200VA,
+	TariffR2,
+	TariffR3,
+}
+
+// TariffClassVA mengembalikan daya tersambung terkecil (VA) untuk golongan
+// tarif, dipakai bila daya rumah tangga tidak dikonfigurasi.
+func TariffClassVA(class string) int {
+	switch class {
+	case TariffR1_450VA:
+		return 450
+	case TariffR1_900VA, TariffR1_900VARTM:
+		return 900
+	case TariffR1_1300VA:
+		return 1300
+	case TariffR1_2200VA:
+		return 2200
+	case TariffR2:
+		return 3500
+	case TariffR3:
+		return 6600
+	}
+	return 0
+}
+
+func ValidTariffClass(class string) bool {
+	for _, c := range TariffClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+type Tariff struct {
+	ID            int       `json:"id"`
+	Class         string    `json:"class"`
+	MinVA         int       `json:"min_va"`
+	MaxVA         *int      `json:"max_va,omitempty"`
+	PricePerKWh   float64   `json:"price_per_kwh"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+// Cost menghitung biaya (Rupiah, dibulatkan ke sen) untuk energi dalam kWh.
+func Cost(kwh, pricePerKWh float64) float64 {
+	return RoundIDR(kwh * pricePerKWh)
+}
+
+// RoundIDR membulatkan nilai Rupiah ke dua desimal.
+func RoundIDR(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// ComputeCost mengisi cost_idr dari energi record dan harga tarif yang
+// berlaku pada tanggal record. Panggil setelah ComputeEnergy.
+func (r *EnergyRecord) ComputeCost(pricePerKWh float64) {
+	r.CostIDR = Cost(r.EnergyKWh, pricePerKWh)
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTimezone adalah zona waktu rumah tangga bila tidak dikonfigurasi.
+const DefaultTimezone = "Asia/Jakarta"
+
+// timezoneAliases memetakan singkatan zona waktu Indonesia ke nama IANA.
+var timezoneAliases = map[string]string{
+	"WIB":  "Asia/Jakarta",
+	"WITA": "Asia/Makassar",
+	"WIT":  "Asia/Jayapura",
+}
+
+// LoadTimezone memuat zona waktu dari nama IANA atau singkatan WIB/WITA/WIT.
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if alias, ok := timezoneAliases[strings.ToUpper(name)]; ok {
+		name = alias
+	}
+	if name == "" {
+		return nil, fmt.Errorf("timezone is required")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
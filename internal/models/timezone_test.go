@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	for name, want := range map[string]string{
+		"WIB":           "Asia/Jakarta",
+		"wita":          "Asia/Makassar",
+		" WIT ":         "Asia/Jayapura",
+		"Asia/Makassar": "Asia/Makassar",
+	} {
+		loc, err := LoadTimezone(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, loc.String())
+	}
+
+	for _, name := range []string{"", "Mars/Olympus"} {
+		_, err := LoadTimezone(name)
+		assert.Error(t, err, name)
+	}
+}
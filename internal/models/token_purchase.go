@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// TokenPurchase adalah pembelian token listrik prabayar.
+type TokenPurchase struct {
+	ID          int       `json:"id"`
+	TokenNumber string    `json:"token_number"`
+	AmountPaid  float64   `json:"amount_paid"`
+	AdminFee    float64   `json:"admin_fee"`
+	KWhCredited float64   `json:"kwh_credited"`
+	PurchasedAt time.Time `json:"purchased_at"`
+}
+
+// TokenTotals adalah ringkasan semua pembelian token.
+type TokenTotals struct {
+	PurchaseCount  int
+	KWhCredited    float64
+	FirstPurchase  time.Time
+	LatestPurchase time.Time
+}
+
+// TokenBalance adalah sisa kWh prabayar dan perkiraan kapan habis.
+type TokenBalance struct {
+	KWhCredited     float64    `json:"kwh_credited"`
+	KWhConsumed     float64    `json:"kwh_consumed"`
+	KWhRemaining    float64    `json:"kwh_remaining"`
+	BurnRateKWhDay  float64    `json:"burn_rate_kwh_per_day"`
+	DaysRemaining   *float64   `json:"days_remaining,omitempty"`
+	PredictedEmpty  *time.Time `json:"predicted_empty_at,omitempty"`
+	LastPurchasedAt *time.Time `json:"last_purchased_at,omitempty"`
+	CalculatedAt    time.Time  `json:"calculated_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// UsageSummary adalah total pemakaian record pada rentang [From, To).
+type UsageSummary struct {
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	RecordCount int       `json:"record_count"`
+	EnergyKWh   float64   `json:"energy_kwh"`
+	CostIDR     float64   `json:"cost_idr"`
+}
+
+// Ukuran bucket agregasi pemakaian.
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// Buckets adalah semua ukuran bucket yang didukung.
+var Buckets = []string{BucketDay, BucketWeek, BucketMonth}
+
+// ValidBucket mengecek apakah ukuran bucket dikenal.
+func ValidBucket(bucket string) bool {
+	return slices.Contains(Buckets, bucket)
+}
+
+// UsageBucket adalah total pemakaian pada satu bucket waktu, opsional per
+// perangkat. Start adalah awal bucket menurut jam dinding zona waktu rumah.
+type UsageBucket struct {
+	Start       time.Time `json:"start"`
+	Device      string    `json:"device,omitempty"`
+	RecordCount int       `json:"record_count"`
+	EnergyKWh   float64   `json:"energy_kwh"`
+	CostIDR     float64   `json:"cost_idr"`
+}
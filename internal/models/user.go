@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type User struct {
+	ID           int    `json:"id"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"-"`
+	// IsAdmin memberi izin tariffs:admin.
+	IsAdmin   bool      `json:"is_admin"`
+	CreatedAt time.Time `json:"created_at"`
+}
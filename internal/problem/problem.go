@@ -0,0 +1,173 @@
+package problem
+
+import (
+	"context"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ContentType adalah media type respons error (RFC 7807).
+const ContentType = "application/problem+json"
+
+// TypePrefix adalah awalan URI type; akhirannya sama dengan Code.
+const TypePrefix = "urn:daya-listrik:problem:"
+
+// StatusClientClosedRequest dipakai bila klien memutus koneksi sebelum
+// respons selesai (kode nginx, tidak ada di net/http).
+const StatusClientClosedRequest = 499
+
+// Problem adalah body respons error. Code stabil dan aman dipakai klien untuk
+// membedakan error, sedangkan Detail hanya untuk dibaca manusia dan ditulis
+// dalam bahasa hasil negosiasi Accept-Language.
+type Problem struct {
+	Type     string                  `json:"type"`
+	Title    string                  `json:"title"`
+	Status   int                     `json:"status"`
+	Detail   string                  `json:"detail,omitempty"`
+	Instance string                  `json:"instance,omitempty"`
+	Code     string                  `json:"code"`
+	Errors   []repository.FieldError `json:"errors,omitempty"`
+	// Permission diisi pada respons 403 karena izin yang kurang.
+	Permission string `json:"permission,omitempty"`
+	// Params mengisi placeholder pesan Code di katalog i18n.
+	Params i18n.Params `json:"-"`
+}
+
+// New membuat Problem dengan Detail berbahasa Inggris dari katalog i18n.
+// Problem juga sebuah error sehingga fungsi validasi bisa mengembalikannya
+// langsung untuk ditulis dengan Error.
+func New(status int, code string, params i18n.Params, fields ...repository.FieldError) *Problem {
+	title := http.StatusText(status)
+	if status == StatusClientClosedRequest {
+		title = "Client Closed Request"
+	}
+	p := &Problem{
+		Type:   TypePrefix + code,
+		Title:  title,
+		Status: status,
+		Code:   code,
+		Errors: fields,
+		Params: params,
+	}
+	p.Detail = p.detail(i18n.EN)
+	return p
+}
+
+// InvalidParameter membuat Problem 400 untuk query atau path parameter yang
+// tidak valid.
+func InvalidParameter(field, code string, params i18n.Params) *Problem {
+	return New(http.StatusBadRequest, "invalid_parameter", nil, validate.NewFieldError(field, code, params))
+}
+
+func (p *Problem) Error() string {
+	return p.Detail
+}
+
+// detail merender Detail dalam bahasa lang: pesan field yang gagal bila ada,
+// selain itu pesan Code di katalog.
+func (p *Problem) detail(lang string) string {
+	if len(p.Errors) > 0 {
+		messages := make([]string, len(p.Errors))
+		for i, f := range p.Errors {
+			messages[i] = f.Localize(lang).Message
+		}
+		return strings.Join(messages, "; ")
+	}
+	if msg, ok := i18n.Message(lang, p.Code, p.Params); ok {
+		return msg
+	}
+	return p.Detail
+}
+
+// Write menulis p sebagai application/problem+json dalam bahasa dari header
+// Accept-Language request.
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	lang := i18n.Default
+	localized := *p
+	if r != nil {
+		lang = i18n.Negotiate(r.Header.Get("Accept-Language"))
+		if localized.Instance == "" {
+			localized.Instance = r.URL.Path
+		}
+	}
+	localized.Detail = p.detail(lang)
+	localized.Errors = validate.Errors(p.Errors).Localize(lang)
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(localized)
+}
+
+// InvalidJSON menulis respons 400 untuk body yang tidak bisa di-decode.
+func InvalidJSON(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("Invalid JSON: %v", err)
+	Write(w, r, New(http.StatusBadRequest, "invalid_json", nil))
+}
+
+// Error memetakan err ke respons: request yang dibatalkan klien menjadi 499,
+// *Problem ditulis apa adanya, validate.Errors menjadi 422, error repository
+// dipetakan ke 404, 409, 422 atau 503 (termasuk query yang melewati batas
+// waktu), dan error lain menjadi 500 tanpa membocorkan pesan aslinya.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	// Query yang batal karena klien memutus koneksi bukan kesalahan server.
+	if r != nil && errors.Is(r.Context().Err(), context.Canceled) {
+		log.Printf("Request canceled by client: %s %s", r.Method, r.URL.Path)
+		Write(w, r, New(StatusClientClosedRequest, "request_canceled", nil))
+		return
+	}
+
+	var p *Problem
+	if errors.As(err, &p) {
+		Write(w, r, p)
+		return
+	}
+
+	var fields validate.Errors
+	if errors.As(err, &fields) {
+		Write(w, r, New(http.StatusUnprocessableEntity, "validation_failed", nil, fields...))
+		return
+	}
+
+	var repoErr *repository.Error
+	if errors.As(err, &repoErr) {
+		status := Status(repoErr.Kind)
+		if status == http.StatusServiceUnavailable {
+			log.Printf("Database unavailable: %v", err)
+			w.Header().Set("Retry-After", "5")
+			Write(w, r, New(status, repoErr.Code, nil))
+			return
+		}
+		p := New(status, repoErr.Code, repoErr.Params, repoErr.Fields...)
+		if p.Detail == "" {
+			p.Detail = repoErr.Message
+		}
+		Write(w, r, p)
+		return
+	}
+
+	log.Printf("Internal error: %v", err)
+	Write(w, r, New(http.StatusInternalServerError, "internal_error", nil))
+}
+
+// Status mengembalikan status HTTP untuk jenis error repository.
+func Status(kind error) int {
+	switch kind {
+	case repository.ErrNotFound:
+		return http.StatusNotFound
+	case repository.ErrConflict:
+		return http.StatusConflict
+	case repository.ErrValidation:
+		return http.StatusUnprocessableEntity
+	case repository.ErrUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,126 @@
+package problem
+
+import (
+	"context"
+	"database/sql/driver"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	cases := map[string]struct {
+		err    error
+		status int
+		code   string
+	}{
+		"not found":   {repository.NotFound("device", 3), http.StatusNotFound, "device_not_found"},
+		"conflict":    {repository.ErrEmailTaken, http.StatusConflict, "email_taken"},
+		"validation":  {repository.Invalid(repository.FieldError{Field: "usage", Code: "required", Message: "usage is required"}), http.StatusUnprocessableEntity, "validation_failed"},
+		"unavailable": {&repository.Error{Kind: repository.ErrUnavailable, Code: "database_unavailable", Message: "error fetching records", Err: driver.ErrBadConn}, http.StatusServiceUnavailable, "database_unavailable"},
+		"problem":     {InvalidParameter("id", "invalid", nil), http.StatusBadRequest, "invalid_parameter"},
+		"internal":    {errors.New("pq: relation \"energy_records\" does not exist"), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Error(w, httptest.NewRequest(http.MethodGet, "/api/devices/3", nil), tc.err)
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+
+			var p Problem
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+			assert.Equal(t, tc.status, p.Status)
+			assert.Equal(t, tc.code, p.Code)
+			assert.Equal(t, TypePrefix+tc.code, p.Type)
+			assert.Equal(t, http.StatusText(tc.status), p.Title)
+			assert.Equal(t, "/api/devices/3", p.Instance)
+			// Pesan error database tidak boleh bocor ke klien.
+			assert.NotContains(t, p.Detail, "pq:")
+		})
+	}
+}
+
+func TestError_FieldErrors(t *testing.T) {
+	w := httptest.NewRecorder()
+	Error(w, httptest.NewRequest(http.MethodPost, "/api/records/add", nil), repository.Invalid(
+		repository.FieldError{Field: "usage", Code: "required", Message: "usage is required"},
+		repository.FieldError{Field: "date", Code: "in_future", Message: "date must not be in the future"},
+	))
+
+	var p Problem
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+	assert.Len(t, p.Errors, 2)
+	assert.Equal(t, "date", p.Errors[1].Field)
+}
+
+func TestWrite_NegotiatesLanguage(t *testing.T) {
+	err := repository.Invalid(
+		validate.NewFieldError("usage", validate.CodeRequired, nil),
+		validate.NewFieldError("name", validate.CodeTooLong, i18n.Params{"max": 100}),
+	)
+	cases := map[string]struct {
+		lang   string
+		detail string
+	}{
+		"":                     {i18n.ID, "usage wajib diisi dan harus lebih dari 0; name tidak boleh lebih dari 100 karakter"},
+		"en-US,en;q=0.9":       {i18n.EN, "usage is required and must be greater than 0; name must not exceed 100 characters"},
+		"fr,id;q=0.5,en;q=0.4": {i18n.ID, "usage wajib diisi dan harus lebih dari 0; name tidak boleh lebih dari 100 karakter"},
+	}
+
+	for header, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/devices/add", nil)
+		req.Header.Set("Accept-Language", header)
+		w := httptest.NewRecorder()
+		Error(w, req, err)
+
+		assert.Equal(t, tc.lang, w.Header().Get("Content-Language"), header)
+		assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+		var p Problem
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+		assert.Equal(t, tc.detail, p.Detail, header)
+		assert.Equal(t, "validation_failed", p.Code)
+		assert.Equal(t, float64(100), p.Errors[1].Params["max"])
+	}
+}
+
+func TestWrite_LocalizesCodeMessage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/devices/3", nil)
+	w := httptest.NewRecorder()
+	Error(w, req, repository.NotFound("device", 3))
+
+	var p Problem
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+	assert.Equal(t, "Perangkat dengan ID 3 tidak ditemukan", p.Detail)
+
+	req.Header.Set("Accept-Language", "en")
+	w = httptest.NewRecorder()
+	Error(w, req, repository.NotFound("device", 3))
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+	assert.Equal(t, "device with ID 3 not found", p.Detail)
+}
+
+func TestError_ClientCanceled(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/records", nil)
+	ctx, cancel := context.WithCancel(req.Context())
+	cancel()
+
+	w := httptest.NewRecorder()
+	Error(w, req.WithContext(ctx), fmt.Errorf("error fetching records: %w", context.Canceled))
+
+	assert.Equal(t, StatusClientClosedRequest, w.Code)
+	var p Problem
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+	assert.Equal(t, "request_canceled", p.Code)
+	assert.Equal(t, "Client Closed Request", p.Title)
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrInvalidAPIKey dikembalikan untuk key yang salah, kedaluwarsa atau sudah
+// dicabut.
+var ErrInvalidAPIKey = errors.New("invalid, expired or revoked api key")
+
+// APIKeyPrefix menandai key milik API ini agar mudah dikenali bila bocor.
+const APIKeyPrefix = "dlk_"
+
+type APIKeyRepositoryInterface interface {
+	AddAPIKey(ctx context.Context, key *models.APIKey) error
+	GetAPIKeys(ctx context.Context, householdID int) ([]models.APIKey, error)
+	DeleteAPIKey(ctx context.Context, householdID int, id string) error
+	AuthenticateAPIKey(ctx context.Context, raw string) (*models.APIKey, *models.Household, error)
+}
+
+type APIKeyRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+const apiKeyColumns = `k.id, k.household_id, k.label, k.prefix, k.scopes, COALESCE(k.created_by, 0), k.expires_at, k.last_used_at, k.revoked_at, k.created_at`
+
+func scanAPIKey(row interface{ Scan(...any) error }, key *models.APIKey, extra ...any) error {
+	dest := []any{&key.ID, &key.HouseholdID, &key.Label, &key.Prefix, pq.Array(&key.Scopes), &key.CreatedBy, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(key)))
+	return hex.EncodeToString(sum[:])
+}
+
+// newAPIKey membuat key acak berisi 32 byte heksadesimal setelah APIKeyPrefix.
+func newAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + hex.EncodeToString(buf), nil
+}
+
+// AddAPIKey membuat key baru dan mengisinya ke key.Key. Key tidak bisa diambil
+// lagi setelah ini.
+func (r *APIKeyRepository) AddAPIKey(ctx context.Context, key *models.APIKey) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	raw, err := newAPIKey()
+	if err != nil {
+		return fmt.Errorf("error generating api key: %v", err)
+	}
+	key.Prefix = raw[:len(APIKeyPrefix)+8]
+
+	query := `INSERT INTO api_keys (household_id, label, prefix, key_hash, scopes, created_by, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+	err = r.DB.QueryRowContext(ctx, query, key.HouseholdID, key.Label, key.Prefix, hashAPIKey(raw), pq.Array(key.Scopes), key.CreatedBy, key.ExpiresAt).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return dbError("error inserting api key", err)
+	}
+	key.Key = raw
+	return nil
+}
+
+// GetAPIKeys mengembalikan semua key rumah tangga, termasuk yang sudah dicabut.
+func (r *APIKeyRepository) GetAPIKeys(ctx context.Context, householdID int) ([]models.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys k WHERE k.household_id = $1 ORDER BY k.id`, householdID)
+	if err != nil {
+		return nil, dbError("error fetching api keys", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var key models.APIKey
+		if err := scanAPIKey(rows, &key); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	return keys, nil
+}
+
+// DeleteAPIKey mencabut key. Baris tetap disimpan agar riwayatnya terlihat,
+// tetapi key langsung ditolak pada request berikutnya.
+func (r *APIKeyRepository) DeleteAPIKey(ctx context.Context, householdID int, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE household_id = $1 AND id = $2 AND revoked_at IS NULL`, householdID, id)
+	if err != nil {
+		return dbError("error revoking api key", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return NotFound("api key", id)
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey mencari key yang masih berlaku beserta rumah tangganya,
+// sekaligus mencatat waktu pemakaiannya.
+func (r *APIKeyRepository) AuthenticateAPIKey(ctx context.Context, raw string) (*models.APIKey, *models.Household, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `UPDATE api_keys k SET last_used_at = NOW()
+FROM households h
+WHERE h.id = k.household_id AND k.key_hash = $1 AND k.revoked_at IS NULL AND (k.expires_at IS NULL OR k.expires_at > NOW())
+RETURNING ` + apiKeyColumns + `, h.id, h.name, h.address, h.tariff_class, h.contracted_va, h.timezone, h.billing_day, h.created_at`
+
+	key := &models.APIKey{}
+	h := &models.Household{}
+	err := scanAPIKey(r.DB.QueryRowContext(ctx, query, hashAPIKey(raw)), key,
+		&h.ID, &h.Name, &h.Address, &h.TariffClass, &h.ContractedVA, &h.Timezone, &h.BillingDay, &h.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, dbError("error authenticating api key", err)
+	}
+	return key, h, nil
+}
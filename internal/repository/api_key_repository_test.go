@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &APIKeyRepository{DB: db}
+	key := &models.APIKey{HouseholdID: 10, Label: "Smart plug AC", Scopes: []string{models.ScopeRecordsWrite}, CreatedBy: 7}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO api_keys`)).
+		WithArgs(10, "Smart plug AC", sqlmock.AnyArg(), sqlmock.AnyArg(), pq.Array(key.Scopes), 7, key.ExpiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+
+	err = repo.AddAPIKey(context.Background(), key)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^dlk_[0-9a-f]{64}$`, key.Key)
+	assert.True(t, strings.HasPrefix(key.Key, key.Prefix))
+	assert.Len(t, key.Prefix, 12)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &APIKeyRepository{DB: db}
+	query := `UPDATE api_keys SET revoked_at = NOW() WHERE household_id = $1 AND id = $2 AND revoked_at IS NULL`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(10, "1").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteAPIKey(context.Background(), 10, "1"))
+
+	// Key rumah tangga lain atau yang sudah dicabut
+	mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(10, "2").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.EqualError(t, repo.DeleteAPIKey(context.Background(), 10, "2"), "api key with ID 2 not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &APIKeyRepository{DB: db}
+	raw := "dlk_" + strings.Repeat("ab", 32)
+	columns := []string{"id", "household_id", "label", "prefix", "scopes", "created_by", "expires_at", "last_used_at", "revoked_at", "created_at",
+		"h_id", "name", "address", "tariff_class", "contracted_va", "timezone", "billing_day", "h_created_at"}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE api_keys k SET last_used_at = NOW()`)).WithArgs(hashAPIKey(raw)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 10, "Smart plug AC", raw[:12], "{records:write}", 7, nil, now, nil, now,
+			10, "Rumah", "", models.TariffR1_900VA, 0, "Asia/Jakarta", 1, now))
+
+	key, household, err := repo.AuthenticateAPIKey(context.Background(), " "+raw+" ")
+	assert.NoError(t, err)
+	assert.True(t, key.HasScope(models.ScopeRecordsWrite))
+	assert.Equal(t, 10, household.ID)
+	assert.Equal(t, models.TariffR1_900VA, household.TariffClass)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE api_keys k SET last_used_at = NOW()`)).WillReturnError(sql.ErrNoRows)
+	_, _, err = repo.AuthenticateAPIKey(context.Background(), "dlk_revoked")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE api_keys k SET last_used_at = NOW()`)).WillReturnError(errors.New("db error"))
+	_, _, err = repo.AuthenticateAPIKey(context.Background(), "dlk_x")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidAPIKey)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultQueryTimeout adalah batas waktu satu pemanggilan repository bila
+// QUERY_TIMEOUT tidak diatur.
+const DefaultQueryTimeout = 5 * time.Second
+
+// withTimeout membatasi ctx dengan timeout. Timeout nol berarti query hanya
+// dibatalkan bersama ctx, mis. saat klien memutus koneksi.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// Satu-satunya koneksi sedang dipakai query lain yang lambat.
+	db.SetMaxOpenConns(1)
+	conn, err := db.Conn(context.Background())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	repo := &DeviceRepository{DB: db, Timeout: 20 * time.Millisecond}
+	start := time.Now()
+	_, err = repo.GetDevices(context.Background(), 10)
+
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestQueryCanceled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	repo := &EnergyRecordRepository{DB: db}
+	_, err = repo.GetByIdRecord(ctx, 10, "1")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrUnavailable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"time"
+)
+
+// DeviceRepositoryInterface selalu dibatasi pada perangkat milik householdID.
+type DeviceRepositoryInterface interface {
+	AddDevice(ctx context.Context, householdID int, device *models.Device) error
+	GetByIdDevice(ctx context.Context, householdID int, id string) (*models.Device, error)
+	DeleteDevice(ctx context.Context, householdID int, id string) error
+	UpdateDevice(ctx context.Context, householdID int, device *models.Device) error
+	GetDevices(ctx context.Context, householdID int) ([]models.Device, error)
+}
+
+type DeviceRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+// ErrDeviceNameTaken dikembalikan bila nama perangkat sudah dipakai di rumah
+// tangga yang sama.
+var ErrDeviceNameTaken = Conflict("device_name_taken")
+
+func (r *DeviceRepository) AddDevice(ctx context.Context, householdID int, device *models.Device) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `INSERT INTO devices (name, rated_wattage, category, room, notes, household_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	err := r.DB.QueryRowContext(ctx, query, device.Name, device.RatedWattage, device.Category, device.Room, device.Notes, householdID).
+		Scan(&device.ID, &device.CreatedAt)
+	if err != nil {
+		if uniqueViolation(err) {
+			return ErrDeviceNameTaken
+		}
+		return dbError("error inserting device", err)
+	}
+	return nil
+}
+
+func (r *DeviceRepository) GetByIdDevice(ctx context.Context, householdID int, id string) (*models.Device, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	device := &models.Device{}
+	query := `SELECT id, name, rated_wattage, category, room, notes, created_at FROM devices WHERE id = $1 AND household_id = $2`
+	err := r.DB.QueryRowContext(ctx, query, id, householdID).
+		Scan(&device.ID, &device.Name, &device.RatedWattage, &device.Category, &device.Room, &device.Notes, &device.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &models.Device{}, NotFound("device", id)
+		}
+		return &models.Device{}, dbError("error retrieving device", err)
+	}
+	return device, nil
+}
+
+func (r *DeviceRepository) DeleteDevice(ctx context.Context, householdID int, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `DELETE FROM devices WHERE id = $1 AND household_id = $2`
+	result, err := r.DB.ExecContext(ctx, query, id, householdID)
+	if err != nil {
+		return dbError("error deleting device", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return NotFound("device", id)
+	}
+
+	return nil
+}
+
+func (r *DeviceRepository) UpdateDevice(ctx context.Context, householdID int, device *models.Device) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `UPDATE devices SET name=$1, rated_wattage=$2, category=$3, room=$4, notes=$5 WHERE id=$6 AND household_id=$7`
+	result, err := r.DB.ExecContext(ctx, query, device.Name, device.RatedWattage, device.Category, device.Room, device.Notes, device.ID, householdID)
+	if err != nil {
+		if uniqueViolation(err) {
+			return ErrDeviceNameTaken
+		}
+		return dbError("error updating device", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return NotFound("device", device.ID)
+	}
+
+	return nil
+}
+
+func (r *DeviceRepository) GetDevices(ctx context.Context, householdID int) ([]models.Device, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(ctx, "SELECT id, name, rated_wattage, category, room, notes, created_at FROM devices WHERE household_id = $1 ORDER BY name", householdID)
+	if err != nil {
+		return nil, dbError("error fetching devices", err)
+	}
+	defer rows.Close()
+
+	var devices []models.Device
+	for rows.Next() {
+		var device models.Device
+		if err := rows.Scan(&device.ID, &device.Name, &device.RatedWattage, &device.Category, &device.Room, &device.Notes, &device.CreatedAt); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		devices = append(devices, device)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	if devices == nil {
+		devices = []models.Device{}
+	}
+	return devices, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var deviceColumns = []string{"id", "name", "rated_wattage", "category", "room", "notes", "created_at"}
+
+func TestAddDevice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &DeviceRepository{DB: db}
+
+	device := &models.Device{Name: "AC", RatedWattage: 350, Category: "cooling", Room: "Kamar"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO devices (name, rated_wattage, category, room, notes, household_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+	)).WithArgs(device.Name, device.RatedWattage, device.Category, device.Room, device.Notes, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+
+	err = repo.AddDevice(context.Background(), 1, device)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, device.ID)
+
+	mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO devices (name, rated_wattage, category, room, notes, household_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+	)).WillReturnError(errors.New("duplicate key"))
+
+	err = repo.AddDevice(context.Background(), 1, device)
+	assert.Error(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO devices (name, rated_wattage, category, room, notes, household_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+	)).WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.AddDevice(context.Background(), 1, device)
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func TestGetByIdDevice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &DeviceRepository{DB: db}
+	query := `SELECT id, name, rated_wattage, category, room, notes, created_at FROM devices WHERE id = $1 AND household_id = $2`
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("1", 1).
+		WillReturnRows(sqlmock.NewRows(deviceColumns).AddRow(1, "TV", 90.0, "entertainment", "Ruang Tamu", "", time.Now()))
+
+	device, err := repo.GetByIdDevice(context.Background(), 1, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "TV", device.Name)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("999", 1).WillReturnError(sql.ErrNoRows)
+
+	device, err = repo.GetByIdDevice(context.Background(), 1, "999")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, err.Error(), "not found")
+	assert.Equal(t, 0, device.ID)
+}
+
+func TestDeleteDevice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &DeviceRepository{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM devices WHERE id = $1 AND household_id = $2`)).WithArgs("1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteDevice(context.Background(), 1, "1"))
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM devices WHERE id = $1 AND household_id = $2`)).WithArgs("1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = repo.DeleteDevice(context.Background(), 1, "1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestUpdateDevice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &DeviceRepository{DB: db}
+	device := &models.Device{ID: 1, Name: "Kulkas", RatedWattage: 120, Category: "kitchen", Room: "Dapur"}
+	query := `UPDATE devices SET name=$1, rated_wattage=$2, category=$3, room=$4, notes=$5 WHERE id=$6 AND household_id=$7`
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(device.Name, device.RatedWattage, device.Category, device.Room, device.Notes, device.ID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.UpdateDevice(context.Background(), 1, device))
+
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(device.Name, device.RatedWattage, device.Category, device.Room, device.Notes, device.ID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = repo.UpdateDevice(context.Background(), 1, device)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestGetDevices(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &DeviceRepository{DB: db}
+	query := `SELECT id, name, rated_wattage, category, room, notes, created_at FROM devices WHERE household_id = $1 ORDER BY name`
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(deviceColumns).
+			AddRow(1, "AC", 350.0, "cooling", "Kamar", "", time.Now()).
+			AddRow(2, "TV", 90.0, "entertainment", "Ruang Tamu", "", time.Now()))
+
+	devices, err := repo.GetDevices(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, devices, 2)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnRows(sqlmock.NewRows(deviceColumns))
+
+	devices, err = repo.GetDevices(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, devices, 0)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(errors.New("query error"))
+
+	_, err = repo.GetDevices(context.Background(), 1)
+	assert.Error(t, err)
+}
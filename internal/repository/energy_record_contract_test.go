@@ -0,0 +1,464 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordStore adalah satu driver untuk contract test. Rumah tangga, tarif dan
+// perangkat dibuat lewat repos yang sama dengan record-nya, sehingga perubahan
+// di repository lain ikut teruji.
+type recordStore struct {
+	repos Repositories
+	// db diisi bila database dipakai bersama; data yang dibuat contract test
+	// dihapus lagi setelah test selesai.
+	db *sql.DB
+}
+
+// newHousehold membuat user dan rumah tangga kosong miliknya dengan golongan
+// tarif tertentu, lalu mengembalikan id rumah tangga.
+func (s recordStore) newHousehold(t *testing.T, tariffClass string) int {
+	t.Helper()
+	ctx := context.Background()
+	user := &models.User{Email: fmt.Sprintf("contract-%d-%d@example.com", time.Now().UnixNano(), contractUsers.Add(1)), PasswordHash: "hash"}
+	require.NoError(t, s.repos.Users.AddUser(ctx, user))
+	household := &models.Household{Name: "Contract test", TariffClass: tariffClass, Timezone: models.DefaultTimezone, BillingDay: 1}
+	require.NoError(t, s.repos.Households.AddHousehold(ctx, user.ID, household))
+	if s.db != nil {
+		t.Cleanup(func() {
+			s.db.Exec(`DELETE FROM households WHERE id = $1`, household.ID)
+			s.db.Exec(`DELETE FROM users WHERE id = $1`, user.ID)
+		})
+	}
+	return household.ID
+}
+
+// addTariff menyimpan tarif baru dan menghapusnya lagi dari database bersama.
+func (s recordStore) addTariff(t *testing.T, tariff *models.Tariff) {
+	t.Helper()
+	require.NoError(t, s.repos.Tariffs.AddTariff(context.Background(), tariff))
+	if s.db != nil {
+		t.Cleanup(func() { s.db.Exec(`DELETE FROM tariffs WHERE id = $1`, tariff.ID) })
+	}
+}
+
+var contractUsers atomic.Int64
+
+// TestEnergyRecordContract_Postgres menjalankan contract test terhadap
+// database di TEST_DATABASE_URL yang sudah dimigrasi dengan cmd/migrate up.
+// Data dibuat di rumah tangga baru dan dihapus setelah test.
+func TestEnergyRecordContract_Postgres(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.Ping())
+
+	testEnergyRecordContract(t, recordStore{repos: NewPostgresRepositories(db, DefaultQueryTimeout), db: db})
+}
+
+// testEnergyRecordContract adalah perilaku yang wajib sama di semua
+// implementasi EnergyRecordRepositoryInterface.
+func testEnergyRecordContract(t *testing.T, store recordStore) {
+	ctx := context.Background()
+	base := time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC)
+	price := 1444.70 // R-1/1300VA
+
+	add := func(t *testing.T, householdID int, record models.EnergyRecord) models.EnergyRecord {
+		t.Helper()
+		require.NoError(t, store.repos.Records.AddRecord(ctx, householdID, &record))
+		return record
+	}
+
+	t.Run("add and get", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+
+		added := add(t, household, models.EnergyRecord{Usage: 100, Duration: 2, Device: " Lampu Teras ", Date: base})
+
+		assert.Positive(t, added.ID)
+		assert.Equal(t, "Lampu Teras", added.Device)
+		require.NotNil(t, added.DeviceID)
+		assert.Equal(t, 200.0, added.EnergyWh)
+		assert.Equal(t, models.Cost(0.2, price), added.CostIDR)
+
+		got, err := store.repos.Records.GetByIdRecord(ctx, household, strconv.Itoa(added.ID))
+		require.NoError(t, err)
+		assert.Equal(t, added.ID, got.ID)
+		assert.True(t, base.Equal(got.Date), "date %v", got.Date)
+		assert.Equal(t, "Lampu Teras", got.Device)
+		assert.Equal(t, added.DeviceID, got.DeviceID)
+		assert.Nil(t, got.StartedAt)
+		assert.Equal(t, 200.0, got.EnergyWh)
+		assert.Equal(t, added.CostIDR, got.CostIDR)
+	})
+
+	t.Run("date defaults to now", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+
+		added := add(t, household, models.EnergyRecord{Usage: 50, Duration: 1, Device: "TV"})
+
+		assert.WithinDuration(t, time.Now(), added.Date, time.Minute)
+		got, err := store.repos.Records.GetByIdRecord(ctx, household, strconv.Itoa(added.ID))
+		require.NoError(t, err)
+		assert.True(t, added.Date.Equal(got.Date))
+	})
+
+	t.Run("cost follows the household tariff", func(t *testing.T) {
+		household := store.newHousehold(t, "R-1/900VA")
+
+		added := add(t, household, models.EnergyRecord{Usage: 1000, Duration: 1, Device: "Pompa", Date: base})
+
+		assert.Equal(t, models.Cost(1, 605), added.CostIDR)
+	})
+
+	t.Run("tariff class changes reprice records", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		added := add(t, household, models.EnergyRecord{Usage: 1000, Duration: 1, Device: "Pompa", Date: base})
+
+		update := &models.Household{ID: household, Name: "Contract test", TariffClass: "R-1/900VA", Timezone: models.DefaultTimezone, BillingDay: 1}
+		require.NoError(t, store.repos.Households.UpdateHousehold(ctx, update))
+
+		got, err := store.repos.Records.GetByIdRecord(ctx, household, strconv.Itoa(added.ID))
+		require.NoError(t, err)
+		assert.Equal(t, models.Cost(1, 605), got.CostIDR)
+		summary, err := store.repos.Records.SummarizeRecords(ctx, household, base, base.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, models.Cost(1, 605), summary.CostIDR)
+	})
+
+	t.Run("new tariffs apply from effective_from", func(t *testing.T) {
+		household := store.newHousehold(t, models.TariffR1_2200VA)
+		effective := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+		before := add(t, household, models.EnergyRecord{Usage: 1000, Duration: 1, Device: "AC", Date: effective.Add(-time.Hour)})
+
+		maxVA := 2200
+		store.addTariff(t, &models.Tariff{Class: models.TariffR1_2200VA, MinVA: 2200, MaxVA: &maxVA, PricePerKWh: 2000, EffectiveFrom: effective})
+
+		after := add(t, household, models.EnergyRecord{Usage: 1000, Duration: 1, Device: "AC", Date: effective})
+		assert.Equal(t, models.Cost(1, 2000), after.CostIDR)
+		got, err := store.repos.Records.GetByIdRecord(ctx, household, strconv.Itoa(before.ID))
+		require.NoError(t, err)
+		assert.Equal(t, models.Cost(1, 1444.70), got.CostIDR, "older records keep the previous tariff")
+	})
+
+	t.Run("registered devices keep their name and category", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		device := &models.Device{Name: "Lampu Teras", Category: "lighting"}
+		require.NoError(t, store.repos.Devices.AddDevice(ctx, household, device))
+
+		err := store.repos.Records.AddRecord(ctx, household, &models.EnergyRecord{Usage: 600, Duration: 1, DeviceID: &device.ID})
+		assert.ErrorIs(t, err, ErrValidation)
+		require.Len(t, FieldErrors(err), 1)
+		assert.Equal(t, "usage", FieldErrors(err)[0].Field, "lighting devices are capped at 500 W")
+
+		byID := add(t, household, models.EnergyRecord{Usage: 20, Duration: 1, DeviceID: &device.ID, Date: base})
+		byName := add(t, household, models.EnergyRecord{Usage: 20, Duration: 1, Device: "lampu teras", Date: base})
+		assert.Equal(t, "Lampu Teras", byID.Device)
+		require.NotNil(t, byName.DeviceID)
+		assert.Equal(t, device.ID, *byName.DeviceID)
+
+		err = store.repos.Records.AddRecord(ctx, household, &models.EnergyRecord{Usage: 600, Duration: 1, Device: "LAMPU TERAS"})
+		assert.ErrorIs(t, err, ErrValidation, "the ceiling applies to records by name too")
+	})
+
+	t.Run("devices are matched case-insensitively", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+
+		first := add(t, household, models.EnergyRecord{Usage: 900, Duration: 1, Device: "AC", Date: base})
+		second := add(t, household, models.EnergyRecord{Usage: 800, Duration: 1, Device: "ac", Date: base})
+		byID := add(t, household, models.EnergyRecord{Usage: 700, Duration: 1, DeviceID: first.DeviceID, Date: base})
+
+		assert.Equal(t, *first.DeviceID, *second.DeviceID)
+		assert.Equal(t, "AC", second.Device)
+		assert.Equal(t, "AC", byID.Device)
+	})
+
+	t.Run("invalid device or usage", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		other := store.newHousehold(t, models.DefaultTariffClass)
+		foreign := add(t, other, models.EnergyRecord{Usage: 10, Duration: 1, Device: "Lampu", Date: base})
+
+		err := store.repos.Records.AddRecord(ctx, household, &models.EnergyRecord{Usage: 10, Duration: 1, DeviceID: foreign.DeviceID})
+		assert.ErrorIs(t, err, ErrValidation)
+		require.Len(t, FieldErrors(err), 1)
+		assert.Equal(t, "device_id", FieldErrors(err)[0].Field, "devices of another household are not visible")
+
+		err = store.repos.Records.AddRecord(ctx, household, &models.EnergyRecord{Usage: models.MaxDeviceWattage + 1, Duration: 1, Device: "Lampu"})
+		assert.ErrorIs(t, err, ErrValidation)
+		require.Len(t, FieldErrors(err), 1)
+		assert.Equal(t, "usage", FieldErrors(err)[0].Field)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		startedAt := base.Add(-time.Hour)
+		added := add(t, household, models.EnergyRecord{Usage: 100, Duration: 1, Device: "Kipas", Date: base, StartedAt: &startedAt})
+
+		update := models.EnergyRecord{ID: added.ID, Usage: 60, Duration: 3, Device: "Kipas Angin"}
+		require.NoError(t, store.repos.Records.UpdateRecord(ctx, household, &update))
+
+		assert.True(t, base.Equal(update.Date), "date is kept when not given")
+		require.NotNil(t, update.StartedAt)
+		assert.True(t, startedAt.Equal(*update.StartedAt), "started_at is kept when not given")
+		assert.Equal(t, 180.0, update.EnergyWh)
+		assert.Equal(t, models.Cost(0.18, price), update.CostIDR)
+
+		got, err := store.repos.Records.GetByIdRecord(ctx, household, strconv.Itoa(added.ID))
+		require.NoError(t, err)
+		assert.Equal(t, 60.0, got.Usage)
+		assert.Equal(t, "Kipas Angin", got.Device)
+		assert.NotEqual(t, *added.DeviceID, *got.DeviceID)
+
+		newDate := base.Add(24 * time.Hour)
+		update = models.EnergyRecord{ID: added.ID, Usage: 60, Duration: 3, Device: "Kipas Angin", Date: newDate}
+		require.NoError(t, store.repos.Records.UpdateRecord(ctx, household, &update))
+		assert.True(t, newDate.Equal(update.Date))
+	})
+
+	t.Run("updating a missing record creates no device", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+
+		err := store.repos.Records.UpdateRecord(ctx, household, &models.EnergyRecord{ID: 999999, Usage: 10, Duration: 1, Device: "Perangkat Hantu"})
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		devices, err := store.repos.Devices.GetDevices(ctx, household)
+		require.NoError(t, err)
+		assert.Empty(t, devices)
+	})
+
+	t.Run("records of other households do not exist", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		other := store.newHousehold(t, models.DefaultTariffClass)
+		added := add(t, household, models.EnergyRecord{Usage: 100, Duration: 1, Device: "Kulkas", Date: base})
+		id := strconv.Itoa(added.ID)
+
+		_, err := store.repos.Records.GetByIdRecord(ctx, other, id)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, store.repos.Records.UpdateRecord(ctx, other, &models.EnergyRecord{ID: added.ID, Usage: 1, Duration: 1, Device: "Kulkas"}), ErrNotFound)
+		assert.ErrorIs(t, store.repos.Records.DeleteRecord(ctx, other, id), ErrNotFound)
+
+		_, err = store.repos.Records.GetByIdRecord(ctx, household, id)
+		assert.NoError(t, err, "the record is untouched")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		added := add(t, household, models.EnergyRecord{Usage: 100, Duration: 1, Device: "Setrika", Date: base})
+		id := strconv.Itoa(added.ID)
+
+		require.NoError(t, store.repos.Records.DeleteRecord(ctx, household, id))
+
+		_, err := store.repos.Records.GetByIdRecord(ctx, household, id)
+		var repoErr *Error
+		require.True(t, errors.As(err, &repoErr))
+		assert.Equal(t, "energy_record_not_found", repoErr.Code)
+		assert.ErrorIs(t, store.repos.Records.DeleteRecord(ctx, household, id), ErrNotFound)
+		assert.ErrorIs(t, store.repos.Records.UpdateRecord(ctx, household, &models.EnergyRecord{ID: added.ID, Usage: 1, Duration: 1, Device: "Setrika"}), ErrNotFound)
+	})
+
+	t.Run("get records", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		day := 24 * time.Hour
+		r1 := add(t, household, models.EnergyRecord{Usage: 10, Duration: 5, Device: "Lampu", Date: base})
+		r2 := add(t, household, models.EnergyRecord{Usage: 900, Duration: 2, Device: "AC", Date: base.Add(day)})
+		r3 := add(t, household, models.EnergyRecord{Usage: 150, Duration: 24, Device: "Kulkas", Date: base.Add(2 * day)})
+		r4 := add(t, household, models.EnergyRecord{Usage: 900, Duration: 1, Device: "ac", Date: base.Add(3 * day)})
+		r5 := add(t, household, models.EnergyRecord{Usage: 100, Duration: 3, Device: "TV", Date: base.Add(4 * day)})
+		add(t, store.newHousehold(t, models.DefaultTariffClass), models.EnergyRecord{Usage: 100, Duration: 1, Device: "AC", Date: base})
+
+		from, to := base.Add(day), base.Add(3*day)
+		minUsage, minEnergy, maxEnergy := 150.0, 300.0, 1800.0
+		tests := []struct {
+			name  string
+			query RecordQuery
+			want  []models.EnergyRecord
+		}{
+			{"default order", RecordQuery{}, []models.EnergyRecord{r1, r2, r3, r4, r5}},
+			{"date range", RecordQuery{From: &from, To: &to}, []models.EnergyRecord{r2, r3}},
+			{"device name", RecordQuery{Device: "Ac"}, []models.EnergyRecord{r2, r4}},
+			{"device id", RecordQuery{DeviceID: r3.DeviceID}, []models.EnergyRecord{r3}},
+			{"min usage", RecordQuery{MinUsage: &minUsage}, []models.EnergyRecord{r2, r3, r4}},
+			{"energy range", RecordQuery{MinEnergyWh: &minEnergy, MaxEnergyWh: &maxEnergy}, []models.EnergyRecord{r2, r4, r5}},
+			{"sort by energy", RecordQuery{SortBy: "energy_wh", SortDesc: true}, []models.EnergyRecord{r3, r2, r4, r5, r1}},
+			{"sort by device", RecordQuery{SortBy: "device"}, []models.EnergyRecord{r2, r4, r3, r1, r5}},
+			{"offset", RecordQuery{Offset: 3}, []models.EnergyRecord{r4, r5}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				page, err := store.repos.Records.GetRecords(ctx, household, tt.query)
+
+				require.NoError(t, err)
+				assert.Equal(t, recordIDs(tt.want), recordIDs(page.Records))
+				if tt.query.Offset == 0 {
+					assert.Equal(t, len(tt.want), page.Total)
+				}
+				assert.Empty(t, page.NextCursor)
+			})
+		}
+
+		t.Run("cursor pagination", func(t *testing.T) {
+			query := RecordQuery{SortBy: "usage", SortDesc: true, Limit: 2}
+			var pages [][]int
+			for {
+				page, err := store.repos.Records.GetRecords(ctx, household, query)
+				require.NoError(t, err)
+				assert.Equal(t, 5, page.Total, "total ignores the cursor")
+				pages = append(pages, recordIDs(page.Records))
+				if page.NextCursor == "" {
+					break
+				}
+				afterID, err := DecodeRecordCursor(page.NextCursor)
+				require.NoError(t, err)
+				query.AfterID = &afterID
+			}
+
+			assert.Equal(t, [][]int{{r4.ID, r2.ID}, {r3.ID, r5.ID}, {r1.ID}}, pages, "ties are broken by id")
+		})
+
+		t.Run("invalid sort", func(t *testing.T) {
+			_, err := store.repos.Records.GetRecords(ctx, household, RecordQuery{SortBy: "price"})
+
+			assert.ErrorIs(t, err, ErrValidation)
+		})
+	})
+
+	t.Run("active records", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		at := func(hour int) *time.Time {
+			t := time.Date(2024, 3, 11, hour, 0, 0, 0, time.UTC)
+			return &t
+		}
+		morning := add(t, household, models.EnergyRecord{Usage: 100, Duration: 2, Device: "Pompa", StartedAt: at(10), Date: *at(12)})
+		noon := add(t, household, models.EnergyRecord{Usage: 100, Duration: 1, Device: "Rice Cooker", StartedAt: at(12), Date: *at(13)})
+		add(t, household, models.EnergyRecord{Usage: 100, Duration: 1, Device: "Lampu", Date: *at(12)})
+
+		tests := []struct {
+			name     string
+			from, to int
+			want     []models.EnergyRecord
+		}{
+			{"ending at from is excluded", 12, 13, []models.EnergyRecord{noon}},
+			{"starting at to is excluded", 11, 12, []models.EnergyRecord{morning}},
+			{"overlapping both", 11, 13, []models.EnergyRecord{morning, noon}},
+			{"nothing active", 14, 15, nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				records, err := store.repos.Records.GetActiveRecords(ctx, household, *at(tt.from), *at(tt.to))
+
+				require.NoError(t, err)
+				assert.Equal(t, recordIDs(tt.want), recordIDs(records))
+			})
+		}
+	})
+
+	t.Run("summarize", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		add(t, household, models.EnergyRecord{Usage: 1000, Duration: 1, Device: "AC", Date: base})
+		add(t, household, models.EnergyRecord{Usage: 250, Duration: 2, Device: "Kulkas", Date: base.Add(time.Hour)})
+		add(t, household, models.EnergyRecord{Usage: 1000, Duration: 1, Device: "AC", Date: base.Add(24 * time.Hour)})
+
+		summary, err := store.repos.Records.SummarizeRecords(ctx, household, base, base.Add(24*time.Hour))
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, summary.RecordCount)
+		assert.InDelta(t, 1.5, summary.EnergyKWh, 1e-9)
+		assert.InDelta(t, models.RoundIDR(1.5*price), summary.CostIDR, 0.01)
+
+		empty, err := store.repos.Records.SummarizeRecords(ctx, household, base.Add(-48*time.Hour), base)
+		require.NoError(t, err)
+		assert.Zero(t, empty.RecordCount)
+		assert.Zero(t, empty.EnergyKWh)
+	})
+
+	t.Run("usage buckets", func(t *testing.T) {
+		household := store.newHousehold(t, models.DefaultTariffClass)
+		jakarta, err := models.LoadTimezone("Asia/Jakarta")
+		require.NoError(t, err)
+		wib := func(day, hour, minute int) time.Time {
+			return time.Date(2024, 3, day, hour, minute, 0, 0, jakarta)
+		}
+		// Batas hari mengikuti WIB: 01.00 WIB 11 Maret masih 10 Maret di UTC.
+		// 11 Maret 2024 adalah hari Senin.
+		add(t, household, models.EnergyRecord{Usage: 1000, Duration: 1, Device: "AC", Date: wib(10, 23, 30)})
+		add(t, household, models.EnergyRecord{Usage: 10, Duration: 10, Device: "Lampu", Date: wib(11, 1, 0)})
+		add(t, household, models.EnergyRecord{Usage: 500, Duration: 2, Device: "AC", Date: wib(11, 8, 0)})
+		from, to := wib(1, 0, 0), wib(31, 0, 0)
+
+		tests := []struct {
+			name  string
+			query UsageBucketQuery
+			want  []models.UsageBucket
+		}{
+			{
+				"day",
+				UsageBucketQuery{Bucket: models.BucketDay},
+				[]models.UsageBucket{
+					{Start: wib(10, 0, 0), RecordCount: 1, EnergyKWh: 1},
+					{Start: wib(11, 0, 0), RecordCount: 2, EnergyKWh: 1.1},
+				},
+			},
+			{
+				"week by device",
+				UsageBucketQuery{Bucket: models.BucketWeek, GroupByDevice: true},
+				[]models.UsageBucket{
+					{Start: wib(4, 0, 0), Device: "AC", RecordCount: 1, EnergyKWh: 1},
+					{Start: wib(11, 0, 0), Device: "AC", RecordCount: 1, EnergyKWh: 1},
+					{Start: wib(11, 0, 0), Device: "Lampu", RecordCount: 1, EnergyKWh: 0.1},
+				},
+			},
+			{
+				"month",
+				UsageBucketQuery{Bucket: models.BucketMonth},
+				[]models.UsageBucket{{Start: wib(1, 0, 0), RecordCount: 3, EnergyKWh: 2.1}},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.query.From, tt.query.To, tt.query.Location = from, to, jakarta
+
+				buckets, err := store.repos.Records.GetUsageBuckets(ctx, household, tt.query)
+
+				require.NoError(t, err)
+				require.Len(t, buckets, len(tt.want))
+				for i, want := range tt.want {
+					got := buckets[i]
+					assert.True(t, want.Start.Equal(got.Start), "bucket %d starts at %v, want %v", i, got.Start, want.Start)
+					assert.Equal(t, jakarta, got.Start.Location())
+					assert.Equal(t, want.Device, got.Device)
+					assert.Equal(t, want.RecordCount, got.RecordCount)
+					assert.InDelta(t, want.EnergyKWh, got.EnergyKWh, 1e-9)
+					assert.InDelta(t, models.RoundIDR(want.EnergyKWh*price), got.CostIDR, 0.01)
+				}
+			})
+		}
+
+		t.Run("invalid bucket", func(t *testing.T) {
+			_, err := store.repos.Records.GetUsageBuckets(ctx, household, UsageBucketQuery{Bucket: "year", From: from, To: to})
+
+			assert.ErrorIs(t, err, ErrValidation)
+		})
+	})
+}
+
+func recordIDs(records []models.EnergyRecord) []int {
+	ids := []int{}
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
@@ -1,103 +1,556 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"daya-listrik-api/internal/i18n"
 	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/validate"
+	"encoding/base64"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// EnergyRecordRepositoryInterface selalu dibatasi pada record milik
+// householdID; record rumah tangga lain diperlakukan seolah tidak ada.
 type EnergyRecordRepositoryInterface interface {
-	AddRecord(record *models.EnergyRecord) error
-	GetByIdRecord(id string) (*models.EnergyRecord, error)
-	DeleteRecord(id string) error
-	UpdateRecord(record *models.EnergyRecord) error
-	GetRecords() ([]models.EnergyRecord, error)
+	AddRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error
+	GetByIdRecord(ctx context.Context, householdID int, id string) (*models.EnergyRecord, error)
+	DeleteRecord(ctx context.Context, householdID int, id string) error
+	UpdateRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error
+	GetRecords(ctx context.Context, householdID int, query RecordQuery) (*RecordPage, error)
+	GetActiveRecords(ctx context.Context, householdID int, from, to time.Time) ([]models.EnergyRecord, error)
+	SummarizeRecords(ctx context.Context, householdID int, from, to time.Time) (*models.UsageSummary, error)
+	GetUsageBuckets(ctx context.Context, householdID int, query UsageBucketQuery) ([]models.UsageBucket, error)
+}
+
+// RecordQuery berisi opsi filter, pengurutan dan paginasi untuk GetRecords.
+// Nilai kosong berarti tanpa filter dan urutan bawaan (id naik).
+type RecordQuery struct {
+	// From dan To membatasi date pada rentang [From, To).
+	From *time.Time
+	To   *time.Time
+	// Device mencocokkan nama perangkat tanpa membedakan huruf besar/kecil.
+	Device      string
+	DeviceID    *int
+	MinUsage    *float64
+	MaxUsage    *float64
+	MinEnergyWh *float64
+	MaxEnergyWh *float64
+	SortBy      string
+	SortDesc    bool
+	// Limit 0 berarti DefaultRecordLimit.
+	Limit  int
+	Offset int
+	// AfterID adalah id record terakhir dari halaman sebelumnya (cursor).
+	// Urutan harus sama dengan request yang menghasilkan cursor tersebut.
+	AfterID *int
+}
+
+// RecordPage adalah satu halaman hasil GetRecords. Total dihitung dari
+// filter tanpa memperhitungkan limit, offset maupun cursor.
+type RecordPage struct {
+	Records    []models.EnergyRecord
+	Total      int
+	NextCursor string
+}
+
+const (
+	DefaultRecordLimit = 50
+	MaxRecordLimit     = 500
+)
+
+// energyWhExpr adalah padanan SQL dari models.EnergyWh, dipakai untuk
+// filter dan pengurutan di database.
+const energyWhExpr = "(e.usage * e.duration)"
+
+const deviceNameExpr = "COALESCE(d.name, e.device)"
+
+// recordSortColumns memetakan nama sort yang diterima API ke ekspresi SQL.
+// Ekspresi tidak boleh NULL agar pagination dengan cursor tetap konsisten,
+// karena itu record tanpa started_at diurutkan berdasarkan date-nya.
+var recordSortColumns = map[string]string{
+	"id":         "e.id",
+	"date":       "e.date",
+	"usage":      "e.usage",
+	"duration":   "e.duration",
+	"device":     deviceNameExpr,
+	"started_at": "COALESCE(e.started_at, e.date)",
+	"energy_wh":  energyWhExpr,
+}
+
+// ValidRecordSort mengecek apakah kolom bisa dipakai untuk pengurutan.
+func ValidRecordSort(column string) bool {
+	_, ok := recordSortColumns[column]
+	return ok
+}
+
+// EncodeRecordCursor membuat cursor opaque dari id record.
+func EncodeRecordCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(id)))
+}
+
+// DecodeRecordCursor membaca id record dari cursor buatan EncodeRecordCursor.
+func DecodeRecordCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, Invalid(validate.NewFieldError("cursor", validate.CodeInvalid, nil))
+	}
+	id, err := strconv.Atoi(string(raw))
+	if err != nil || id <= 0 {
+		return 0, Invalid(validate.NewFieldError("cursor", validate.CodeInvalid, nil))
+	}
+	return id, nil
 }
 
 type EnergyRecordRepository struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+// Nama perangkat diambil dari tabel devices bila record sudah terhubung,
+// sehingga mengganti nama perangkat ikut mengubah semua record-nya.
+// Harga per kWh diambil dari tarif golongan rumah tangga yang berlaku pada
+// tanggal record, jadi penyesuaian tarif tidak mengubah biaya record lama.
+// Parameter $1 selalu id rumah tangga.
+const selectRecordQuery = `SELECT e.id, e.date, e.usage, ` + deviceNameExpr + `, e.duration, e.device_id, e.started_at, t.price_per_kwh
+` + recordFrom + `
+` + tariffJoin
+
+const recordFrom = `FROM energy_records e
+LEFT JOIN devices d ON d.id = e.device_id`
+
+const tariffJoin = `JOIN households h ON h.id = e.household_id
+LEFT JOIN LATERAL (
+	SELECT price_per_kwh FROM tariffs
+	WHERE class = h.tariff_class AND effective_from <= e.date
+	ORDER BY effective_from DESC LIMIT 1
+) t ON TRUE`
+
+const tariffPriceQuery = `SELECT t.price_per_kwh FROM tariffs t
+JOIN households h ON h.tariff_class = t.class
+WHERE h.id = $1 AND t.effective_from <= $2 ORDER BY t.effective_from DESC LIMIT 1`
+
+func scanRecord(row interface{ Scan(...any) error }, record *models.EnergyRecord) error {
+	var price sql.NullFloat64
+	if err := row.Scan(&record.ID, &record.Date, &record.Usage, &record.Device, &record.Duration, &record.DeviceID, &record.StartedAt, &price); err != nil {
+		return err
+	}
+	record.ComputeEnergy()
+	record.ComputeCost(price.Float64)
+	return nil
 }
 
-func (r *EnergyRecordRepository) AddRecord(record *models.EnergyRecord) error {
-	query := `INSERT INTO energy_records (usage, device, duration) VALUES ($1, $2, $3) RETURNING id, date`
-	err := r.DB.QueryRow(query, record.Usage, record.Device, record.Duration).Scan(&record.ID, &record.Date)
+// applyCost menghitung energi dan biaya record yang baru disimpan.
+func (r *EnergyRecordRepository) applyCost(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	record.ComputeEnergy()
+
+	var price sql.NullFloat64
+	err := r.DB.QueryRowContext(ctx, tariffPriceQuery, householdID, record.Date).Scan(&price)
+	if err != nil && err != sql.ErrNoRows {
+		return dbError("error retrieving tariff", err)
+	}
+	record.ComputeCost(price.Float64)
+	return nil
+}
+
+// resolveDevice menghubungkan record ke tabel devices. Jika device_id diisi,
+// perangkat harus sudah ada di rumah tangga yang sama; jika tidak, perangkat
+// dicari berdasarkan nama (tanpa membedakan huruf besar/kecil) dan dibuat bila
+// belum ada. Dijalankan di transaksi yang sama dengan penulisan record agar
+// perangkat baru ikut batal bila record gagal disimpan. Query-nya berlaku
+// untuk Postgres maupun SQLite.
+func resolveDevice(ctx context.Context, tx *sql.Tx, householdID int, record *models.EnergyRecord) error {
+	var category string
+	if record.DeviceID != nil {
+		err := tx.QueryRowContext(ctx, `SELECT name, category FROM devices WHERE id = $1 AND household_id = $2`, *record.DeviceID, householdID).Scan(&record.Device, &category)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return Invalid(validate.NewFieldError("device_id", validate.CodeNotFound, i18n.Params{"value": *record.DeviceID}))
+			}
+			return dbError("error retrieving device", err)
+		}
+	} else {
+		var deviceID int
+		query := `INSERT INTO devices (household_id, name) VALUES ($1, $2) ON CONFLICT (household_id, (LOWER(name))) DO UPDATE SET name = devices.name RETURNING id, name, category`
+		err := tx.QueryRowContext(ctx, query, householdID, strings.TrimSpace(record.Device)).Scan(&deviceID, &record.Device, &category)
+		if err != nil {
+			return dbError("error resolving device", err)
+		}
+		record.DeviceID = &deviceID
+	}
+
+	return checkUsageCeiling(record.Usage, category)
+}
+
+// checkRecordExists memastikan record ada di rumah tangga sebelum perangkatnya
+// di-resolve, sehingga PUT ke id yang tidak ada tidak membuat perangkat.
+func checkRecordExists(ctx context.Context, tx *sql.Tx, householdID, id int) error {
+	var exists bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM energy_records WHERE id = $1 AND household_id = $2)`, id, householdID).Scan(&exists)
 	if err != nil {
-		return fmt.Errorf("error inserting record: %v", err)
+		return dbError("error retrieving record", err)
+	}
+	if !exists {
+		return NotFound("energy record", id)
 	}
 	return nil
 }
 
-func (r *EnergyRecordRepository) GetByIdRecord(id string) (*models.EnergyRecord, error) {
+// checkUsageCeiling menolak daya di atas batas kategori perangkat, yang hampir
+// pasti salah input.
+func checkUsageCeiling(usage float64, category string) error {
+	if ceiling := models.WattageCeiling(category); usage > ceiling {
+		return Invalid(validate.NewFieldError("usage", validate.CodeOutOfRange, i18n.Params{"max": ceiling, "category": category}))
+	}
+	return nil
+}
+
+// nullableDate mengubah tanggal kosong menjadi NULL agar default di SQL dipakai.
+func nullableDate(date time.Time) sql.NullTime {
+	return sql.NullTime{Time: date, Valid: !date.IsZero()}
+}
+
+// AddRecord menyimpan record baru; date memakai NOW() bila tidak diisi.
+func (r *EnergyRecordRepository) AddRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError("error starting transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := resolveDevice(ctx, tx, householdID, record); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO energy_records (usage, device, duration, device_id, started_at, date, household_id) VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()), $7) RETURNING id, date`
+	err = tx.QueryRowContext(ctx, query, record.Usage, record.Device, record.Duration, record.DeviceID, record.StartedAt, nullableDate(record.Date), householdID).
+		Scan(&record.ID, &record.Date)
+	if err != nil {
+		return dbError("error inserting record", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return dbError("error committing record", err)
+	}
+	return r.applyCost(ctx, householdID, record)
+}
+
+func (r *EnergyRecordRepository) GetByIdRecord(ctx context.Context, householdID int, id string) (*models.EnergyRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
 	record := &models.EnergyRecord{}
-	query := `SELECT id, date, usage, device, duration FROM energy_records WHERE id = $1`
-	err := r.DB.QueryRow(query, id).Scan(&record.ID, &record.Date, &record.Usage, &record.Device, &record.Duration)
+	err := scanRecord(r.DB.QueryRowContext(ctx, selectRecordQuery+` WHERE e.household_id = $1 AND e.id = $2`, householdID, id), record)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return &models.EnergyRecord{}, fmt.Errorf("record with ID %s not found", id)
+			return &models.EnergyRecord{}, NotFound("energy record", id)
 		}
-		return &models.EnergyRecord{}, fmt.Errorf("error retrieving record: %v", err)
+		return &models.EnergyRecord{}, dbError("error retrieving record", err)
 	}
 	return record, nil
 }
 
-func (r *EnergyRecordRepository) DeleteRecord(id string) error {
-	query := `DELETE FROM energy_records WHERE id = $1`
-	result, err := r.DB.Exec(query, id)
+func (r *EnergyRecordRepository) DeleteRecord(ctx context.Context, householdID int, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `DELETE FROM energy_records WHERE id = $1 AND household_id = $2`
+	result, err := r.DB.ExecContext(ctx, query, id, householdID)
 	if err != nil {
-		return fmt.Errorf("error deleting record: %v", err)
+		return dbError("error deleting record", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("error checking rows affected: %v", err)
+		return dbError("error checking rows affected", err)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("record with ID %s not found", id)
+		return NotFound("energy record", id)
 	}
 
 	return nil
 }
 
-func (r *EnergyRecordRepository) UpdateRecord(record *models.EnergyRecord) error {
-	query := `UPDATE energy_records SET usage=$1, device=$2, duration=$3 WHERE id=$4`
-	result, err := r.DB.Exec(query, record.Usage, record.Device, record.Duration, record.ID)
+// UpdateRecord mengubah record; date dan started_at yang tidak diisi
+// mempertahankan nilai lama.
+func (r *EnergyRecordRepository) UpdateRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("error updating record: %v", err)
+		return dbError("error starting transaction", err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	if err := checkRecordExists(ctx, tx, householdID, record.ID); err != nil {
+		return err
+	}
+	if err := resolveDevice(ctx, tx, householdID, record); err != nil {
+		return err
+	}
+
+	query := `UPDATE energy_records SET usage=$1, device=$2, duration=$3, device_id=$4,
+	started_at=COALESCE($5, started_at), date=COALESCE($6, date) WHERE id=$7 AND household_id=$8 RETURNING date, started_at`
+	err = tx.QueryRowContext(ctx, query, record.Usage, record.Device, record.Duration, record.DeviceID, record.StartedAt, nullableDate(record.Date), record.ID, householdID).
+		Scan(&record.Date, &record.StartedAt)
 	if err != nil {
-		return fmt.Errorf("error checking rows affected: %v", err)
+		if err == sql.ErrNoRows {
+			return NotFound("energy record", record.ID)
+		}
+		return dbError("error updating record", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return dbError("error committing record", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("record with ID %d not found", record.ID)
+	return r.applyCost(ctx, householdID, record)
+}
+
+// recordConditions menyusun kondisi WHERE dari rumah tangga ($1) dan filter
+// RecordQuery.
+func recordConditions(householdID int, q RecordQuery) ([]string, []any) {
+	conditions := []string{"e.household_id = $1"}
+	args := []any{householdID}
+	add := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
 	}
 
-	return nil
+	if q.From != nil {
+		add("e.date >= $%d", *q.From)
+	}
+	if q.To != nil {
+		add("e.date < $%d", *q.To)
+	}
+	if device := strings.TrimSpace(q.Device); device != "" {
+		add("LOWER("+deviceNameExpr+") = LOWER($%d)", device)
+	}
+	if q.DeviceID != nil {
+		add("e.device_id = $%d", *q.DeviceID)
+	}
+	if q.MinUsage != nil {
+		add("e.usage >= $%d", *q.MinUsage)
+	}
+	if q.MaxUsage != nil {
+		add("e.usage <= $%d", *q.MaxUsage)
+	}
+	if q.MinEnergyWh != nil {
+		add(energyWhExpr+" >= $%d", *q.MinEnergyWh)
+	}
+	if q.MaxEnergyWh != nil {
+		add(energyWhExpr+" <= $%d", *q.MaxEnergyWh)
+	}
+	return conditions, args
 }
 
-func (r *EnergyRecordRepository) GetRecords() ([]models.EnergyRecord, error) {
-	rows, err := r.DB.Query("SELECT id, date, usage, device, duration FROM energy_records")
+func whereClause(conditions []string) string {
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// buildRecordQuery menyusun query SELECT beserta argumennya dari RecordQuery.
+// Hasil selalu diurutkan dengan e.id sebagai pemecah seri, sehingga halaman
+// berikutnya bisa dilanjutkan dari record terakhir (keyset pagination).
+// Query mengambil limit+1 baris untuk mengetahui apakah masih ada halaman.
+// selectQuery adalah SELECT sampai FROM/JOIN tanpa WHERE, mis.
+// selectRecordQuery.
+func buildRecordQuery(selectQuery string, householdID int, q RecordQuery) (string, []any, error) {
+	sortBy := q.SortBy
+	if sortBy == "" {
+		sortBy = "id"
+	}
+	column, ok := recordSortColumns[sortBy]
+	if !ok {
+		return "", nil, Invalid(validate.NewFieldError("sort", validate.CodeInvalid, i18n.Params{"value": q.SortBy}))
+	}
+	direction, comparison := "ASC", ">"
+	if q.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions, args := recordConditions(householdID, q)
+	if q.AfterID != nil {
+		args = append(args, *q.AfterID)
+		conditions = append(conditions, fmt.Sprintf("(%s, e.id) %s ((SELECT %s %s WHERE e.id = $%d AND e.household_id = $1), $%d)",
+			column, comparison, column, recordFrom, len(args), len(args)))
+	}
+
+	query := selectQuery + whereClause(conditions)
+	query += fmt.Sprintf(" ORDER BY %s %s, e.id %s", column, direction, direction)
+
+	args = append(args, recordLimit(q.Limit)+1)
+	query += fmt.Sprintf(" LIMIT $%d", len(args))
+	if q.Offset > 0 {
+		args = append(args, q.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return query, args, nil
+}
+
+func recordLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultRecordLimit
+	}
+	return limit
+}
+
+func (r *EnergyRecordRepository) GetRecords(ctx context.Context, householdID int, q RecordQuery) (*RecordPage, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query, args, err := buildRecordQuery(selectRecordQuery, householdID, q)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching records: %w", err)
+		return nil, err
+	}
+	records, err := r.queryRecords(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &RecordPage{Records: records}
+	if limit := recordLimit(q.Limit); len(records) > limit {
+		page.Records = records[:limit]
+		page.NextCursor = EncodeRecordCursor(page.Records[limit-1].ID)
+	}
+
+	conditions, countArgs := recordConditions(householdID, q)
+	err = r.DB.QueryRowContext(ctx, `SELECT COUNT(*) `+recordFrom+whereClause(conditions), countArgs...).Scan(&page.Total)
+	if err != nil {
+		return nil, dbError("error counting records", err)
+	}
+	return page, nil
+}
+
+const activeRecordsQuery = selectRecordQuery + `
+WHERE e.household_id = $1 AND e.started_at IS NOT NULL AND e.started_at < $3
+	AND e.started_at + e.duration * INTERVAL '1 hour' > $2
+ORDER BY e.started_at, e.id`
+
+// GetActiveRecords mengembalikan record yang rentang pemakaiannya
+// [started_at, started_at + duration) beririsan dengan [from, to).
+func (r *EnergyRecordRepository) GetActiveRecords(ctx context.Context, householdID int, from, to time.Time) ([]models.EnergyRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	return r.queryRecords(ctx, activeRecordsQuery, householdID, from, to)
+}
+
+func (r *EnergyRecordRepository) queryRecords(ctx context.Context, query string, args ...any) ([]models.EnergyRecord, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, dbError("error fetching records", err)
 	}
 	defer rows.Close()
 
 	var records []models.EnergyRecord
 	for rows.Next() {
 		var record models.EnergyRecord
-		if err := rows.Scan(&record.ID, &record.Date, &record.Usage, &record.Device, &record.Duration); err != nil {
-			return nil, fmt.Errorf("error scanning row: %w", err)
+		if err := scanRecord(rows, &record); err != nil {
+			return nil, dbError("error scanning row", err)
 		}
 		records = append(records, record)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error in row iteration: %w", err)
+		return nil, dbError("error in row iteration", err)
 	}
 	if records == nil {
 		records = []models.EnergyRecord{}
 	}
 	return records, nil
 }
+
+const summarizeRecordsQuery = `SELECT COUNT(*), COALESCE(SUM(` + energyWhExpr + `), 0) / 1000,
+	COALESCE(SUM(` + energyWhExpr + ` / 1000 * t.price_per_kwh), 0)
+FROM energy_records e
+` + tariffJoin + `
+WHERE e.household_id = $1 AND e.date >= $2 AND e.date < $3`
+
+// SummarizeRecords menjumlahkan energi dan biaya record pada rentang [from, to).
+func (r *EnergyRecordRepository) SummarizeRecords(ctx context.Context, householdID int, from, to time.Time) (*models.UsageSummary, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	summary := &models.UsageSummary{From: from, To: to}
+	err := r.DB.QueryRowContext(ctx, summarizeRecordsQuery, householdID, from, to).
+		Scan(&summary.RecordCount, &summary.EnergyKWh, &summary.CostIDR)
+	if err != nil {
+		return nil, dbError("error summarizing records", err)
+	}
+	summary.CostIDR = models.RoundIDR(summary.CostIDR)
+	return summary, nil
+}
+
+// UsageBucketQuery berisi parameter agregasi pemakaian per bucket waktu.
+type UsageBucketQuery struct {
+	From          time.Time
+	To            time.Time
+	Bucket        string
+	GroupByDevice bool
+	// Location menentukan batas bucket menurut jam dinding rumah tangga.
+	Location *time.Location
+}
+
+// usageBucketsQuery mengelompokkan record dengan date_trunc pada jam dinding
+// zona waktu $3, lalu mengembalikan awal bucket sebagai timestamptz.
+const usageBucketsQuery = `SELECT date_trunc($2, e.date AT TIME ZONE $3) AT TIME ZONE $3%s,
+	COUNT(*), COALESCE(SUM(` + energyWhExpr + `), 0) / 1000,
+	COALESCE(SUM(` + energyWhExpr + ` / 1000 * t.price_per_kwh), 0)
+` + recordFrom + `
+` + tariffJoin + `
+WHERE e.household_id = $1 AND e.date >= $4 AND e.date < $5
+GROUP BY %s ORDER BY %s`
+
+func buildUsageBucketsQuery(groupByDevice bool) string {
+	if groupByDevice {
+		return fmt.Sprintf(usageBucketsQuery, ", "+deviceNameExpr, "1, 2", "1, 2")
+	}
+	return fmt.Sprintf(usageBucketsQuery, "", "1", "1")
+}
+
+// GetUsageBuckets menjumlahkan energi, biaya dan jumlah record per bucket
+// pada rentang [From, To). Bucket tanpa record tidak dikembalikan.
+func (r *EnergyRecordRepository) GetUsageBuckets(ctx context.Context, householdID int, q UsageBucketQuery) ([]models.UsageBucket, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	if !models.ValidBucket(q.Bucket) {
+		return nil, Invalid(validate.NewFieldError("bucket", validate.CodeInvalid, i18n.Params{"values": models.Buckets}))
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	rows, err := r.DB.QueryContext(ctx, buildUsageBucketsQuery(q.GroupByDevice), householdID, q.Bucket, loc.String(), q.From, q.To)
+	if err != nil {
+		return nil, dbError("error aggregating records", err)
+	}
+	defer rows.Close()
+
+	buckets := []models.UsageBucket{}
+	for rows.Next() {
+		var bucket models.UsageBucket
+		dest := []any{&bucket.Start}
+		if q.GroupByDevice {
+			dest = append(dest, &bucket.Device)
+		}
+		dest = append(dest, &bucket.RecordCount, &bucket.EnergyKWh, &bucket.CostIDR)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		bucket.Start = bucket.Start.In(loc)
+		bucket.CostIDR = models.RoundIDR(bucket.CostIDR)
+		buckets = append(buckets, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	return buckets, nil
+}
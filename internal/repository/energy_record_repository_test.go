@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"daya-listrik-api/internal/models"
 	"errors"
@@ -12,6 +13,14 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var recordColumns = []string{"id", "date", "usage", "device", "duration", "device_id", "started_at", "price_per_kwh"}
+
+const (
+	resolveDeviceByNameQuery = `INSERT INTO devices (household_id, name) VALUES ($1, $2) ON CONFLICT (household_id, (LOWER(name))) DO UPDATE SET name = devices.name RETURNING id, name, category`
+	resolveDeviceByIdQuery   = `SELECT name, category FROM devices WHERE id = $1 AND household_id = $2`
+	recordExistsQuery        = `SELECT EXISTS (SELECT 1 FROM energy_records WHERE id = $1 AND household_id = $2)`
+)
+
 func TestAddRecord(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -25,24 +34,61 @@ func TestAddRecord(t *testing.T) {
 		Duration: 5.0,
 	}
 
-	// Setup expectation for QueryRow + Scan on Insert returning id and date
+	// Device resolved by name, then insert returning id and date
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(resolveDeviceByNameQuery)).WithArgs(1, "Device A").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "category"}).AddRow(7, "Device A", ""))
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO energy_records (usage, device, duration) VALUES ($1, $2, $3) RETURNING id, date`,
-	)).WithArgs(record.Usage, record.Device, record.Duration).
+		`INSERT INTO energy_records (usage, device, duration, device_id, started_at, date, household_id) VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()), $7) RETURNING id, date`,
+	)).WithArgs(record.Usage, record.Device, record.Duration, 7, nil, sql.NullTime{}, 1).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "date"}).AddRow(1, time.Now()))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(tariffPriceQuery)).WithArgs(1, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"price_per_kwh"}).AddRow(1444.70))
 
-	err = repo.AddRecord(record)
+	err = repo.AddRecord(context.Background(), 1, record)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, record.ID)
-
-	// Test error on Insert
+	assert.Equal(t, 7, *record.DeviceID)
+	assert.Equal(t, 52.5, record.EnergyWh)
+	assert.Equal(t, 75.85, record.CostIDR)
+
+	// Test error on Insert: perangkat yang baru dibuat ikut di-rollback
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(resolveDeviceByIdQuery)).WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "category"}).AddRow("Device A", ""))
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO energy_records (usage, device, duration) VALUES ($1, $2, $3) RETURNING id, date`,
-	)).WithArgs(record.Usage, record.Device, record.Duration).
+		`INSERT INTO energy_records (usage, device, duration, device_id, started_at, date, household_id) VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()), $7) RETURNING id, date`,
+	)).WithArgs(record.Usage, record.Device, record.Duration, 7, nil, sqlmock.AnyArg(), 1).
 		WillReturnError(errors.New("insert error"))
+	mock.ExpectRollback()
 
-	err = repo.AddRecord(record)
+	err = repo.AddRecord(context.Background(), 1, record)
 	assert.Error(t, err)
+
+	// Unknown device_id
+	unknown := 99
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(resolveDeviceByIdQuery)).WithArgs(unknown, 1).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err = repo.AddRecord(context.Background(), 1, &models.EnergyRecord{Usage: 1, DeviceID: &unknown})
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, "device_id", FieldErrors(err)[0].Field)
+	assert.Contains(t, err.Error(), "not found")
+
+	// Usage above the device category ceiling
+	lamp := 3
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(resolveDeviceByIdQuery)).WithArgs(lamp, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "category"}).AddRow("Lampu Teras", "lighting"))
+	mock.ExpectRollback()
+
+	err = repo.AddRecord(context.Background(), 1, &models.EnergyRecord{Usage: 1500, Duration: 1, DeviceID: &lamp})
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, "usage", FieldErrors(err)[0].Field)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestGetByIdRecord(t *testing.T) {
@@ -63,32 +109,34 @@ func TestGetByIdRecord(t *testing.T) {
 
 	// Happy path
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT id, date, usage, device, duration FROM energy_records WHERE id = $1`,
-	)).WithArgs(id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "date", "usage", "device", "duration"}).
-			AddRow(expectedRecord.ID, expectedRecord.Date, expectedRecord.Usage, expectedRecord.Device, expectedRecord.Duration))
+		selectRecordQuery+` WHERE e.household_id = $1 AND e.id = $2`,
+	)).WithArgs(1, id).
+		WillReturnRows(sqlmock.NewRows(recordColumns).
+			AddRow(expectedRecord.ID, expectedRecord.Date, expectedRecord.Usage, expectedRecord.Device, expectedRecord.Duration, nil, nil, 1444.70))
 
-	rec, err := repo.GetByIdRecord(id)
+	rec, err := repo.GetByIdRecord(context.Background(), 1, id)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRecord.ID, rec.ID)
+	assert.Equal(t, 0.07, rec.EnergyKWh)
+	assert.Equal(t, 101.13, rec.CostIDR)
 
 	// No rows found
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT id, date, usage, device, duration FROM energy_records WHERE id = $1`,
-	)).WithArgs("999").
+		selectRecordQuery+` WHERE e.household_id = $1 AND e.id = $2`,
+	)).WithArgs(1, "999").
 		WillReturnError(sql.ErrNoRows)
 
-	rec, err = repo.GetByIdRecord("999")
+	rec, err = repo.GetByIdRecord(context.Background(), 1, "999")
 	assert.Error(t, err)
 	assert.Equal(t, 0, rec.ID)
 
 	// Other error
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT id, date, usage, device, duration FROM energy_records WHERE id = $1`,
-	)).WithArgs("error").
+		selectRecordQuery+` WHERE e.household_id = $1 AND e.id = $2`,
+	)).WithArgs(1, "error").
 		WillReturnError(errors.New("some db error"))
 
-	rec, err = repo.GetByIdRecord("error")
+	rec, err = repo.GetByIdRecord(context.Background(), 1, "error")
 	assert.Error(t, err)
 }
 
@@ -103,30 +151,30 @@ func TestDeleteRecord(t *testing.T) {
 
 	// Successful delete (rows affected = 1)
 	mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM energy_records WHERE id = $1`,
-	)).WithArgs(id).
+		`DELETE FROM energy_records WHERE id = $1 AND household_id = $2`,
+	)).WithArgs(id, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = repo.DeleteRecord(id)
+	err = repo.DeleteRecord(context.Background(), 1, id)
 	assert.NoError(t, err)
 
 	// Delete no rows affected
 	mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM energy_records WHERE id = $1`,
-	)).WithArgs(id).
+		`DELETE FROM energy_records WHERE id = $1 AND household_id = $2`,
+	)).WithArgs(id, 1).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = repo.DeleteRecord(id)
-	assert.Error(t, err)
+	err = repo.DeleteRecord(context.Background(), 1, id)
+	assert.ErrorIs(t, err, ErrNotFound)
 	assert.Contains(t, err.Error(), "not found")
 
 	// Exec error
 	mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM energy_records WHERE id = $1`,
-	)).WithArgs(id).
+		`DELETE FROM energy_records WHERE id = $1 AND household_id = $2`,
+	)).WithArgs(id, 1).
 		WillReturnError(errors.New("exec error"))
 
-	err = repo.DeleteRecord(id)
+	err = repo.DeleteRecord(context.Background(), 1, id)
 	assert.Error(t, err)
 }
 
@@ -137,77 +185,120 @@ func TestUpdateRecord(t *testing.T) {
 
 	repo := &EnergyRecordRepository{DB: db}
 
+	deviceID := 3
 	record := &models.EnergyRecord{
 		ID:       1,
 		Usage:    15.0,
 		Device:   "Device B",
 		Duration: 2.5,
+		DeviceID: &deviceID,
+	}
+	expectDevice := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(recordExistsQuery)).WithArgs(record.ID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(regexp.QuoteMeta(resolveDeviceByIdQuery)).WithArgs(deviceID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "category"}).AddRow("Device B", ""))
 	}
 
-	// Successful update (rows affected = 1)
-	mock.ExpectExec(regexp.QuoteMeta(
-		`UPDATE energy_records SET usage=$1, device=$2, duration=$3 WHERE id=$4`,
-	)).WithArgs(record.Usage, record.Device, record.Duration, record.ID).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	updateQuery := `UPDATE energy_records SET usage=$1, device=$2, duration=$3, device_id=$4,
+	started_at=COALESCE($5, started_at), date=COALESCE($6, date) WHERE id=$7 AND household_id=$8 RETURNING date, started_at`
+	backfilled := time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC)
+	record.Date = backfilled
+
+	// Successful update
+	expectDevice()
+	mock.ExpectQuery(regexp.QuoteMeta(updateQuery)).
+		WithArgs(record.Usage, record.Device, record.Duration, deviceID, nil, sql.NullTime{Time: backfilled, Valid: true}, record.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"date", "started_at"}).AddRow(backfilled, nil))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(tariffPriceQuery)).WithArgs(1, sqlmock.AnyArg()).
+		WillReturnError(sql.ErrNoRows)
 
-	err = repo.UpdateRecord(record)
+	err = repo.UpdateRecord(context.Background(), 1, record)
 	assert.NoError(t, err)
+	assert.Equal(t, backfilled, record.Date)
+	assert.Equal(t, 0.0, record.CostIDR)
+
+	// Record tidak ada: perangkat tidak di-resolve sama sekali
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(recordExistsQuery)).WithArgs(record.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
+
+	err = repo.UpdateRecord(context.Background(), 1, &models.EnergyRecord{ID: record.ID, Usage: 1, Device: "Perangkat Baru"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Record terhapus di antara pengecekan dan update
+	expectDevice()
+	mock.ExpectQuery(regexp.QuoteMeta(updateQuery)).
+		WithArgs(record.Usage, record.Device, record.Duration, deviceID, nil, sqlmock.AnyArg(), record.ID, 1).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
 
-	// Update no rows affected
-	mock.ExpectExec(regexp.QuoteMeta(
-		`UPDATE energy_records SET usage=$1, device=$2, duration=$3 WHERE id=$4`,
-	)).WithArgs(record.Usage, record.Device, record.Duration, record.ID).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-
-	err = repo.UpdateRecord(record)
+	err = repo.UpdateRecord(context.Background(), 1, record)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 
 	// Exec error
-	mock.ExpectExec(regexp.QuoteMeta(
-		`UPDATE energy_records SET usage=$1, device=$2, duration=$3 WHERE id=$4`,
-	)).WithArgs(record.Usage, record.Device, record.Duration, record.ID).
+	expectDevice()
+	mock.ExpectQuery(regexp.QuoteMeta(updateQuery)).
+		WithArgs(record.Usage, record.Device, record.Duration, deviceID, nil, sqlmock.AnyArg(), record.ID, 1).
 		WillReturnError(errors.New("exec error"))
+	mock.ExpectRollback()
 
-	err = repo.UpdateRecord(record)
+	err = repo.UpdateRecord(context.Background(), 1, record)
 	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+const countRecordsQuery = `SELECT COUNT(*) ` + recordFrom
+
 func TestGetRecords(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := &EnergyRecordRepository{DB: db}
+	defaultQuery := selectRecordQuery + ` WHERE e.household_id = $1 ORDER BY e.id ASC, e.id ASC LIMIT $2`
 
 	// Happy path with 2 records
-	rows := sqlmock.NewRows([]string{"id", "date", "usage", "device", "duration"}).
-		AddRow(1, time.Now(), 10.0, "Device1", 2.0).
-		AddRow(2, time.Now(), 20.0, "Device2", 3.0)
+	rows := sqlmock.NewRows(recordColumns).
+		AddRow(1, time.Now(), 10.0, "Device1", 2.0, 1, nil, 1444.70).
+		AddRow(2, time.Now(), 20.0, "Device2", 3.0, 2, nil, nil)
 
-	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT id, date, usage, device, duration FROM energy_records`,
-	)).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(defaultQuery)).
+		WithArgs(1, DefaultRecordLimit+1).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(countRecordsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
 
-	records, err := repo.GetRecords()
+	page, err := repo.GetRecords(context.Background(), 1, RecordQuery{})
 	assert.NoError(t, err)
-	assert.Len(t, records, 2)
+	assert.Len(t, page.Records, 2)
+	assert.Equal(t, 2, page.Total)
+	assert.Empty(t, page.NextCursor)
 
 	// Empty result
-	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT id, date, usage, device, duration FROM energy_records`,
-	)).WillReturnRows(sqlmock.NewRows([]string{"id", "date", "usage", "device", "duration"}))
+	mock.ExpectQuery(regexp.QuoteMeta(defaultQuery)).
+		WithArgs(1, DefaultRecordLimit+1).WillReturnRows(sqlmock.NewRows(recordColumns))
+	mock.ExpectQuery(regexp.QuoteMeta(countRecordsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-	records, err = repo.GetRecords()
+	page, err = repo.GetRecords(context.Background(), 1, RecordQuery{})
 	assert.NoError(t, err)
-	assert.Len(t, records, 0)
+	assert.Len(t, page.Records, 0)
 
 	// Query error
-	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT id, date, usage, device, duration FROM energy_records`,
-	)).WillReturnError(errors.New("query error"))
+	mock.ExpectQuery(regexp.QuoteMeta(defaultQuery)).WillReturnError(errors.New("query error"))
+
+	_, err = repo.GetRecords(context.Background(), 1, RecordQuery{})
+	assert.Error(t, err)
+
+	// Count error
+	mock.ExpectQuery(regexp.QuoteMeta(defaultQuery)).WillReturnRows(sqlmock.NewRows(recordColumns))
+	mock.ExpectQuery(regexp.QuoteMeta(countRecordsQuery)).WillReturnError(errors.New("count error"))
 
-	records, err = repo.GetRecords()
+	_, err = repo.GetRecords(context.Background(), 1, RecordQuery{})
 	assert.Error(t, err)
 }
 
@@ -218,16 +309,16 @@ func TestGetRecordsScanError(t *testing.T) {
 
 	repo := &EnergyRecordRepository{DB: db}
 
-	rows := sqlmock.NewRows([]string{"id", "date", "usage", "device", "duration"}).
-		AddRow(1, time.Now(), "invalid_float", "Device1", 2.0)
+	rows := sqlmock.NewRows(recordColumns).
+		AddRow(1, time.Now(), "invalid_float", "Device1", 2.0, nil, nil, nil)
 
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT id, date, usage, device, duration FROM energy_records`,
-	)).WillReturnRows(rows)
+		selectRecordQuery,
+	)).WithArgs(1, DefaultRecordLimit+1).WillReturnRows(rows)
 
-	records, err := repo.GetRecords()
+	page, err := repo.GetRecords(context.Background(), 1, RecordQuery{})
 	assert.Error(t, err)
-	assert.Nil(t, records)
+	assert.Nil(t, page)
 }
 
 func TestGetRecordsRowsErr(t *testing.T) {
@@ -237,14 +328,178 @@ func TestGetRecordsRowsErr(t *testing.T) {
 
 	repo := &EnergyRecordRepository{DB: db}
 
-	rows := sqlmock.NewRows([]string{"id", "date", "usage", "device", "duration"}).
-		AddRow(1, time.Now(), 10.0, "Device1", 2.0)
+	rows := sqlmock.NewRows(recordColumns).
+		AddRow(1, time.Now(), 10.0, "Device1", 2.0, 1, nil, 1444.70)
 
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT id, date, usage, device, duration FROM energy_records`,
-	)).WillReturnRows(rows)
+		selectRecordQuery,
+	)).WithArgs(1, DefaultRecordLimit+1).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(countRecordsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-	records, err := repo.GetRecords()
+	page, err := repo.GetRecords(context.Background(), 1, RecordQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, page.Records, 1)
+}
+
+func TestGetRecordsEnergyFilterAndSort(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &EnergyRecordRepository{DB: db}
+
+	minWh, maxWh := 100.0, 5000.0
+	rows := sqlmock.NewRows(recordColumns).
+		AddRow(1, time.Now(), 350.0, "AC", 8.0, 1, nil, 1444.70)
+
+	mock.ExpectQuery(regexp.QuoteMeta(
+		selectRecordQuery+` WHERE e.household_id = $1 AND (e.usage * e.duration) >= $2 AND (e.usage * e.duration) <= $3 ORDER BY (e.usage * e.duration) DESC, e.id DESC LIMIT $4`,
+	)).WithArgs(1, minWh, maxWh, DefaultRecordLimit+1).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(
+		countRecordsQuery+` WHERE e.household_id = $1 AND (e.usage * e.duration) >= $2 AND (e.usage * e.duration) <= $3`,
+	)).WithArgs(1, minWh, maxWh).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	page, err := repo.GetRecords(context.Background(), 1, RecordQuery{MinEnergyWh: &minWh, MaxEnergyWh: &maxWh, SortBy: "energy_wh", SortDesc: true})
+	assert.NoError(t, err)
+	assert.Len(t, page.Records, 1)
+	assert.Equal(t, 2800.0, page.Records[0].EnergyWh)
+	assert.Equal(t, 2.8, page.Records[0].EnergyKWh)
+	assert.Equal(t, 4045.16, page.Records[0].CostIDR)
+
+	_, err = repo.GetRecords(context.Background(), 1, RecordQuery{SortBy: "password"})
+	assert.Error(t, err)
+}
+
+func TestGetRecordsFiltersAndPagination(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &EnergyRecordRepository{DB: db}
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	minUsage := 100.0
+	afterID := 40
+	now := time.Now()
+
+	// limit 2 mengambil 3 baris; baris ketiga menandakan masih ada halaman
+	rows := sqlmock.NewRows(recordColumns).
+		AddRow(41, now, 350.0, "AC", 8.0, 1, nil, 1444.70).
+		AddRow(45, now, 350.0, "AC", 6.0, 1, nil, 1444.70).
+		AddRow(52, now, 350.0, "AC", 2.0, 1, nil, 1444.70)
+
+	filters := ` WHERE e.household_id = $1 AND e.date >= $2 AND e.date < $3 AND LOWER(COALESCE(d.name, e.device)) = LOWER($4) AND e.usage >= $5`
+	mock.ExpectQuery(regexp.QuoteMeta(
+		selectRecordQuery+filters+
+			` AND (e.date, e.id) > ((SELECT e.date `+recordFrom+` WHERE e.id = $6 AND e.household_id = $1), $6)`+
+			` ORDER BY e.date ASC, e.id ASC LIMIT $7 OFFSET $8`,
+	)).WithArgs(1, from, to, "ac", minUsage, afterID, 3, 10).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(
+		countRecordsQuery+` WHERE e.household_id = $1 AND e.date >= $2 AND e.date < $3 AND LOWER(COALESCE(d.name, e.device)) = LOWER($4) AND e.usage >= $5`,
+	)).WithArgs(1, from, to, "ac", minUsage).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(57))
+
+	page, err := repo.GetRecords(context.Background(), 1, RecordQuery{
+		From: &from, To: &to, Device: " ac ", MinUsage: &minUsage,
+		SortBy: "date", Limit: 2, Offset: 10, AfterID: &afterID,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, page.Records, 2)
+	assert.Equal(t, 57, page.Total)
+
+	next, err := DecodeRecordCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, 45, next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDecodeRecordCursor(t *testing.T) {
+	id, err := DecodeRecordCursor(EncodeRecordCursor(123))
+	assert.NoError(t, err)
+	assert.Equal(t, 123, id)
+
+	for _, cursor := range []string{"", "!!", EncodeRecordCursor(0), "YWJj"} {
+		_, err := DecodeRecordCursor(cursor)
+		assert.Error(t, err, cursor)
+	}
+}
+
+func TestSummarizeRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &EnergyRecordRepository{DB: db}
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	mock.ExpectQuery(regexp.QuoteMeta(summarizeRecordsQuery)).
+		WithArgs(1, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "energy_kwh", "cost_idr"}).AddRow(3, 12.5, 18058.754))
+
+	summary, err := repo.SummarizeRecords(context.Background(), 1, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, summary.RecordCount)
+	assert.Equal(t, 12.5, summary.EnergyKWh)
+	assert.Equal(t, 18058.75, summary.CostIDR)
+
+	mock.ExpectQuery(regexp.QuoteMeta(summarizeRecordsQuery)).WillReturnError(errors.New("query error"))
+
+	_, err = repo.SummarizeRecords(context.Background(), 1, from, to)
+	assert.Error(t, err)
+}
+
+func TestGetActiveRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &EnergyRecordRepository{DB: db}
+	from := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	startedAt := from.Add(8 * time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta(activeRecordsQuery)).
+		WithArgs(1, from, to).
+		WillReturnRows(sqlmock.NewRows(recordColumns).
+			AddRow(1, startedAt, 600.0, "Setrika", 1.0, 2, startedAt, 1444.70))
+
+	records, err := repo.GetActiveRecords(context.Background(), 1, from, to)
 	assert.NoError(t, err)
 	assert.Len(t, records, 1)
-}
\ No newline at end of file
+	assert.Equal(t, startedAt, *records[0].StartedAt)
+}
+
+func TestGetUsageBuckets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &EnergyRecordRepository{DB: db}
+	wita, _ := models.LoadTimezone("WITA")
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, wita)
+	to := from.AddDate(0, 0, 7)
+
+	mock.ExpectQuery(regexp.QuoteMeta(buildUsageBucketsQuery(true))).
+		WithArgs(1, models.BucketDay, "Asia/Makassar", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "device", "count", "energy_kwh", "cost_idr"}).
+			AddRow(time.Date(2026, 9, 30, 16, 0, 0, 0, time.UTC), "AC", 2, 5.6, 8090.321))
+
+	buckets, err := repo.GetUsageBuckets(context.Background(), 1, UsageBucketQuery{From: from, To: to, Bucket: models.BucketDay, GroupByDevice: true, Location: wita})
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 1)
+	assert.True(t, from.Equal(buckets[0].Start))
+	assert.Equal(t, wita, buckets[0].Start.Location())
+	assert.Equal(t, "AC", buckets[0].Device)
+	assert.Equal(t, 8090.32, buckets[0].CostIDR)
+
+	mock.ExpectQuery(regexp.QuoteMeta(buildUsageBucketsQuery(false))).WillReturnError(errors.New("query error"))
+
+	_, err = repo.GetUsageBuckets(context.Background(), 1, UsageBucketQuery{From: from, To: to, Bucket: models.BucketMonth, Location: wita})
+	assert.Error(t, err)
+
+	_, err = repo.GetUsageBuckets(context.Background(), 1, UsageBucketQuery{From: from, To: to, Bucket: "hour"})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/validate"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Jenis error repository. Semua error repository bisa dicek dengan errors.Is
+// terhadap salah satu jenis ini; handler memetakannya ke 404, 409, 422 dan 503.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrUnavailable = errors.New("database unavailable")
+)
+
+// FieldError menjelaskan satu field input yang tidak valid.
+type FieldError = validate.FieldError
+
+// Error adalah error bertipe dengan Code yang stabil untuk dibaca klien.
+// Message berbahasa Inggris; handler merender ulang pesannya dari Code dan
+// Params sesuai bahasa klien.
+type Error struct {
+	// Kind adalah salah satu dari ErrNotFound, ErrConflict, ErrValidation
+	// atau ErrUnavailable.
+	Kind    error
+	Code    string
+	Message string
+	Params  i18n.Params
+	Fields  []FieldError
+	// Err adalah penyebab asli, bila ada.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// NotFound membuat error untuk resource yang tidak ada, dengan kode seperti
+// "energy_record_not_found".
+func NotFound(resource string, id any) *Error {
+	return newError(ErrNotFound, strings.ReplaceAll(resource, " ", "_")+"_not_found", i18n.Params{"id": id})
+}
+
+func Conflict(code string) *Error {
+	return newError(ErrConflict, code, nil)
+}
+
+func newError(kind error, code string, params i18n.Params) *Error {
+	return &Error{Kind: kind, Code: code, Message: i18n.Text(i18n.EN, code, params), Params: params}
+}
+
+// Invalid membuat error validasi yang berisi semua field yang gagal.
+func Invalid(fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: "validation_failed", Message: validate.Errors(fields).Error(), Fields: fields}
+}
+
+// FieldErrors mengembalikan daftar field yang tidak valid dari err, bila ada.
+func FieldErrors(err error) []FieldError {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	var fields validate.Errors
+	if errors.As(err, &fields) {
+		return fields
+	}
+	return nil
+}
+
+// dbError membungkus error database. Koneksi yang putus, database yang sedang
+// dimulai ulang, atau query yang kehabisan waktu ditandai ErrUnavailable.
+func dbError(message string, err error) error {
+	if unavailable(err) {
+		return &Error{Kind: ErrUnavailable, Code: "database_unavailable", Message: message, Err: err}
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+func unavailable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// 08: connection exception, 53300: too_many_connections,
+		// 57P01-57P03: server dimatikan atau belum siap, 57014: query_canceled
+		return pqErr.Code.Class() == "08" || pqErr.Code == "53300" ||
+			pqErr.Code == "57P01" || pqErr.Code == "57P02" || pqErr.Code == "57P03" || pqErr.Code == "57014"
+	}
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		// File sedang dikunci penulisan lain lebih lama dari busy_timeout
+		code := liteErr.Code() & 0xff
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// uniqueViolation mengecek pelanggaran constraint UNIQUE atau primary key.
+func uniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		return liteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || liteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+	}
+	return false
+}
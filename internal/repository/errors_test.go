@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotFound(t *testing.T) {
+	err := NotFound("energy record", 7)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, "energy_record_not_found", err.Code)
+	assert.EqualError(t, err, "energy record with ID 7 not found")
+}
+
+func TestInvalid(t *testing.T) {
+	err := Invalid(
+		FieldError{Field: "usage", Code: "required", Message: "usage is required"},
+		FieldError{Field: "date", Code: "in_future", Message: "date must not be in the future"},
+	)
+
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.EqualError(t, err, "usage is required; date must not be in the future")
+	assert.Len(t, FieldErrors(err), 2)
+	assert.Nil(t, FieldErrors(errors.New("plain")))
+}
+
+func TestDBError(t *testing.T) {
+	for name, cause := range map[string]error{
+		"bad connection":  driver.ErrBadConn,
+		"admin shutdown":  &pq.Error{Code: "57P01"},
+		"connection lost": &pq.Error{Code: "08006"},
+	} {
+		err := dbError("error fetching records", cause)
+		assert.ErrorIs(t, err, ErrUnavailable, name)
+		assert.ErrorIs(t, err, cause, name)
+	}
+
+	err := dbError("error fetching records", &pq.Error{Code: "42601"})
+	assert.NotErrorIs(t, err, ErrUnavailable)
+	assert.Contains(t, err.Error(), "error fetching records")
+}
@@ -0,0 +1,393 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotMember dikembalikan bila user bukan anggota rumah tangga.
+	ErrNotMember = newError(ErrNotFound, "household_member_not_found", nil)
+	// ErrLastOwner mencegah rumah tangga kehilangan owner terakhirnya.
+	ErrLastOwner = Conflict("last_owner")
+	// ErrInvalidInvitation dikembalikan untuk kode yang salah, kedaluwarsa
+	// atau sudah dipakai.
+	ErrInvalidInvitation = newError(ErrValidation, "invalid_invitation", nil)
+	ErrAlreadyMember     = Conflict("already_member")
+)
+
+type HouseholdRepositoryInterface interface {
+	AddHousehold(ctx context.Context, ownerID int, household *models.Household) error
+	GetHouseholds(ctx context.Context, userID int) ([]models.Membership, error)
+	GetMembership(ctx context.Context, userID, householdID int) (*models.Membership, error)
+	UpdateHousehold(ctx context.Context, household *models.Household) error
+	DeleteHousehold(ctx context.Context, id int) error
+	GetMembers(ctx context.Context, householdID int) ([]models.HouseholdMember, error)
+	UpdateMemberRole(ctx context.Context, householdID, userID int, role string) error
+	DeleteMember(ctx context.Context, householdID, userID int) error
+	AddInvitation(ctx context.Context, invitation *models.HouseholdInvitation) error
+	GetByCodeInvitation(ctx context.Context, code string) (*models.HouseholdInvitation, error)
+	RedeemInvitation(ctx context.Context, code string, userID int) (*models.Membership, error)
+}
+
+type HouseholdRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+const selectMembershipQuery = `SELECT h.id, h.name, h.address, h.tariff_class, h.contracted_va, h.timezone, h.billing_day, h.created_at, m.role
+FROM household_members m
+JOIN households h ON h.id = m.household_id`
+
+func scanMembership(row interface{ Scan(...any) error }, m *models.Membership) error {
+	h := &m.Household
+	return row.Scan(&h.ID, &h.Name, &h.Address, &h.TariffClass, &h.ContractedVA, &h.Timezone, &h.BillingDay, &h.CreatedAt, &m.Role)
+}
+
+// ownedTables adalah tabel yang datanya dimiliki rumah tangga.
+var ownedTables = []string{"energy_records", "devices", "token_purchases", "meter_readings"}
+
+// AddHousehold menyimpan rumah tangga baru dengan ownerID sebagai owner.
+// Rumah tangga baru selalu kosong; data yang dibuat sebelum ada rumah tangga
+// hanya dipindahkan operator lewat AdoptOrphans.
+func (r *HouseholdRepository) AddHousehold(ctx context.Context, ownerID int, household *models.Household) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError("error starting transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO households (name, address, tariff_class, contracted_va, timezone, billing_day) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	err = tx.QueryRowContext(ctx, query, household.Name, household.Address, household.TariffClass, household.ContractedVA, household.Timezone, household.BillingDay).
+		Scan(&household.ID, &household.CreatedAt)
+	if err != nil {
+		return dbError("error inserting household", err)
+	}
+
+	memberQuery := `INSERT INTO household_members (household_id, user_id, role) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, memberQuery, household.ID, ownerID, models.RoleOwner); err != nil {
+		return dbError("error inserting household member", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dbError("error committing household", err)
+	}
+	return nil
+}
+
+// AdoptOrphans memindahkan data yang dibuat sebelum ada rumah tangga
+// (household_id NULL) ke householdID dan mengembalikan jumlah barisnya.
+// Perangkat tanpa rumah tangga yang namanya sudah ada di householdID digabung
+// ke perangkat tersebut. Hanya dipanggil operator (admin adopt-orphans).
+func (r *HouseholdRepository) AdoptOrphans(ctx context.Context, householdID int) (int64, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, dbError("error starting transaction", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM households WHERE id = $1)`, householdID).Scan(&exists); err != nil {
+		return 0, dbError("error retrieving household", err)
+	}
+	if !exists {
+		return 0, NotFound("household", householdID)
+	}
+
+	mergeQuery := `UPDATE energy_records e SET device_id = d.id
+FROM devices o
+JOIN devices d ON d.household_id = $1 AND LOWER(d.name) = LOWER(o.name)
+WHERE o.household_id IS NULL AND e.device_id = o.id`
+	if _, err := tx.ExecContext(ctx, mergeQuery, householdID); err != nil {
+		return 0, dbError("error merging devices", err)
+	}
+	duplicateQuery := `DELETE FROM devices o USING devices d
+WHERE o.household_id IS NULL AND d.household_id = $1 AND LOWER(d.name) = LOWER(o.name)`
+	if _, err := tx.ExecContext(ctx, duplicateQuery, householdID); err != nil {
+		return 0, dbError("error merging devices", err)
+	}
+
+	var adopted int64
+	for _, table := range ownedTables {
+		result, err := tx.ExecContext(ctx, `UPDATE `+table+` SET household_id = $1 WHERE household_id IS NULL`, householdID)
+		if err != nil {
+			return 0, dbError("error adopting "+table, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, dbError("error adopting "+table, err)
+		}
+		adopted += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, dbError("error committing household", err)
+	}
+	return adopted, nil
+}
+
+// GetHouseholds mengembalikan semua rumah tangga tempat userID menjadi anggota.
+func (r *HouseholdRepository) GetHouseholds(ctx context.Context, userID int) ([]models.Membership, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(ctx, selectMembershipQuery+` WHERE m.user_id = $1 ORDER BY h.id`, userID)
+	if err != nil {
+		return nil, dbError("error fetching households", err)
+	}
+	defer rows.Close()
+
+	memberships := []models.Membership{}
+	for rows.Next() {
+		var m models.Membership
+		if err := scanMembership(rows, &m); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		memberships = append(memberships, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	return memberships, nil
+}
+
+// GetMembership mengembalikan rumah tangga beserta peran userID di dalamnya.
+func (r *HouseholdRepository) GetMembership(ctx context.Context, userID, householdID int) (*models.Membership, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	m := &models.Membership{}
+	err := scanMembership(r.DB.QueryRowContext(ctx, selectMembershipQuery+` WHERE m.user_id = $1 AND m.household_id = $2`, userID, householdID), m)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotMember
+		}
+		return nil, dbError("error retrieving household", err)
+	}
+	return m, nil
+}
+
+func (r *HouseholdRepository) UpdateHousehold(ctx context.Context, household *models.Household) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `UPDATE households SET name=$1, address=$2, tariff_class=$3, contracted_va=$4, timezone=$5, billing_day=$6 WHERE id=$7 RETURNING created_at`
+	err := r.DB.QueryRowContext(ctx, query, household.Name, household.Address, household.TariffClass, household.ContractedVA, household.Timezone, household.BillingDay, household.ID).
+		Scan(&household.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return NotFound("household", household.ID)
+		}
+		return dbError("error updating household", err)
+	}
+	return nil
+}
+
+// DeleteHousehold menghapus rumah tangga beserta semua datanya.
+func (r *HouseholdRepository) DeleteHousehold(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM households WHERE id = $1`, id)
+	if err != nil {
+		return dbError("error deleting household", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return NotFound("household", id)
+	}
+
+	return nil
+}
+
+const selectMembersQuery = `SELECT m.user_id, u.email, m.role, m.joined_at
+FROM household_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.household_id = $1
+ORDER BY m.joined_at, m.user_id`
+
+func (r *HouseholdRepository) GetMembers(ctx context.Context, householdID int) ([]models.HouseholdMember, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(ctx, selectMembersQuery, householdID)
+	if err != nil {
+		return nil, dbError("error fetching household members", err)
+	}
+	defer rows.Close()
+
+	members := []models.HouseholdMember{}
+	for rows.Next() {
+		var member models.HouseholdMember
+		if err := rows.Scan(&member.UserID, &member.Email, &member.Role, &member.JoinedAt); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	return members, nil
+}
+
+// keepsOwner adalah kondisi tambahan agar owner terakhir tidak bisa diturunkan
+// perannya atau dikeluarkan.
+const keepsOwner = `(role <> 'owner' OR (SELECT COUNT(*) FROM household_members WHERE household_id = $1 AND role = 'owner') > 1)`
+
+func (r *HouseholdRepository) UpdateMemberRole(ctx context.Context, householdID, userID int, role string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `UPDATE household_members SET role = $3 WHERE household_id = $1 AND user_id = $2 AND ($3 = 'owner' OR ` + keepsOwner + `)`
+	result, err := r.DB.ExecContext(ctx, query, householdID, userID, role)
+	if err != nil {
+		return dbError("error updating household member", err)
+	}
+	return r.checkMemberChange(ctx, result, householdID, userID)
+}
+
+func (r *HouseholdRepository) DeleteMember(ctx context.Context, householdID, userID int) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `DELETE FROM household_members WHERE household_id = $1 AND user_id = $2 AND ` + keepsOwner
+	result, err := r.DB.ExecContext(ctx, query, householdID, userID)
+	if err != nil {
+		return dbError("error deleting household member", err)
+	}
+	return r.checkMemberChange(ctx, result, householdID, userID)
+}
+
+// checkMemberChange membedakan anggota yang tidak ada dengan owner terakhir
+// bila perubahan keanggotaan tidak mengenai baris apa pun.
+func (r *HouseholdRepository) checkMemberChange(ctx context.Context, result sql.Result, householdID, userID int) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var role string
+	err = r.DB.QueryRowContext(ctx, `SELECT role FROM household_members WHERE household_id = $1 AND user_id = $2`, householdID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return ErrNotMember
+	}
+	if err != nil {
+		return dbError("error retrieving household member", err)
+	}
+	return ErrLastOwner
+}
+
+// hashInvitationCode menormalkan kode undangan lalu menghitung hash SHA-256-nya.
+func hashInvitationCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToUpper(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+// newInvitationCode membuat kode acak 16 karakter heksadesimal.
+func newInvitationCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(buf)), nil
+}
+
+// AddInvitation membuat kode undangan baru dan mengisinya ke invitation.Code.
+// Kode tidak bisa diambil lagi setelah ini.
+func (r *HouseholdRepository) AddInvitation(ctx context.Context, invitation *models.HouseholdInvitation) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	code, err := newInvitationCode()
+	if err != nil {
+		return fmt.Errorf("error generating invitation code: %v", err)
+	}
+
+	query := `INSERT INTO household_invitations (household_id, code_hash, role, created_by, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	err = r.DB.QueryRowContext(ctx, query, invitation.HouseholdID, hashInvitationCode(code), invitation.Role, invitation.CreatedBy, invitation.ExpiresAt).
+		Scan(&invitation.ID, &invitation.CreatedAt)
+	if err != nil {
+		return dbError("error inserting invitation", err)
+	}
+	invitation.Code = code
+	return nil
+}
+
+const validInvitationCondition = `code_hash = $1 AND used_at IS NULL AND expires_at > NOW()`
+
+// GetByCodeInvitation mencari undangan yang masih berlaku tanpa memakainya.
+func (r *HouseholdRepository) GetByCodeInvitation(ctx context.Context, code string) (*models.HouseholdInvitation, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	invitation := &models.HouseholdInvitation{}
+	query := `SELECT id, household_id, role, COALESCE(created_by, 0), expires_at, created_at FROM household_invitations WHERE ` + validInvitationCondition
+	err := r.DB.QueryRowContext(ctx, query, hashInvitationCode(code)).
+		Scan(&invitation.ID, &invitation.HouseholdID, &invitation.Role, &invitation.CreatedBy, &invitation.ExpiresAt, &invitation.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, dbError("error retrieving invitation", err)
+	}
+	return invitation, nil
+}
+
+// RedeemInvitation memakai kode undangan dan menjadikan userID anggota rumah
+// tangga. Kode ditandai terpakai dalam transaksi yang sama, sehingga satu
+// kode tidak bisa dipakai dua kali.
+func (r *HouseholdRepository) RedeemInvitation(ctx context.Context, code string, userID int) (*models.Membership, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, dbError("error starting transaction", err)
+	}
+	defer tx.Rollback()
+
+	var householdID int
+	var role string
+	query := `UPDATE household_invitations SET used_at = NOW(), used_by = $2 WHERE ` + validInvitationCondition + ` RETURNING household_id, role`
+	err = tx.QueryRowContext(ctx, query, hashInvitationCode(code), userID).Scan(&householdID, &role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, dbError("error redeeming invitation", err)
+	}
+
+	memberQuery := `INSERT INTO household_members (household_id, user_id, role) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, memberQuery, householdID, userID, role); err != nil {
+		if uniqueViolation(err) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, dbError("error inserting household member", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError("error committing invitation", err)
+	}
+	return r.GetMembership(ctx, userID, householdID)
+}
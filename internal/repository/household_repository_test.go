@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var membershipColumns = []string{"id", "name", "address", "tariff_class", "contracted_va", "timezone", "billing_day", "created_at", "role"}
+
+func TestAddHousehold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &HouseholdRepository{DB: db}
+	household := &models.Household{Name: "Rumah", TariffClass: models.TariffR1_1300VA, Timezone: "Asia/Jakarta", BillingDay: 1}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO households`)).
+		WithArgs("Rumah", "", models.TariffR1_1300VA, 0, "Asia/Jakarta", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(10, time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO household_members`)).WithArgs(10, 7, models.RoleOwner).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Data tanpa rumah tangga tidak ikut diambil alih
+	mock.ExpectCommit()
+
+	err = repo.AddHousehold(context.Background(), 7, household)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, household.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdoptOrphans(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &HouseholdRepository{DB: db}
+	existsQuery := `SELECT EXISTS (SELECT 1 FROM households WHERE id = $1)`
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(existsQuery)).WithArgs(10).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE energy_records e SET device_id = d.id`)).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM devices o USING devices d`)).WithArgs(10).WillReturnResult(sqlmock.NewResult(0, 1))
+	for i, table := range ownedTables {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE ` + table + ` SET household_id = $1 WHERE household_id IS NULL`)).WithArgs(10).
+			WillReturnResult(sqlmock.NewResult(0, int64(i+1)))
+	}
+	mock.ExpectCommit()
+
+	adopted, err := repo.AdoptOrphans(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), adopted)
+
+	// Rumah tangga tujuan harus ada
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(existsQuery)).WithArgs(11).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
+
+	_, err = repo.AdoptOrphans(context.Background(), 11)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestAddHousehold_Postgres memastikan rumah tangga yang baru didaftarkan
+// tidak pernah menerima data tanpa pemilik, bahkan bila ia satu-satunya
+// rumah tangga, dan data itu baru pindah lewat AdoptOrphans.
+func TestAddHousehold_Postgres(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.Ping())
+
+	ctx := context.Background()
+	store := recordStore{repos: NewPostgresRepositories(db, DefaultQueryTimeout), db: db}
+
+	var orphanID int
+	require.NoError(t, db.QueryRow(`INSERT INTO energy_records (usage, device) VALUES (10, 'Orphan test') RETURNING id`).Scan(&orphanID))
+	t.Cleanup(func() { db.Exec(`DELETE FROM energy_records WHERE id = $1`, orphanID) })
+	owner := func() sql.NullInt64 {
+		var householdID sql.NullInt64
+		require.NoError(t, db.QueryRow(`SELECT household_id FROM energy_records WHERE id = $1`, orphanID).Scan(&householdID))
+		return householdID
+	}
+
+	store.newHousehold(t, models.DefaultTariffClass)
+	assert.False(t, owner().Valid, "first registration received orphaned data")
+	second := store.newHousehold(t, models.DefaultTariffClass)
+	assert.False(t, owner().Valid, "second registration received orphaned data")
+
+	adopted, err := store.repos.Households.(*HouseholdRepository).AdoptOrphans(ctx, second)
+	require.NoError(t, err)
+	assert.Positive(t, adopted)
+	assert.Equal(t, sql.NullInt64{Int64: int64(second), Valid: true}, owner())
+}
+
+func TestGetMembership(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &HouseholdRepository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectMembershipQuery+` WHERE m.user_id = $1 AND m.household_id = $2`)).WithArgs(7, 10).
+		WillReturnRows(sqlmock.NewRows(membershipColumns).AddRow(10, "Rumah", "", models.TariffR1_900VA, 0, "Asia/Jakarta", 1, time.Now(), models.RoleEditor))
+
+	m, err := repo.GetMembership(context.Background(), 7, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleEditor, m.Role)
+	assert.Equal(t, models.TariffR1_900VA, m.Household.TariffClass)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectMembershipQuery)).WithArgs(7, 11).WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetMembership(context.Background(), 7, 11)
+	assert.ErrorIs(t, err, ErrNotMember)
+}
+
+func TestDeleteMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &HouseholdRepository{DB: db}
+	roleQuery := `SELECT role FROM household_members WHERE household_id = $1 AND user_id = $2`
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM household_members`)).WithArgs(10, 8).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteMember(context.Background(), 10, 8))
+
+	// Owner terakhir tidak ikut terhapus
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM household_members`)).WithArgs(10, 7).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(roleQuery)).WithArgs(10, 7).WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(models.RoleOwner))
+	assert.ErrorIs(t, repo.DeleteMember(context.Background(), 10, 7), ErrLastOwner)
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM household_members`)).WithArgs(10, 9).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(roleQuery)).WithArgs(10, 9).WillReturnError(sql.ErrNoRows)
+	assert.ErrorIs(t, repo.DeleteMember(context.Background(), 10, 9), ErrNotMember)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddInvitation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &HouseholdRepository{DB: db}
+	expires := time.Now().Add(7 * 24 * time.Hour)
+	invitation := &models.HouseholdInvitation{HouseholdID: 10, Role: models.RoleViewer, CreatedBy: 7, ExpiresAt: expires}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO household_invitations`)).
+		WithArgs(10, sqlmock.AnyArg(), models.RoleViewer, 7, expires).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+
+	err = repo.AddInvitation(context.Background(), invitation)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[0-9A-F]{16}$`, invitation.Code)
+	// Kode dicocokkan tanpa membedakan huruf besar/kecil
+	assert.Equal(t, hashInvitationCode(invitation.Code), hashInvitationCode(" "+strings.ToLower(invitation.Code)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeemInvitation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &HouseholdRepository{DB: db}
+	redeemQuery := `UPDATE household_invitations SET used_at = NOW(), used_by = $2 WHERE ` + validInvitationCondition
+	hash := hashInvitationCode("A1B2C3D4E5F60718")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(redeemQuery)).WithArgs(hash, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"household_id", "role"}).AddRow(10, models.RoleEditor))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO household_members`)).WithArgs(10, 8, models.RoleEditor).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(selectMembershipQuery)).WithArgs(8, 10).
+		WillReturnRows(sqlmock.NewRows(membershipColumns).AddRow(10, "Rumah", "", models.TariffR1_1300VA, 0, "Asia/Jakarta", 1, time.Now(), models.RoleEditor))
+
+	m, err := repo.RedeemInvitation(context.Background(), "A1B2C3D4E5F60718", 8)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, m.Household.ID)
+
+	// Kode yang sudah dipakai atau kedaluwarsa
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(redeemQuery)).WithArgs(hash, 9).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = repo.RedeemInvitation(context.Background(), "A1B2C3D4E5F60718", 9)
+	assert.ErrorIs(t, err, ErrInvalidInvitation)
+
+	// User sudah menjadi anggota
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(redeemQuery)).WithArgs(hash, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"household_id", "role"}).AddRow(10, models.RoleViewer))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO household_members`)).WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err = repo.RedeemInvitation(context.Background(), "A1B2C3D4E5F60718", 7)
+	assert.ErrorIs(t, err, ErrAlreadyMember)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
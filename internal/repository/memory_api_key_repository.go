@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type memoryAPIKey struct {
+	key     models.APIKey
+	keyHash string
+}
+
+// output menyalin key tanpa nilai rahasianya.
+func (k *memoryAPIKey) output() models.APIKey {
+	key := k.key
+	key.Key = ""
+	key.Scopes = slices.Clone(k.key.Scopes)
+	key.ExpiresAt = copyTime(k.key.ExpiresAt)
+	key.LastUsedAt = copyTime(k.key.LastUsedAt)
+	key.RevokedAt = copyTime(k.key.RevokedAt)
+	return key
+}
+
+// AddAPIKey membuat key baru dan mengisinya ke key.Key. Key tidak bisa diambil
+// lagi setelah ini.
+func (r *MemoryStore) AddAPIKey(ctx context.Context, key *models.APIKey) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error inserting api key", err)
+	}
+	raw, err := newAPIKey()
+	if err != nil {
+		return fmt.Errorf("error generating api key: %v", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key.Prefix = raw[:len(APIKeyPrefix)+8]
+	key.ID = r.nextID("api_keys")
+	key.CreatedAt = time.Now()
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	stored := &memoryAPIKey{key: *key, keyHash: hashAPIKey(raw)}
+	stored.key = stored.output()
+	r.apiKeys[key.ID] = stored
+	key.Key = raw
+	return nil
+}
+
+// GetAPIKeys mengembalikan semua key rumah tangga, termasuk yang sudah dicabut.
+func (r *MemoryStore) GetAPIKeys(ctx context.Context, householdID int) ([]models.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error fetching api keys", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []models.APIKey{}
+	for _, stored := range r.apiKeys {
+		if stored.key.HouseholdID == householdID {
+			keys = append(keys, stored.output())
+		}
+	}
+	slices.SortFunc(keys, func(a, b models.APIKey) int { return a.ID - b.ID })
+	return keys, nil
+}
+
+// DeleteAPIKey mencabut key. Key tetap disimpan agar riwayatnya terlihat,
+// tetapi langsung ditolak pada request berikutnya.
+func (r *MemoryStore) DeleteAPIKey(ctx context.Context, householdID int, id string) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error revoking api key", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.apiKeys[memoryID(id)]
+	if stored == nil || stored.key.HouseholdID != householdID || stored.key.RevokedAt != nil {
+		return NotFound("api key", id)
+	}
+	now := time.Now()
+	stored.key.RevokedAt = &now
+	return nil
+}
+
+// AuthenticateAPIKey mencari key yang masih berlaku beserta rumah tangganya,
+// sekaligus mencatat waktu pemakaiannya.
+func (r *MemoryStore) AuthenticateAPIKey(ctx context.Context, raw string) (*models.APIKey, *models.Household, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, dbError("error authenticating api key", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hash := hashAPIKey(raw)
+	now := time.Now()
+	for _, stored := range r.apiKeys {
+		if stored.keyHash != hash || stored.key.RevokedAt != nil || (stored.key.ExpiresAt != nil && !stored.key.ExpiresAt.After(now)) {
+			continue
+		}
+		household := r.households[stored.key.HouseholdID]
+		if household == nil {
+			break
+		}
+		stored.key.LastUsedAt = &now
+		key, h := stored.output(), *household
+		return &key, &h, nil
+	}
+	return nil, nil, ErrInvalidAPIKey
+}
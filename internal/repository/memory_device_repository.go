@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+type memoryDevice struct {
+	householdID int
+	device      models.Device
+}
+
+// findDevice mencari perangkat householdID bernama name tanpa membedakan
+// huruf besar/kecil, selain perangkat exceptID. Dipanggil dengan mu terkunci.
+func (r *MemoryStore) findDevice(householdID int, name string, exceptID int) *memoryDevice {
+	for id, d := range r.devices {
+		if id != exceptID && d.householdID == householdID && strings.EqualFold(d.device.Name, name) {
+			return d
+		}
+	}
+	return nil
+}
+
+func (r *MemoryStore) AddDevice(ctx context.Context, householdID int, device *models.Device) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error inserting device", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.findDevice(householdID, device.Name, 0) != nil {
+		return ErrDeviceNameTaken
+	}
+	device.ID = r.nextID("devices")
+	device.CreatedAt = time.Now()
+	r.devices[device.ID] = &memoryDevice{householdID: householdID, device: *device}
+	return nil
+}
+
+func (r *MemoryStore) GetByIdDevice(ctx context.Context, householdID int, id string) (*models.Device, error) {
+	if err := ctx.Err(); err != nil {
+		return &models.Device{}, dbError("error retrieving device", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.devices[memoryID(id)]
+	if stored == nil || stored.householdID != householdID {
+		return &models.Device{}, NotFound("device", id)
+	}
+	device := stored.device
+	return &device, nil
+}
+
+// DeleteDevice menghapus perangkat; record yang memakainya tetap ada dengan
+// nama perangkat terakhir, seperti ON DELETE SET NULL.
+func (r *MemoryStore) DeleteDevice(ctx context.Context, householdID int, id string) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error deleting device", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.devices[memoryID(id)]
+	if stored == nil || stored.householdID != householdID {
+		return NotFound("device", id)
+	}
+	delete(r.devices, stored.device.ID)
+	for _, record := range r.records {
+		if record.record.DeviceID != nil && *record.record.DeviceID == stored.device.ID {
+			record.record.DeviceID = nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryStore) UpdateDevice(ctx context.Context, householdID int, device *models.Device) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error updating device", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.devices[device.ID]
+	if stored == nil || stored.householdID != householdID {
+		return NotFound("device", device.ID)
+	}
+	if r.findDevice(householdID, device.Name, device.ID) != nil {
+		return ErrDeviceNameTaken
+	}
+	createdAt := stored.device.CreatedAt
+	stored.device = *device
+	stored.device.CreatedAt = createdAt
+	return nil
+}
+
+func (r *MemoryStore) GetDevices(ctx context.Context, householdID int) ([]models.Device, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error fetching devices", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	devices := []models.Device{}
+	for _, stored := range r.devices {
+		if stored.householdID == householdID {
+			devices = append(devices, stored.device)
+		}
+	}
+	slices.SortFunc(devices, func(a, b models.Device) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return devices, nil
+}
@@ -0,0 +1,362 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/validate"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type memoryRecord struct {
+	householdID int
+	record      models.EnergyRecord
+}
+
+// memoryRecordSort adalah padanan recordSortColumns untuk record di memori.
+var memoryRecordSort = map[string]func(a, b *models.EnergyRecord) int{
+	"id":       func(a, b *models.EnergyRecord) int { return cmp.Compare(a.ID, b.ID) },
+	"date":     func(a, b *models.EnergyRecord) int { return a.Date.Compare(b.Date) },
+	"usage":    func(a, b *models.EnergyRecord) int { return cmp.Compare(a.Usage, b.Usage) },
+	"duration": func(a, b *models.EnergyRecord) int { return cmp.Compare(a.Duration, b.Duration) },
+	"device":   func(a, b *models.EnergyRecord) int { return strings.Compare(a.Device, b.Device) },
+	"started_at": func(a, b *models.EnergyRecord) int {
+		return startedOrDate(a).Compare(startedOrDate(b))
+	},
+	"energy_wh": func(a, b *models.EnergyRecord) int {
+		return cmp.Compare(models.EnergyWh(a.Usage, a.Duration), models.EnergyWh(b.Usage, b.Duration))
+	},
+}
+
+func startedOrDate(record *models.EnergyRecord) time.Time {
+	if record.StartedAt != nil {
+		return *record.StartedAt
+	}
+	return record.Date
+}
+
+// price mengembalikan harga per kWh golongan tarif rumah tangga yang berlaku
+// pada date, atau 0 bila tidak ada. Dipanggil dengan mu terkunci.
+func (r *MemoryStore) price(householdID int, date time.Time) float64 {
+	household := r.households[householdID]
+	if household == nil {
+		return 0
+	}
+	if effective := r.effectiveTariff(household.TariffClass, date); effective != nil {
+		return effective.PricePerKWh
+	}
+	return 0
+}
+
+// output menyalin record tersimpan beserta nama perangkat terkini, energi dan
+// biayanya, sehingga pemanggil tidak bisa mengubah data di repository.
+// Dipanggil dengan mu terkunci.
+func (r *MemoryStore) output(stored *memoryRecord) models.EnergyRecord {
+	record := stored.record
+	if record.StartedAt != nil {
+		startedAt := *record.StartedAt
+		record.StartedAt = &startedAt
+	}
+	if record.DeviceID != nil {
+		deviceID := *record.DeviceID
+		record.DeviceID = &deviceID
+		if device := r.devices[deviceID]; device != nil {
+			record.Device = device.device.Name
+		}
+	}
+	record.ComputeEnergy()
+	record.ComputeCost(r.price(stored.householdID, record.Date))
+	return record
+}
+
+// resolveDevice adalah padanan resolveDevice untuk perangkat di memori, yang
+// sama dengan perangkat di /api/devices. Dipanggil dengan mu terkunci untuk
+// menulis.
+func (r *MemoryStore) resolveDevice(householdID int, record *models.EnergyRecord) error {
+	var device *memoryDevice
+	if record.DeviceID != nil {
+		device = r.devices[*record.DeviceID]
+		if device == nil || device.householdID != householdID {
+			return Invalid(validate.NewFieldError("device_id", validate.CodeNotFound, i18n.Params{"value": *record.DeviceID}))
+		}
+	} else {
+		name := strings.TrimSpace(record.Device)
+		device = r.findDevice(householdID, name, 0)
+		if device == nil {
+			device = &memoryDevice{householdID: householdID, device: models.Device{ID: r.nextID("devices"), Name: name, CreatedAt: time.Now()}}
+			r.devices[device.device.ID] = device
+		}
+		deviceID := device.device.ID
+		record.DeviceID = &deviceID
+	}
+	record.Device = device.device.Name
+
+	return checkUsageCeiling(record.Usage, device.device.Category)
+}
+
+// store menyalin field record yang disimpan ke stored.
+func store(stored *memoryRecord, record *models.EnergyRecord) {
+	stored.record.Usage = record.Usage
+	stored.record.Duration = record.Duration
+	stored.record.Device = record.Device
+	deviceID := *record.DeviceID
+	stored.record.DeviceID = &deviceID
+	if record.StartedAt != nil {
+		startedAt := *record.StartedAt
+		stored.record.StartedAt = &startedAt
+	}
+	if !record.Date.IsZero() {
+		stored.record.Date = record.Date
+	}
+}
+
+// AddRecord menyimpan record baru dengan id berikutnya; date memakai waktu
+// sekarang bila tidak diisi.
+func (r *MemoryStore) AddRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error inserting record", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.resolveDevice(householdID, record); err != nil {
+		return err
+	}
+	if record.Date.IsZero() {
+		record.Date = time.Now()
+	}
+
+	record.ID = r.nextID("energy_records")
+	stored := &memoryRecord{householdID: householdID, record: models.EnergyRecord{ID: record.ID}}
+	store(stored, record)
+	r.records[record.ID] = stored
+
+	record.ComputeEnergy()
+	record.ComputeCost(r.price(householdID, record.Date))
+	return nil
+}
+
+// find mengembalikan record milik householdID. Dipanggil dengan mu terkunci.
+func (r *MemoryStore) find(householdID int, id string) *memoryRecord {
+	stored := r.records[memoryID(id)]
+	if stored == nil || stored.householdID != householdID {
+		return nil
+	}
+	return stored
+}
+
+func (r *MemoryStore) GetByIdRecord(ctx context.Context, householdID int, id string) (*models.EnergyRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return &models.EnergyRecord{}, dbError("error retrieving record", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.find(householdID, id)
+	if stored == nil {
+		return &models.EnergyRecord{}, NotFound("energy record", id)
+	}
+	record := r.output(stored)
+	return &record, nil
+}
+
+func (r *MemoryStore) DeleteRecord(ctx context.Context, householdID int, id string) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error deleting record", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.find(householdID, id)
+	if stored == nil {
+		return NotFound("energy record", id)
+	}
+	delete(r.records, stored.record.ID)
+	return nil
+}
+
+// UpdateRecord mengubah record; date dan started_at yang tidak diisi
+// mempertahankan nilai lama.
+func (r *MemoryStore) UpdateRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error updating record", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.find(householdID, strconv.Itoa(record.ID))
+	if stored == nil {
+		return NotFound("energy record", record.ID)
+	}
+	if err := r.resolveDevice(householdID, record); err != nil {
+		return err
+	}
+	store(stored, record)
+
+	updated := r.output(stored)
+	record.Date, record.StartedAt = updated.Date, updated.StartedAt
+	record.ComputeEnergy()
+	record.ComputeCost(r.price(householdID, record.Date))
+	return nil
+}
+
+// householdRecords mengembalikan salinan semua record householdID yang lolos
+// match. Dipanggil dengan mu terkunci.
+func (r *MemoryStore) householdRecords(householdID int, match func(*models.EnergyRecord) bool) []models.EnergyRecord {
+	records := []models.EnergyRecord{}
+	for _, stored := range r.records {
+		if stored.householdID != householdID {
+			continue
+		}
+		if record := r.output(stored); match(&record) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// matchRecordQuery adalah padanan recordConditions.
+func matchRecordQuery(record *models.EnergyRecord, q RecordQuery) bool {
+	energyWh := models.EnergyWh(record.Usage, record.Duration)
+	device := strings.TrimSpace(q.Device)
+	return (q.From == nil || !record.Date.Before(*q.From)) &&
+		(q.To == nil || record.Date.Before(*q.To)) &&
+		(device == "" || strings.EqualFold(record.Device, device)) &&
+		(q.DeviceID == nil || (record.DeviceID != nil && *record.DeviceID == *q.DeviceID)) &&
+		(q.MinUsage == nil || record.Usage >= *q.MinUsage) &&
+		(q.MaxUsage == nil || record.Usage <= *q.MaxUsage) &&
+		(q.MinEnergyWh == nil || energyWh >= *q.MinEnergyWh) &&
+		(q.MaxEnergyWh == nil || energyWh <= *q.MaxEnergyWh)
+}
+
+// GetRecords memfilter, mengurutkan dan memotong record seperti
+// buildRecordQuery, termasuk cursor yang melanjutkan setelah record AfterID.
+func (r *MemoryStore) GetRecords(ctx context.Context, householdID int, q RecordQuery) (*RecordPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error fetching records", err)
+	}
+	sortBy := cmp.Or(q.SortBy, "id")
+	compareColumn, ok := memoryRecordSort[sortBy]
+	if !ok {
+		return nil, Invalid(validate.NewFieldError("sort", validate.CodeInvalid, i18n.Params{"value": q.SortBy}))
+	}
+	compare := func(a, b *models.EnergyRecord) int {
+		c := cmp.Or(compareColumn(a, b), cmp.Compare(a.ID, b.ID))
+		if q.SortDesc {
+			return -c
+		}
+		return c
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := r.householdRecords(householdID, func(record *models.EnergyRecord) bool {
+		return matchRecordQuery(record, q)
+	})
+	page := &RecordPage{Total: len(records)}
+
+	if q.AfterID != nil {
+		// Seperti subquery cursor di SQL, record yang sudah dihapus tidak
+		// menghasilkan halaman apa pun.
+		after := r.find(householdID, strconv.Itoa(*q.AfterID))
+		if after == nil {
+			records = nil
+		} else {
+			afterRecord := r.output(after)
+			records = slices.DeleteFunc(records, func(record models.EnergyRecord) bool {
+				return compare(&record, &afterRecord) <= 0
+			})
+		}
+	}
+	slices.SortFunc(records, func(a, b models.EnergyRecord) int {
+		return compare(&a, &b)
+	})
+
+	records = records[min(q.Offset, len(records)):]
+	if limit := recordLimit(q.Limit); len(records) > limit {
+		records = records[:limit]
+		page.NextCursor = EncodeRecordCursor(records[limit-1].ID)
+	}
+	page.Records = append([]models.EnergyRecord{}, records...)
+	return page, nil
+}
+
+// GetActiveRecords mengembalikan record yang rentang pemakaiannya
+// [started_at, started_at + duration) beririsan dengan [from, to).
+func (r *MemoryStore) GetActiveRecords(ctx context.Context, householdID int, from, to time.Time) ([]models.EnergyRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error fetching records", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := r.householdRecords(householdID, func(record *models.EnergyRecord) bool {
+		return record.StartedAt != nil && record.StartedAt.Before(to) &&
+			record.StartedAt.Add(time.Duration(record.Duration*float64(time.Hour))).After(from)
+	})
+	slices.SortFunc(records, func(a, b models.EnergyRecord) int {
+		return cmp.Or(a.StartedAt.Compare(*b.StartedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return records, nil
+}
+
+// SummarizeRecords menjumlahkan energi dan biaya record pada rentang [from, to).
+func (r *MemoryStore) SummarizeRecords(ctx context.Context, householdID int, from, to time.Time) (*models.UsageSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error summarizing records", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	summary := &models.UsageSummary{From: from, To: to}
+	for _, stored := range r.records {
+		record := stored.record
+		if stored.householdID != householdID || record.Date.Before(from) || !record.Date.Before(to) {
+			continue
+		}
+		kwh := models.WhToKWh(models.EnergyWh(record.Usage, record.Duration))
+		summary.RecordCount++
+		summary.EnergyKWh += kwh
+		summary.CostIDR += kwh * r.price(householdID, record.Date)
+	}
+	summary.CostIDR = models.RoundIDR(summary.CostIDR)
+	return summary, nil
+}
+
+// GetUsageBuckets menjumlahkan energi, biaya dan jumlah record per bucket
+// pada rentang [From, To). Bucket tanpa record tidak dikembalikan.
+func (r *MemoryStore) GetUsageBuckets(ctx context.Context, householdID int, q UsageBucketQuery) ([]models.UsageBucket, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error aggregating records", err)
+	}
+	if !models.ValidBucket(q.Bucket) {
+		return nil, Invalid(validate.NewFieldError("bucket", validate.CodeInvalid, i18n.Params{"values": models.Buckets}))
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var usages []bucketUsage
+	for _, stored := range r.records {
+		if stored.householdID != householdID || stored.record.Date.Before(q.From) || !stored.record.Date.Before(q.To) {
+			continue
+		}
+		record := r.output(stored)
+		usages = append(usages, bucketUsage{
+			date:     record.Date,
+			device:   record.Device,
+			energyWh: record.EnergyWh,
+			price:    r.price(householdID, record.Date),
+		})
+	}
+	return aggregateBuckets(usages, q.Bucket, q.GroupByDevice, loc), nil
+}
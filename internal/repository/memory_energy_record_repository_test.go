@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnergyRecordContract_Memory(t *testing.T) {
+	testEnergyRecordContract(t, recordStore{repos: NewMemoryRepositories(NewMemoryStore())})
+}
+
+func TestMemoryEnergyRecordRepository_SortColumns(t *testing.T) {
+	assert.ElementsMatch(t, slices.Collect(maps.Keys(recordSortColumns)), slices.Collect(maps.Keys(memoryRecordSort)))
+}
+
+func TestMemoryEnergyRecordRepository_ConcurrentAdd(t *testing.T) {
+	repo := NewMemoryStore()
+	_, household := newTestHousehold(t, NewMemoryRepositories(repo), "owner@example.com")
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	ids := make([]int, 50)
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			record := &models.EnergyRecord{Usage: 100, Duration: 1, Device: "Lampu"}
+			assert.NoError(t, repo.AddRecord(ctx, household.ID, record))
+			ids[i] = record.ID
+			_, err := repo.GetRecords(ctx, household.ID, RecordQuery{})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(ids)
+	assert.Equal(t, len(ids), len(slices.Compact(ids)), "ids are unique")
+	page, err := repo.GetRecords(ctx, household.ID, RecordQuery{Limit: 100})
+	require.NoError(t, err)
+	assert.Equal(t, len(ids), page.Total)
+	assert.Equal(t, page.Records[0].DeviceID, page.Records[len(ids)-1].DeviceID, "concurrent adds share one device")
+}
+
+func TestMemoryEnergyRecordRepository_ReturnsCopies(t *testing.T) {
+	repo := NewMemoryStore()
+	_, household := newTestHousehold(t, NewMemoryRepositories(repo), "owner@example.com")
+	ctx := context.Background()
+	startedAt := time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC)
+	record := &models.EnergyRecord{Usage: 100, Duration: 1, Device: "Lampu", StartedAt: &startedAt}
+	require.NoError(t, repo.AddRecord(ctx, household.ID, record))
+
+	got, err := repo.GetByIdRecord(ctx, household.ID, "1")
+	require.NoError(t, err)
+	*got.StartedAt = got.StartedAt.Add(time.Hour)
+	*record.StartedAt = startedAt.Add(2 * time.Hour)
+
+	got, err = repo.GetByIdRecord(ctx, household.ID, "1")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 11, 8, 0, 0, 0, time.UTC), *got.StartedAt)
+}
@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"daya-listrik-api/internal/models"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type memoryMember struct {
+	userID   int
+	role     string
+	joinedAt time.Time
+}
+
+type memoryInvitation struct {
+	invitation models.HouseholdInvitation
+	codeHash   string
+	used       bool
+}
+
+// AddHousehold menyimpan rumah tangga baru dengan ownerID sebagai owner.
+func (r *MemoryStore) AddHousehold(ctx context.Context, ownerID int, household *models.Household) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error inserting household", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	household.ID = r.nextID("households")
+	household.CreatedAt = time.Now()
+	stored := *household
+	r.households[household.ID] = &stored
+	r.members[household.ID] = []*memoryMember{{userID: ownerID, role: models.RoleOwner, joinedAt: household.CreatedAt}}
+	return nil
+}
+
+// membership mengembalikan rumah tangga beserta peran userID di dalamnya,
+// atau nil. Dipanggil dengan mu terkunci.
+func (r *MemoryStore) membership(userID, householdID int) *models.Membership {
+	member := r.member(householdID, userID)
+	if member == nil {
+		return nil
+	}
+	return &models.Membership{Household: *r.households[householdID], Role: member.role}
+}
+
+// member mengembalikan keanggotaan userID di householdID, atau nil.
+// Dipanggil dengan mu terkunci.
+func (r *MemoryStore) member(householdID, userID int) *memoryMember {
+	for _, m := range r.members[householdID] {
+		if m.userID == userID {
+			return m
+		}
+	}
+	return nil
+}
+
+// GetHouseholds mengembalikan semua rumah tangga tempat userID menjadi anggota.
+func (r *MemoryStore) GetHouseholds(ctx context.Context, userID int) ([]models.Membership, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error fetching households", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	memberships := []models.Membership{}
+	for householdID := range r.households {
+		if m := r.membership(userID, householdID); m != nil {
+			memberships = append(memberships, *m)
+		}
+	}
+	slices.SortFunc(memberships, func(a, b models.Membership) int {
+		return cmp.Compare(a.Household.ID, b.Household.ID)
+	})
+	return memberships, nil
+}
+
+// GetMembership mengembalikan rumah tangga beserta peran userID di dalamnya.
+func (r *MemoryStore) GetMembership(ctx context.Context, userID, householdID int) (*models.Membership, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error retrieving household", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m := r.membership(userID, householdID)
+	if m == nil {
+		return nil, ErrNotMember
+	}
+	return m, nil
+}
+
+// UpdateHousehold mengubah pengaturan rumah tangga. Golongan tarif baru
+// langsung dipakai untuk biaya semua record-nya.
+func (r *MemoryStore) UpdateHousehold(ctx context.Context, household *models.Household) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error updating household", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.households[household.ID]
+	if stored == nil {
+		return NotFound("household", household.ID)
+	}
+	household.CreatedAt = stored.CreatedAt
+	*stored = *household
+	return nil
+}
+
+// DeleteHousehold menghapus rumah tangga beserta semua datanya, seperti
+// ON DELETE CASCADE.
+func (r *MemoryStore) DeleteHousehold(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error deleting household", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.households[id] == nil {
+		return NotFound("household", id)
+	}
+	delete(r.households, id)
+	delete(r.members, id)
+	deleteOwned(r.invitations, func(i *memoryInvitation) bool { return i.invitation.HouseholdID == id })
+	deleteOwned(r.devices, func(d *memoryDevice) bool { return d.householdID == id })
+	deleteOwned(r.records, func(e *memoryRecord) bool { return e.householdID == id })
+	deleteOwned(r.tokens, func(t *memoryTokenPurchase) bool { return t.householdID == id })
+	deleteOwned(r.meters, func(m *memoryMeterReading) bool { return m.householdID == id })
+	deleteOwned(r.apiKeys, func(k *memoryAPIKey) bool { return k.key.HouseholdID == id })
+	return nil
+}
+
+func deleteOwned[T any](rows map[int]*T, owned func(*T) bool) {
+	for id, row := range rows {
+		if owned(row) {
+			delete(rows, id)
+		}
+	}
+}
+
+func (r *MemoryStore) GetMembers(ctx context.Context, householdID int) ([]models.HouseholdMember, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error fetching household members", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := []models.HouseholdMember{}
+	for _, m := range r.members[householdID] {
+		members = append(members, models.HouseholdMember{UserID: m.userID, Email: r.users[m.userID].Email, Role: m.role, JoinedAt: m.joinedAt})
+	}
+	slices.SortFunc(members, func(a, b models.HouseholdMember) int {
+		return cmp.Or(a.JoinedAt.Compare(b.JoinedAt), cmp.Compare(a.UserID, b.UserID))
+	})
+	return members, nil
+}
+
+// keepsOwner adalah padanan kondisi keepsOwner: owner terakhir tidak bisa
+// diturunkan perannya atau dikeluarkan. Dipanggil dengan mu terkunci.
+func (r *MemoryStore) keepsOwner(householdID int, member *memoryMember) error {
+	if member.role != models.RoleOwner {
+		return nil
+	}
+	owners := 0
+	for _, m := range r.members[householdID] {
+		if m.role == models.RoleOwner {
+			owners++
+		}
+	}
+	if owners > 1 {
+		return nil
+	}
+	return ErrLastOwner
+}
+
+func (r *MemoryStore) UpdateMemberRole(ctx context.Context, householdID, userID int, role string) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error updating household member", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member := r.member(householdID, userID)
+	if member == nil {
+		return ErrNotMember
+	}
+	if role != models.RoleOwner {
+		if err := r.keepsOwner(householdID, member); err != nil {
+			return err
+		}
+	}
+	member.role = role
+	return nil
+}
+
+func (r *MemoryStore) DeleteMember(ctx context.Context, householdID, userID int) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error deleting household member", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member := r.member(householdID, userID)
+	if member == nil {
+		return ErrNotMember
+	}
+	if err := r.keepsOwner(householdID, member); err != nil {
+		return err
+	}
+	r.members[householdID] = slices.DeleteFunc(r.members[householdID], func(m *memoryMember) bool { return m == member })
+	return nil
+}
+
+// AddInvitation membuat kode undangan baru dan mengisinya ke invitation.Code.
+// Kode tidak bisa diambil lagi setelah ini.
+func (r *MemoryStore) AddInvitation(ctx context.Context, invitation *models.HouseholdInvitation) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error inserting invitation", err)
+	}
+	code, err := newInvitationCode()
+	if err != nil {
+		return fmt.Errorf("error generating invitation code: %v", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invitation.ID = r.nextID("household_invitations")
+	invitation.CreatedAt = time.Now()
+	stored := &memoryInvitation{invitation: *invitation, codeHash: hashInvitationCode(code)}
+	r.invitations[invitation.ID] = stored
+	invitation.Code = code
+	return nil
+}
+
+// validInvitation adalah padanan validInvitationCondition. Dipanggil dengan mu
+// terkunci.
+func (r *MemoryStore) validInvitation(code string) *memoryInvitation {
+	hash := hashInvitationCode(code)
+	for _, stored := range r.invitations {
+		if stored.codeHash == hash && !stored.used && stored.invitation.ExpiresAt.After(time.Now()) {
+			return stored
+		}
+	}
+	return nil
+}
+
+// GetByCodeInvitation mencari undangan yang masih berlaku tanpa memakainya.
+func (r *MemoryStore) GetByCodeInvitation(ctx context.Context, code string) (*models.HouseholdInvitation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error retrieving invitation", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.validInvitation(code)
+	if stored == nil {
+		return nil, ErrInvalidInvitation
+	}
+	invitation := stored.invitation
+	return &invitation, nil
+}
+
+// RedeemInvitation memakai kode undangan dan menjadikan userID anggota rumah
+// tangga. Kode hanya ditandai terpakai bila userID berhasil ditambahkan.
+func (r *MemoryStore) RedeemInvitation(ctx context.Context, code string, userID int) (*models.Membership, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error redeeming invitation", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.validInvitation(code)
+	if stored == nil {
+		return nil, ErrInvalidInvitation
+	}
+	householdID := stored.invitation.HouseholdID
+	if r.member(householdID, userID) != nil {
+		return nil, ErrAlreadyMember
+	}
+	stored.used = true
+	r.members[householdID] = append(r.members[householdID], &memoryMember{userID: userID, role: stored.invitation.Role, joinedAt: time.Now()})
+	return r.membership(userID, householdID), nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"daya-listrik-api/internal/models"
+	"slices"
+	"time"
+)
+
+type memoryMeterReading struct {
+	householdID int
+	reading     models.MeterReading
+}
+
+// AddMeterReading menyimpan pembacaan meter; read_at memakai waktu sekarang
+// bila tidak diisi.
+func (r *MemoryStore) AddMeterReading(ctx context.Context, householdID int, reading *models.MeterReading) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error inserting meter reading", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if reading.ReadAt.IsZero() {
+		reading.ReadAt = time.Now()
+	}
+	reading.ID = r.nextID("meter_readings")
+	r.meters[reading.ID] = &memoryMeterReading{householdID: householdID, reading: *reading}
+	return nil
+}
+
+func (r *MemoryStore) GetByIdMeterReading(ctx context.Context, householdID int, id string) (*models.MeterReading, error) {
+	if err := ctx.Err(); err != nil {
+		return &models.MeterReading{}, dbError("error retrieving meter reading", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.meters[memoryID(id)]
+	if stored == nil || stored.householdID != householdID {
+		return &models.MeterReading{}, NotFound("meter reading", id)
+	}
+	reading := stored.reading
+	return &reading, nil
+}
+
+func (r *MemoryStore) DeleteMeterReading(ctx context.Context, householdID int, id string) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error deleting meter reading", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.meters[memoryID(id)]
+	if stored == nil || stored.householdID != householdID {
+		return NotFound("meter reading", id)
+	}
+	delete(r.meters, stored.reading.ID)
+	return nil
+}
+
+func (r *MemoryStore) GetMeterReadings(ctx context.Context, householdID int) ([]models.MeterReading, error) {
+	return r.GetLatestMeterReadings(ctx, householdID, 0)
+}
+
+// GetLatestMeterReadings mengembalikan limit pembacaan terakhir, terbaru dulu.
+// Limit 0 berarti semua pembacaan.
+func (r *MemoryStore) GetLatestMeterReadings(ctx context.Context, householdID int, limit int) ([]models.MeterReading, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error fetching meter readings", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	readings := []models.MeterReading{}
+	for _, stored := range r.meters {
+		if stored.householdID == householdID {
+			readings = append(readings, stored.reading)
+		}
+	}
+	slices.SortFunc(readings, func(a, b models.MeterReading) int {
+		return cmp.Or(b.ReadAt.Compare(a.ReadAt), cmp.Compare(b.ID, a.ID))
+	})
+	if limit > 0 && len(readings) > limit {
+		readings = readings[:limit]
+	}
+	return readings, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"daya-listrik-api/internal/models"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore menyimpan semua data di memori proses (DB_DRIVER=memory) untuk
+// demo dan test; semua data hilang saat proses berhenti. MemoryStore memenuhi
+// semua interface repository dengan perilaku yang sama seperti repository
+// Postgres, termasuk id yang bertambah otomatis, error not found untuk data
+// rumah tangga lain dan penghapusan berantai saat rumah tangga dihapus. Rumah
+// tangga, perangkat dan tarif disimpan sekali dan dipakai bersama oleh semua
+// method, sehingga record memakai perangkat dari /api/devices dan golongan
+// tarif rumah tangga saat ini. Buat dengan NewMemoryStore; aman dipakai dari
+// banyak goroutine.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	users       map[int]*models.User
+	households  map[int]*models.Household
+	members     map[int][]*memoryMember
+	invitations map[int]*memoryInvitation
+	tariffs     []models.Tariff
+	devices     map[int]*memoryDevice
+	records     map[int]*memoryRecord
+	tokens      map[int]*memoryTokenPurchase
+	meters      map[int]*memoryMeterReading
+	apiKeys     map[int]*memoryAPIKey
+	// lastIDs adalah padanan sequence SERIAL per tabel.
+	lastIDs map[string]int
+}
+
+// memorySeedTariffs sama dengan tarif di migrasi 003.
+var memorySeedTariffs = []struct {
+	class        string
+	minVA, maxVA int
+	price        float64
+}{
+	{models.TariffR1_450VA, 450, 450, 415.00},
+	{models.TariffR1_900VA, 900, 900, 605.00},
+	{models.TariffR1_900VARTM, 900, 900, 1352.00},
+	{models.TariffR1_1300VA, 1300, 1300, 1444.70},
+	{models.TariffR1_2200VA, 2200, 2200, 1444.70},
+	{models.TariffR2, 3500, 5500, 1699.53},
+	{models.TariffR3, 6600, 0, 1699.53},
+}
+
+// NewMemoryStore membuat MemoryStore kosong berisi tarif awal yang sama
+// dengan migrasi Postgres.
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		users:       map[int]*models.User{},
+		households:  map[int]*models.Household{},
+		members:     map[int][]*memoryMember{},
+		invitations: map[int]*memoryInvitation{},
+		devices:     map[int]*memoryDevice{},
+		records:     map[int]*memoryRecord{},
+		tokens:      map[int]*memoryTokenPurchase{},
+		meters:      map[int]*memoryMeterReading{},
+		apiKeys:     map[int]*memoryAPIKey{},
+		lastIDs:     map[string]int{},
+	}
+	effective := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, seed := range memorySeedTariffs {
+		tariff := models.Tariff{ID: s.nextID("tariffs"), Class: seed.class, MinVA: seed.minVA, PricePerKWh: seed.price, EffectiveFrom: effective}
+		if seed.maxVA > 0 {
+			maxVA := seed.maxVA
+			tariff.MaxVA = &maxVA
+		}
+		s.tariffs = append(s.tariffs, tariff)
+	}
+	return s
+}
+
+// NewMemoryRepositories memakai store untuk semua repository.
+func NewMemoryRepositories(store *MemoryStore) Repositories {
+	return Repositories{
+		Records:    store,
+		Devices:    store,
+		Tariffs:    store,
+		Tokens:     store,
+		Meters:     store,
+		Users:      store,
+		Households: store,
+		APIKeys:    store,
+	}
+}
+
+// nextID mengembalikan id berikutnya untuk table. Dipanggil dengan mu
+// terkunci untuk menulis.
+func (r *MemoryStore) nextID(table string) int {
+	r.lastIDs[table]++
+	return r.lastIDs[table]
+}
+
+// memoryID membaca id dari path; id yang tidak valid tidak cocok dengan data
+// apa pun.
+func memoryID(id string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(id))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// copyTime menyalin waktu opsional agar pemanggil tidak bisa mengubah data
+// yang tersimpan.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
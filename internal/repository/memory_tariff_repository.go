@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// memoryDate adalah padanan kolom DATE: tanggal t di UTC pada tengah malam.
+func memoryDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// copyTariff menyalin tarif beserta max_va-nya.
+func copyTariff(tariff models.Tariff) models.Tariff {
+	if tariff.MaxVA != nil {
+		maxVA := *tariff.MaxVA
+		tariff.MaxVA = &maxVA
+	}
+	return tariff
+}
+
+// effectiveTariff mengembalikan tarif golongan class yang berlaku pada at, atau
+// nil. Dipanggil dengan mu terkunci.
+func (r *MemoryStore) effectiveTariff(class string, at time.Time) *models.Tariff {
+	var effective *models.Tariff
+	for i, tariff := range r.tariffs {
+		if tariff.Class == class && !tariff.EffectiveFrom.After(at) &&
+			(effective == nil || tariff.EffectiveFrom.After(effective.EffectiveFrom)) {
+			effective = &r.tariffs[i]
+		}
+	}
+	return effective
+}
+
+func (r *MemoryStore) AddTariff(ctx context.Context, tariff *models.Tariff) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error inserting tariff", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	effectiveFrom := memoryDate(tariff.EffectiveFrom)
+	for _, existing := range r.tariffs {
+		if existing.Class == tariff.Class && existing.EffectiveFrom.Equal(effectiveFrom) {
+			return dbError("error inserting tariff", errors.New("duplicate class and effective_from"))
+		}
+	}
+	tariff.ID = r.nextID("tariffs")
+	stored := copyTariff(*tariff)
+	stored.EffectiveFrom = effectiveFrom
+	r.tariffs = append(r.tariffs, stored)
+	return nil
+}
+
+func (r *MemoryStore) GetByIdTariff(ctx context.Context, id string) (*models.Tariff, error) {
+	if err := ctx.Err(); err != nil {
+		return &models.Tariff{}, dbError("error retrieving tariff", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tariffID := memoryID(id)
+	for _, tariff := range r.tariffs {
+		if tariff.ID == tariffID {
+			tariff = copyTariff(tariff)
+			return &tariff, nil
+		}
+	}
+	return &models.Tariff{}, NotFound("tariff", id)
+}
+
+// GetTariffs mengembalikan riwayat tarif, opsional difilter per golongan.
+func (r *MemoryStore) GetTariffs(ctx context.Context, class string) ([]models.Tariff, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error fetching tariffs", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tariffs := []models.Tariff{}
+	for _, tariff := range r.tariffs {
+		if class == "" || tariff.Class == class {
+			tariffs = append(tariffs, copyTariff(tariff))
+		}
+	}
+	slices.SortFunc(tariffs, func(a, b models.Tariff) int {
+		return cmp.Or(strings.Compare(a.Class, b.Class), a.EffectiveFrom.Compare(b.EffectiveFrom))
+	})
+	return tariffs, nil
+}
+
+// GetEffectiveTariff mengembalikan tarif golongan class yang berlaku pada waktu at.
+func (r *MemoryStore) GetEffectiveTariff(ctx context.Context, class string, at time.Time) (*models.Tariff, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error retrieving tariff", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	effective := r.effectiveTariff(class, at)
+	if effective == nil {
+		return nil, newError(ErrNotFound, "tariff_not_in_effect", i18n.Params{"class": class, "date": at.Format("2006-01-02")})
+	}
+	tariff := copyTariff(*effective)
+	return &tariff, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"daya-listrik-api/internal/models"
+	"slices"
+	"time"
+)
+
+type memoryTokenPurchase struct {
+	householdID int
+	purchase    models.TokenPurchase
+}
+
+// AddTokenPurchase menyimpan pembelian token; purchased_at memakai waktu
+// sekarang bila tidak diisi.
+func (r *MemoryStore) AddTokenPurchase(ctx context.Context, householdID int, purchase *models.TokenPurchase) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error inserting token purchase", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if purchase.PurchasedAt.IsZero() {
+		purchase.PurchasedAt = time.Now()
+	}
+	purchase.ID = r.nextID("token_purchases")
+	r.tokens[purchase.ID] = &memoryTokenPurchase{householdID: householdID, purchase: *purchase}
+	return nil
+}
+
+func (r *MemoryStore) GetByIdTokenPurchase(ctx context.Context, householdID int, id string) (*models.TokenPurchase, error) {
+	if err := ctx.Err(); err != nil {
+		return &models.TokenPurchase{}, dbError("error retrieving token purchase", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.tokens[memoryID(id)]
+	if stored == nil || stored.householdID != householdID {
+		return &models.TokenPurchase{}, NotFound("token purchase", id)
+	}
+	purchase := stored.purchase
+	return &purchase, nil
+}
+
+func (r *MemoryStore) DeleteTokenPurchase(ctx context.Context, householdID int, id string) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error deleting token purchase", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.tokens[memoryID(id)]
+	if stored == nil || stored.householdID != householdID {
+		return NotFound("token purchase", id)
+	}
+	delete(r.tokens, stored.purchase.ID)
+	return nil
+}
+
+// GetTokenPurchases mengembalikan pembelian token, terbaru dulu.
+func (r *MemoryStore) GetTokenPurchases(ctx context.Context, householdID int) ([]models.TokenPurchase, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error fetching token purchases", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	purchases := []models.TokenPurchase{}
+	for _, stored := range r.tokens {
+		if stored.householdID == householdID {
+			purchases = append(purchases, stored.purchase)
+		}
+	}
+	slices.SortFunc(purchases, func(a, b models.TokenPurchase) int {
+		return cmp.Or(b.PurchasedAt.Compare(a.PurchasedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return purchases, nil
+}
+
+// GetTokenTotals menjumlahkan kWh dari semua pembelian token rumah tangga.
+func (r *MemoryStore) GetTokenTotals(ctx context.Context, householdID int) (*models.TokenTotals, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error summarizing token purchases", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totals := &models.TokenTotals{}
+	for _, stored := range r.tokens {
+		if stored.householdID != householdID {
+			continue
+		}
+		purchasedAt := stored.purchase.PurchasedAt
+		if totals.PurchaseCount == 0 || purchasedAt.Before(totals.FirstPurchase) {
+			totals.FirstPurchase = purchasedAt
+		}
+		if totals.PurchaseCount == 0 || purchasedAt.After(totals.LatestPurchase) {
+			totals.LatestPurchase = purchasedAt
+		}
+		totals.PurchaseCount++
+		totals.KWhCredited += stored.purchase.KWhCredited
+	}
+	return totals, nil
+}
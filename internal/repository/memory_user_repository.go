@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+	"strings"
+	"time"
+)
+
+// findUser mencari user berdasarkan email tanpa membedakan huruf besar/kecil.
+// Dipanggil dengan mu terkunci.
+func (r *MemoryStore) findUser(email string) *models.User {
+	email = strings.TrimSpace(email)
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return user
+		}
+	}
+	return nil
+}
+
+// AddUser menyimpan user baru. User baru tidak pernah menjadi admin.
+func (r *MemoryStore) AddUser(ctx context.Context, user *models.User) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error inserting user", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.findUser(user.Email) != nil {
+		return ErrEmailTaken
+	}
+	user.ID = r.nextID("users")
+	user.Email = strings.TrimSpace(user.Email)
+	user.IsAdmin = false
+	user.CreatedAt = time.Now()
+	stored := *user
+	r.users[user.ID] = &stored
+	return nil
+}
+
+// SetAdmin memberi atau mencabut status admin user dengan email tersebut.
+func (r *MemoryStore) SetAdmin(ctx context.Context, email string, admin bool) error {
+	if err := ctx.Err(); err != nil {
+		return dbError("error updating user", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.findUser(email)
+	if user == nil {
+		return ErrUserNotFound
+	}
+	user.IsAdmin = admin
+	return nil
+}
+
+func (r *MemoryStore) GetByIdUser(ctx context.Context, id int) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error retrieving user", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.users[id]
+	if stored == nil {
+		return nil, ErrUserNotFound
+	}
+	user := *stored
+	return &user, nil
+}
+
+// GetByEmailUser mencari user berdasarkan email tanpa membedakan huruf besar/kecil.
+func (r *MemoryStore) GetByEmailUser(ctx context.Context, email string) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, dbError("error retrieving user", err)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.findUser(email)
+	if stored == nil {
+		return nil, ErrUserNotFound
+	}
+	user := *stored
+	return &user, nil
+}
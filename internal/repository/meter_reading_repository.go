@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"time"
+)
+
+// MeterReadingRepositoryInterface selalu dibatasi pada pembacaan meter milik
+// householdID.
+type MeterReadingRepositoryInterface interface {
+	AddMeterReading(ctx context.Context, householdID int, reading *models.MeterReading) error
+	GetByIdMeterReading(ctx context.Context, householdID int, id string) (*models.MeterReading, error)
+	DeleteMeterReading(ctx context.Context, householdID int, id string) error
+	GetMeterReadings(ctx context.Context, householdID int) ([]models.MeterReading, error)
+	GetLatestMeterReadings(ctx context.Context, householdID int, limit int) ([]models.MeterReading, error)
+}
+
+type MeterReadingRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+const selectMeterReadingQuery = `SELECT id, read_at, cumulative_kwh, photo_ref FROM meter_readings`
+
+func scanMeterReading(row interface{ Scan(...any) error }, reading *models.MeterReading) error {
+	return row.Scan(&reading.ID, &reading.ReadAt, &reading.CumulativeKWh, &reading.PhotoRef)
+}
+
+// AddMeterReading menyimpan pembacaan meter; read_at memakai NOW() bila
+// tidak diisi.
+func (r *MeterReadingRepository) AddMeterReading(ctx context.Context, householdID int, reading *models.MeterReading) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `INSERT INTO meter_readings (read_at, cumulative_kwh, photo_ref, household_id) VALUES (COALESCE($1, NOW()), $2, $3, $4) RETURNING id, read_at`
+	readAt := sql.NullTime{Time: reading.ReadAt, Valid: !reading.ReadAt.IsZero()}
+	err := r.DB.QueryRowContext(ctx, query, readAt, reading.CumulativeKWh, reading.PhotoRef, householdID).Scan(&reading.ID, &reading.ReadAt)
+	if err != nil {
+		return dbError("error inserting meter reading", err)
+	}
+	return nil
+}
+
+func (r *MeterReadingRepository) GetByIdMeterReading(ctx context.Context, householdID int, id string) (*models.MeterReading, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	reading := &models.MeterReading{}
+	err := scanMeterReading(r.DB.QueryRowContext(ctx, selectMeterReadingQuery+` WHERE id = $1 AND household_id = $2`, id, householdID), reading)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &models.MeterReading{}, NotFound("meter reading", id)
+		}
+		return &models.MeterReading{}, dbError("error retrieving meter reading", err)
+	}
+	return reading, nil
+}
+
+func (r *MeterReadingRepository) DeleteMeterReading(ctx context.Context, householdID int, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `DELETE FROM meter_readings WHERE id = $1 AND household_id = $2`
+	result, err := r.DB.ExecContext(ctx, query, id, householdID)
+	if err != nil {
+		return dbError("error deleting meter reading", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return NotFound("meter reading", id)
+	}
+
+	return nil
+}
+
+func (r *MeterReadingRepository) GetMeterReadings(ctx context.Context, householdID int) ([]models.MeterReading, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	return r.queryMeterReadings(ctx, selectMeterReadingQuery+` WHERE household_id = $1 ORDER BY read_at DESC, id DESC`, householdID)
+}
+
+// GetLatestMeterReadings mengembalikan limit pembacaan terakhir, terbaru dulu.
+func (r *MeterReadingRepository) GetLatestMeterReadings(ctx context.Context, householdID int, limit int) ([]models.MeterReading, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	return r.queryMeterReadings(ctx, selectMeterReadingQuery+` WHERE household_id = $1 ORDER BY read_at DESC, id DESC LIMIT $2`, householdID, limit)
+}
+
+func (r *MeterReadingRepository) queryMeterReadings(ctx context.Context, query string, args ...any) ([]models.MeterReading, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, dbError("error fetching meter readings", err)
+	}
+	defer rows.Close()
+
+	var readings []models.MeterReading
+	for rows.Next() {
+		var reading models.MeterReading
+		if err := scanMeterReading(rows, &reading); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		readings = append(readings, reading)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	if readings == nil {
+		readings = []models.MeterReading{}
+	}
+	return readings, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var meterReadingColumns = []string{"id", "read_at", "cumulative_kwh", "photo_ref"}
+
+func TestAddMeterReading(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &MeterReadingRepository{DB: db}
+	query := `INSERT INTO meter_readings (read_at, cumulative_kwh, photo_ref, household_id) VALUES (COALESCE($1, NOW()), $2, $3, $4) RETURNING id, read_at`
+	readAt := time.Date(2026, 10, 1, 7, 0, 0, 0, time.UTC)
+	reading := &models.MeterReading{ReadAt: readAt, CumulativeKWh: 12000.5, PhotoRef: "meter/2026-10-01.jpg"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(sql.NullTime{Time: readAt, Valid: true}, reading.CumulativeKWh, reading.PhotoRef, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "read_at"}).AddRow(1, readAt))
+
+	err = repo.AddMeterReading(context.Background(), 1, reading)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reading.ID)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(errors.New("insert error"))
+
+	err = repo.AddMeterReading(context.Background(), 1, reading)
+	assert.Error(t, err)
+}
+
+func TestGetByIdMeterReading(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &MeterReadingRepository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectMeterReadingQuery+` WHERE id = $1 AND household_id = $2`)).WithArgs("1", 1).
+		WillReturnRows(sqlmock.NewRows(meterReadingColumns).AddRow(1, time.Now(), 12000.5, ""))
+
+	reading, err := repo.GetByIdMeterReading(context.Background(), 1, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 12000.5, reading.CumulativeKWh)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectMeterReadingQuery+` WHERE id = $1 AND household_id = $2`)).WithArgs("2", 1).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByIdMeterReading(context.Background(), 1, "2")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestDeleteMeterReading(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &MeterReadingRepository{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM meter_readings WHERE id = $1 AND household_id = $2`)).WithArgs("1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteMeterReading(context.Background(), 1, "1"))
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM meter_readings WHERE id = $1 AND household_id = $2`)).WithArgs("1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = repo.DeleteMeterReading(context.Background(), 1, "1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestGetMeterReadings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &MeterReadingRepository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectMeterReadingQuery + ` WHERE household_id = $1 ORDER BY read_at DESC, id DESC`)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(meterReadingColumns).
+			AddRow(2, time.Now(), 12050.0, "").
+			AddRow(1, time.Now().AddDate(0, 0, -7), 12000.0, ""))
+
+	readings, err := repo.GetMeterReadings(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, readings, 2)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectMeterReadingQuery+` WHERE household_id = $1 ORDER BY read_at DESC, id DESC LIMIT $2`)).WithArgs(1, 2).
+		WillReturnError(errors.New("query error"))
+
+	_, err = repo.GetLatestMeterReadings(context.Background(), 1, 2)
+	assert.Error(t, err)
+}
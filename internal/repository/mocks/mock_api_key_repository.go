@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) AddAPIKey(ctx context.Context, key *models.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeys(ctx context.Context, householdID int) ([]models.APIKey, error) {
+	args := m.Called(ctx, householdID)
+	keys, _ := args.Get(0).([]models.APIKey)
+	return keys, args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) DeleteAPIKey(ctx context.Context, householdID int, id string) error {
+	args := m.Called(ctx, householdID, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) AuthenticateAPIKey(ctx context.Context, raw string) (*models.APIKey, *models.Household, error) {
+	args := m.Called(ctx, raw)
+	key, _ := args.Get(0).(*models.APIKey)
+	household, _ := args.Get(1).(*models.Household)
+	return key, household, args.Error(2)
+}
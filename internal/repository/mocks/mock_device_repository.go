@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeviceRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceRepository) AddDevice(ctx context.Context, householdID int, device *models.Device) error {
+	args := m.Called(ctx, householdID, device)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) GetDevices(ctx context.Context, householdID int) ([]models.Device, error) {
+	args := m.Called(ctx, householdID)
+	return args.Get(0).([]models.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) GetByIdDevice(ctx context.Context, householdID int, id string) (*models.Device, error) {
+	args := m.Called(ctx, householdID, id)
+	return args.Get(0).(*models.Device), args.Error(1)
+}
+
+func (m *MockDeviceRepository) UpdateDevice(ctx context.Context, householdID int, device *models.Device) error {
+	args := m.Called(ctx, householdID, device)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) DeleteDevice(ctx context.Context, householdID int, id string) error {
+	args := m.Called(ctx, householdID, id)
+	return args.Error(0)
+}
@@ -0,0 +1,72 @@
+package mocks
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHouseholdRepository struct {
+	mock.Mock
+}
+
+func (m *MockHouseholdRepository) AddHousehold(ctx context.Context, ownerID int, household *models.Household) error {
+	args := m.Called(ctx, ownerID, household)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdRepository) GetHouseholds(ctx context.Context, userID int) ([]models.Membership, error) {
+	args := m.Called(ctx, userID)
+	memberships, _ := args.Get(0).([]models.Membership)
+	return memberships, args.Error(1)
+}
+
+func (m *MockHouseholdRepository) GetMembership(ctx context.Context, userID, householdID int) (*models.Membership, error) {
+	args := m.Called(ctx, userID, householdID)
+	membership, _ := args.Get(0).(*models.Membership)
+	return membership, args.Error(1)
+}
+
+func (m *MockHouseholdRepository) UpdateHousehold(ctx context.Context, household *models.Household) error {
+	args := m.Called(ctx, household)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdRepository) DeleteHousehold(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdRepository) GetMembers(ctx context.Context, householdID int) ([]models.HouseholdMember, error) {
+	args := m.Called(ctx, householdID)
+	members, _ := args.Get(0).([]models.HouseholdMember)
+	return members, args.Error(1)
+}
+
+func (m *MockHouseholdRepository) UpdateMemberRole(ctx context.Context, householdID, userID int, role string) error {
+	args := m.Called(ctx, householdID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdRepository) DeleteMember(ctx context.Context, householdID, userID int) error {
+	args := m.Called(ctx, householdID, userID)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdRepository) AddInvitation(ctx context.Context, invitation *models.HouseholdInvitation) error {
+	args := m.Called(ctx, invitation)
+	return args.Error(0)
+}
+
+func (m *MockHouseholdRepository) GetByCodeInvitation(ctx context.Context, code string) (*models.HouseholdInvitation, error) {
+	args := m.Called(ctx, code)
+	invitation, _ := args.Get(0).(*models.HouseholdInvitation)
+	return invitation, args.Error(1)
+}
+
+func (m *MockHouseholdRepository) RedeemInvitation(ctx context.Context, code string, userID int) (*models.Membership, error) {
+	args := m.Called(ctx, code, userID)
+	membership, _ := args.Get(0).(*models.Membership)
+	return membership, args.Error(1)
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMeterReadingRepository struct {
+	mock.Mock
+}
+
+func (m *MockMeterReadingRepository) AddMeterReading(ctx context.Context, householdID int, reading *models.MeterReading) error {
+	args := m.Called(ctx, householdID, reading)
+	return args.Error(0)
+}
+
+func (m *MockMeterReadingRepository) GetByIdMeterReading(ctx context.Context, householdID int, id string) (*models.MeterReading, error) {
+	args := m.Called(ctx, householdID, id)
+	return args.Get(0).(*models.MeterReading), args.Error(1)
+}
+
+func (m *MockMeterReadingRepository) DeleteMeterReading(ctx context.Context, householdID int, id string) error {
+	args := m.Called(ctx, householdID, id)
+	return args.Error(0)
+}
+
+func (m *MockMeterReadingRepository) GetMeterReadings(ctx context.Context, householdID int) ([]models.MeterReading, error) {
+	args := m.Called(ctx, householdID)
+	return args.Get(0).([]models.MeterReading), args.Error(1)
+}
+
+func (m *MockMeterReadingRepository) GetLatestMeterReadings(ctx context.Context, householdID int, limit int) ([]models.MeterReading, error) {
+	args := m.Called(ctx, householdID, limit)
+	return args.Get(0).([]models.MeterReading), args.Error(1)
+}
@@ -1,7 +1,10 @@
 package mocks
 
 import (
+	"context"
 	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -10,27 +13,45 @@ type MockEnergyRecordRepository struct {
 	mock.Mock // *** embed testify.Mock supaya bisa pakai On, AssertExpectations, dll ***
 }
 
-func (m *MockEnergyRecordRepository) AddRecord(record *models.EnergyRecord) error {
-	args := m.Called(record)
+func (m *MockEnergyRecordRepository) AddRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	args := m.Called(ctx, householdID, record)
 	return args.Error(0)
 }
 
-func (m *MockEnergyRecordRepository) GetRecords() ([]models.EnergyRecord, error) {
-	args := m.Called()
-	return args.Get(0).([]models.EnergyRecord), args.Error(1)
+func (m *MockEnergyRecordRepository) GetRecords(ctx context.Context, householdID int, query repository.RecordQuery) (*repository.RecordPage, error) {
+	args := m.Called(ctx, householdID, query)
+	page, _ := args.Get(0).(*repository.RecordPage)
+	return page, args.Error(1)
 }
 
-func (m *MockEnergyRecordRepository) GetByIdRecord(id string) (*models.EnergyRecord, error) {
-	args := m.Called(id)
+func (m *MockEnergyRecordRepository) GetByIdRecord(ctx context.Context, householdID int, id string) (*models.EnergyRecord, error) {
+	args := m.Called(ctx, householdID, id)
 	return args.Get(0).(*models.EnergyRecord), args.Error(1)
 }
 
-func (m *MockEnergyRecordRepository) UpdateRecord(record *models.EnergyRecord) error {
-	args := m.Called(record)
+func (m *MockEnergyRecordRepository) UpdateRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	args := m.Called(ctx, householdID, record)
 	return args.Error(0)
 }
 
-func (m *MockEnergyRecordRepository) DeleteRecord(id string) error {
-	args := m.Called(id)
+func (m *MockEnergyRecordRepository) DeleteRecord(ctx context.Context, householdID int, id string) error {
+	args := m.Called(ctx, householdID, id)
 	return args.Error(0)
 }
+
+func (m *MockEnergyRecordRepository) SummarizeRecords(ctx context.Context, householdID int, from, to time.Time) (*models.UsageSummary, error) {
+	args := m.Called(ctx, householdID, from, to)
+	summary, _ := args.Get(0).(*models.UsageSummary)
+	return summary, args.Error(1)
+}
+
+func (m *MockEnergyRecordRepository) GetUsageBuckets(ctx context.Context, householdID int, query repository.UsageBucketQuery) ([]models.UsageBucket, error) {
+	args := m.Called(ctx, householdID, query)
+	buckets, _ := args.Get(0).([]models.UsageBucket)
+	return buckets, args.Error(1)
+}
+
+func (m *MockEnergyRecordRepository) GetActiveRecords(ctx context.Context, householdID int, from, to time.Time) ([]models.EnergyRecord, error) {
+	args := m.Called(ctx, householdID, from, to)
+	return args.Get(0).([]models.EnergyRecord), args.Error(1)
+}
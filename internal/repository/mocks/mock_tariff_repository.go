@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTariffRepository struct {
+	mock.Mock
+}
+
+func (m *MockTariffRepository) AddTariff(ctx context.Context, tariff *models.Tariff) error {
+	args := m.Called(ctx, tariff)
+	return args.Error(0)
+}
+
+func (m *MockTariffRepository) GetByIdTariff(ctx context.Context, id string) (*models.Tariff, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Tariff), args.Error(1)
+}
+
+func (m *MockTariffRepository) GetTariffs(ctx context.Context, class string) ([]models.Tariff, error) {
+	args := m.Called(ctx, class)
+	return args.Get(0).([]models.Tariff), args.Error(1)
+}
+
+func (m *MockTariffRepository) GetEffectiveTariff(ctx context.Context, class string, at time.Time) (*models.Tariff, error) {
+	args := m.Called(ctx, class, at)
+	tariff, _ := args.Get(0).(*models.Tariff)
+	return tariff, args.Error(1)
+}
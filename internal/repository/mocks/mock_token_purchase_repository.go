@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTokenPurchaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenPurchaseRepository) AddTokenPurchase(ctx context.Context, householdID int, purchase *models.TokenPurchase) error {
+	args := m.Called(ctx, householdID, purchase)
+	return args.Error(0)
+}
+
+func (m *MockTokenPurchaseRepository) GetByIdTokenPurchase(ctx context.Context, householdID int, id string) (*models.TokenPurchase, error) {
+	args := m.Called(ctx, householdID, id)
+	return args.Get(0).(*models.TokenPurchase), args.Error(1)
+}
+
+func (m *MockTokenPurchaseRepository) DeleteTokenPurchase(ctx context.Context, householdID int, id string) error {
+	args := m.Called(ctx, householdID, id)
+	return args.Error(0)
+}
+
+func (m *MockTokenPurchaseRepository) GetTokenPurchases(ctx context.Context, householdID int) ([]models.TokenPurchase, error) {
+	args := m.Called(ctx, householdID)
+	return args.Get(0).([]models.TokenPurchase), args.Error(1)
+}
+
+func (m *MockTokenPurchaseRepository) GetTokenTotals(ctx context.Context, householdID int) (*models.TokenTotals, error) {
+	args := m.Called(ctx, householdID)
+	totals, _ := args.Get(0).(*models.TokenTotals)
+	return totals, args.Error(1)
+}
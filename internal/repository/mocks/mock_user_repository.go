@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) AddUser(ctx context.Context, user *models.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByIdUser(ctx context.Context, id int) (*models.User, error) {
+	args := m.Called(ctx, id)
+	user, _ := args.Get(0).(*models.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmailUser(ctx context.Context, email string) (*models.User, error) {
+	args := m.Called(ctx, email)
+	user, _ := args.Get(0).(*models.User)
+	return user, args.Error(1)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Repositories adalah semua repository yang dipakai server untuk satu
+// DB_DRIVER. Semua field selalu terisi.
+type Repositories struct {
+	Records    EnergyRecordRepositoryInterface
+	Devices    DeviceRepositoryInterface
+	Tariffs    TariffRepositoryInterface
+	Tokens     TokenPurchaseRepositoryInterface
+	Meters     MeterReadingRepositoryInterface
+	Users      UserRepositoryInterface
+	Households HouseholdRepositoryInterface
+	APIKeys    APIKeyRepositoryInterface
+}
+
+// NewPostgresRepositories membuat repository untuk database PostgreSQL yang
+// sudah dimigrasi.
+func NewPostgresRepositories(db *sql.DB, timeout time.Duration) Repositories {
+	return Repositories{
+		Records:    &EnergyRecordRepository{DB: db, Timeout: timeout},
+		Devices:    &DeviceRepository{DB: db, Timeout: timeout},
+		Tariffs:    &TariffRepository{DB: db, Timeout: timeout},
+		Tokens:     &TokenPurchaseRepository{DB: db, Timeout: timeout},
+		Meters:     &MeterReadingRepository{DB: db, Timeout: timeout},
+		Users:      &UserRepository{DB: db, Timeout: timeout},
+		Households: &HouseholdRepository{DB: db, Timeout: timeout},
+		APIKeys:    &APIKeyRepository{DB: db, Timeout: timeout},
+	}
+}
+
+// NewSQLiteRepositories membuat repository untuk file SQLite yang sudah
+// dimigrasi dengan migrations.SQLite. Perangkat dan user memakai repository
+// Postgres karena query-nya berlaku untuk keduanya.
+func NewSQLiteRepositories(db *sql.DB, timeout time.Duration) Repositories {
+	return Repositories{
+		Records:    &SQLiteEnergyRecordRepository{DB: db, Timeout: timeout},
+		Devices:    &DeviceRepository{DB: db, Timeout: timeout},
+		Tariffs:    &SQLiteTariffRepository{TariffRepository{DB: db, Timeout: timeout}},
+		Tokens:     &SQLiteTokenPurchaseRepository{TokenPurchaseRepository{DB: db, Timeout: timeout}},
+		Meters:     &SQLiteMeterReadingRepository{MeterReadingRepository{DB: db, Timeout: timeout}},
+		Users:      &UserRepository{DB: db, Timeout: timeout},
+		Households: &SQLiteHouseholdRepository{HouseholdRepository{DB: db, Timeout: timeout}},
+		APIKeys:    &SQLiteAPIKeyRepository{DB: db, Timeout: timeout},
+	}
+}
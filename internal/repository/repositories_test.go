@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHousehold membuat user dan rumah tangga miliknya lewat repos.
+func newTestHousehold(t *testing.T, repos Repositories, email string) (*models.User, *models.Household) {
+	t.Helper()
+	ctx := context.Background()
+	user := &models.User{Email: email, PasswordHash: "hash"}
+	require.NoError(t, repos.Users.AddUser(ctx, user))
+	household := &models.Household{Name: "Rumah", TariffClass: models.DefaultTariffClass, Timezone: "Asia/Jakarta", BillingDay: 1}
+	require.NoError(t, repos.Households.AddHousehold(ctx, user.ID, household))
+	return user, household
+}
+
+// TestRepositories menjalankan test yang sama untuk setiap driver tanpa
+// PostgreSQL.
+func TestRepositories(t *testing.T) {
+	drivers := map[string]func(t *testing.T) Repositories{
+		"sqlite": func(t *testing.T) Repositories { return NewSQLiteRepositories(newTestSQLite(t), DefaultQueryTimeout) },
+		"memory": func(t *testing.T) Repositories { return NewMemoryRepositories(NewMemoryStore()) },
+	}
+	tests := map[string]func(t *testing.T, repos Repositories){
+		"UsersAndHouseholds":  testRepositoriesUsersAndHouseholds,
+		"APIKeys":             testRepositoriesAPIKeys,
+		"TariffsTokensMeters": testRepositoriesTariffsTokensMeters,
+		"DeviceNameTaken":     testRepositoriesDeviceNameTaken,
+	}
+	for driver, newRepos := range drivers {
+		for name, test := range tests {
+			t.Run(driver+"/"+name, func(t *testing.T) { test(t, newRepos(t)) })
+		}
+	}
+}
+
+func testRepositoriesUsersAndHouseholds(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	owner, household := newTestHousehold(t, repos, "owner@example.com")
+
+	assert.False(t, owner.IsAdmin)
+	assert.False(t, household.CreatedAt.IsZero())
+	assert.ErrorIs(t, repos.Users.AddUser(ctx, &models.User{Email: "OWNER@example.com", PasswordHash: "hash"}), ErrEmailTaken)
+
+	invitation := &models.HouseholdInvitation{HouseholdID: household.ID, Role: models.RoleViewer, CreatedBy: owner.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repos.Households.AddInvitation(ctx, invitation))
+	got, err := repos.Households.GetByCodeInvitation(ctx, invitation.Code)
+	require.NoError(t, err)
+	assert.Equal(t, household.ID, got.HouseholdID)
+
+	member := &models.User{Email: "member@example.com", PasswordHash: "hash"}
+	require.NoError(t, repos.Users.AddUser(ctx, member))
+	membership, err := repos.Households.RedeemInvitation(ctx, invitation.Code, member.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleViewer, membership.Role)
+	_, err = repos.Households.RedeemInvitation(ctx, invitation.Code, member.ID)
+	assert.ErrorIs(t, err, ErrInvalidInvitation, "codes are single use")
+
+	expired := &models.HouseholdInvitation{HouseholdID: household.ID, Role: models.RoleEditor, CreatedBy: owner.ID, ExpiresAt: time.Now().Add(-time.Minute)}
+	require.NoError(t, repos.Households.AddInvitation(ctx, expired))
+	_, err = repos.Households.GetByCodeInvitation(ctx, expired.Code)
+	assert.ErrorIs(t, err, ErrInvalidInvitation)
+
+	again := &models.HouseholdInvitation{HouseholdID: household.ID, Role: models.RoleEditor, CreatedBy: owner.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repos.Households.AddInvitation(ctx, again))
+	_, err = repos.Households.RedeemInvitation(ctx, again.Code, member.ID)
+	assert.ErrorIs(t, err, ErrAlreadyMember)
+}
+
+func testRepositoriesAPIKeys(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	owner, household := newTestHousehold(t, repos, "owner@example.com")
+
+	key := &models.APIKey{HouseholdID: household.ID, Label: "Smart plug", Scopes: []string{models.ScopeRecordsWrite}, CreatedBy: owner.ID}
+	require.NoError(t, repos.APIKeys.AddAPIKey(ctx, key))
+
+	got, h, err := repos.APIKeys.AuthenticateAPIKey(ctx, key.Key)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, got.ID)
+	assert.Equal(t, []string{models.ScopeRecordsWrite}, got.Scopes)
+	require.NotNil(t, got.LastUsedAt)
+	assert.Equal(t, household.ID, h.ID)
+	assert.Equal(t, household.TariffClass, h.TariffClass)
+
+	require.NoError(t, repos.APIKeys.DeleteAPIKey(ctx, household.ID, strconv.Itoa(key.ID)))
+	_, _, err = repos.APIKeys.AuthenticateAPIKey(ctx, key.Key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	keys, err := repos.APIKeys.GetAPIKeys(ctx, household.ID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].RevokedAt)
+
+	expiresAt := time.Now().Add(-time.Minute)
+	expired := &models.APIKey{HouseholdID: household.ID, Label: "Lama", CreatedBy: owner.ID, ExpiresAt: &expiresAt}
+	require.NoError(t, repos.APIKeys.AddAPIKey(ctx, expired))
+	_, _, err = repos.APIKeys.AuthenticateAPIKey(ctx, expired.Key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func testRepositoriesTariffsTokensMeters(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	_, household := newTestHousehold(t, repos, "owner@example.com")
+
+	tariff := &models.Tariff{Class: models.TariffR1_1300VA, MinVA: 1300, MaxVA: &[]int{1300}[0], PricePerKWh: 1500, EffectiveFrom: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)}
+	require.NoError(t, repos.Tariffs.AddTariff(ctx, tariff))
+	effective, err := repos.Tariffs.GetEffectiveTariff(ctx, models.TariffR1_1300VA, time.Date(2024, 6, 30, 23, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1444.70, effective.PricePerKWh)
+	effective, err = repos.Tariffs.GetEffectiveTariff(ctx, models.TariffR1_1300VA, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, tariff.ID, effective.ID)
+	assert.True(t, tariff.EffectiveFrom.Equal(effective.EffectiveFrom))
+	_, err = repos.Tariffs.GetEffectiveTariff(ctx, models.TariffR1_1300VA, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	totals, err := repos.Tokens.GetTokenTotals(ctx, household.ID)
+	require.NoError(t, err)
+	assert.Zero(t, totals.PurchaseCount)
+	assert.True(t, totals.FirstPurchase.IsZero())
+
+	first := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, repos.Tokens.AddTokenPurchase(ctx, household.ID, &models.TokenPurchase{TokenNumber: "12345678901234567890", AmountPaid: 100000, KWhCredited: 65, PurchasedAt: first}))
+	latest := &models.TokenPurchase{TokenNumber: "12345678901234567891", AmountPaid: 50000, KWhCredited: 32}
+	require.NoError(t, repos.Tokens.AddTokenPurchase(ctx, household.ID, latest))
+	assert.WithinDuration(t, time.Now(), latest.PurchasedAt, time.Minute, "purchased_at defaults to now")
+	totals, err = repos.Tokens.GetTokenTotals(ctx, household.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, totals.PurchaseCount)
+	assert.Equal(t, 97.0, totals.KWhCredited)
+	assert.True(t, first.Equal(totals.FirstPurchase))
+	assert.True(t, latest.PurchasedAt.Equal(totals.LatestPurchase))
+
+	reading := &models.MeterReading{CumulativeKWh: 1234.5}
+	require.NoError(t, repos.Meters.AddMeterReading(ctx, household.ID, reading))
+	assert.WithinDuration(t, time.Now(), reading.ReadAt, time.Minute, "read_at defaults to now")
+	readings, err := repos.Meters.GetLatestMeterReadings(ctx, household.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, readings, 1)
+	assert.True(t, reading.ReadAt.Equal(readings[0].ReadAt))
+}
+
+func testRepositoriesDeviceNameTaken(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	_, household := newTestHousehold(t, repos, "owner@example.com")
+
+	require.NoError(t, repos.Devices.AddDevice(ctx, household.ID, &models.Device{Name: "AC"}))
+	assert.ErrorIs(t, repos.Devices.AddDevice(ctx, household.ID, &models.Device{Name: "ac"}), ErrDeviceNameTaken)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"time"
+)
+
+// Query repository Postgres yang tidak memakai fungsi khusus Postgres (NOW(),
+// LATERAL, array, UPDATE ... FROM) dipakai apa adanya untuk SQLite. Repository
+// SQLite* hanya mengganti method yang query-nya berbeda.
+
+// sqliteTimeLayout adalah format kolom waktu di SQLite: UTC dengan lebar tetap
+// sehingga perbandingan teks sama dengan perbandingan waktu.
+const sqliteTimeLayout = "2006-01-02 15:04:05.000000"
+
+// sqliteNow adalah padanan NOW() dalam sqliteTimeLayout.
+const sqliteNow = `(strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')`
+
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+func parseSQLiteTime(value string) (time.Time, error) {
+	return time.ParseInLocation(sqliteTimeLayout, value, time.UTC)
+}
+
+// sqliteNullTime mengubah waktu kosong menjadi NULL agar default di SQL
+// dipakai, padanan nullableDate.
+func sqliteNullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return sqliteTime(t)
+}
+
+// sqliteArgs mengubah argumen waktu menjadi teks sqliteTimeLayout, sehingga
+// kondisi dari recordConditions dan buildRecordQuery bisa dipakai apa adanya.
+func sqliteArgs(args ...any) []any {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case time.Time:
+			converted[i] = sqliteTime(value)
+		case *time.Time:
+			if value != nil {
+				converted[i] = sqliteTime(*value)
+			}
+		default:
+			converted[i] = arg
+		}
+	}
+	return converted
+}
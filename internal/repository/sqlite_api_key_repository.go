@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SQLiteAPIKeyRepository adalah APIKeyRepository untuk DB_DRIVER=sqlite.
+// SQLite tidak punya tipe array, jadi scopes disimpan sebagai array JSON.
+type SQLiteAPIKeyRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func scanSQLiteAPIKey(row interface{ Scan(...any) error }, key *models.APIKey, extra ...any) error {
+	var scopes string
+	dest := []any{&key.ID, &key.HouseholdID, &key.Label, &key.Prefix, &scopes, &key.CreatedBy, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(scopes), &key.Scopes)
+}
+
+// AddAPIKey membuat key baru dan mengisinya ke key.Key. Key tidak bisa diambil
+// lagi setelah ini.
+func (r *SQLiteAPIKeyRepository) AddAPIKey(ctx context.Context, key *models.APIKey) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	raw, err := newAPIKey()
+	if err != nil {
+		return fmt.Errorf("error generating api key: %v", err)
+	}
+	key.Prefix = raw[:len(APIKeyPrefix)+8]
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return fmt.Errorf("error encoding api key scopes: %v", err)
+	}
+	if key.Scopes == nil {
+		scopes = []byte("[]")
+	}
+
+	query := `INSERT INTO api_keys (household_id, label, prefix, key_hash, scopes, created_by, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+	err = r.DB.QueryRowContext(ctx, query, sqliteArgs(key.HouseholdID, key.Label, key.Prefix, hashAPIKey(raw), string(scopes), key.CreatedBy, key.ExpiresAt)...).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return dbError("error inserting api key", err)
+	}
+	key.Key = raw
+	return nil
+}
+
+// GetAPIKeys mengembalikan semua key rumah tangga, termasuk yang sudah dicabut.
+func (r *SQLiteAPIKeyRepository) GetAPIKeys(ctx context.Context, householdID int) ([]models.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys k WHERE k.household_id = $1 ORDER BY k.id`, householdID)
+	if err != nil {
+		return nil, dbError("error fetching api keys", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var key models.APIKey
+		if err := scanSQLiteAPIKey(rows, &key); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	return keys, nil
+}
+
+// DeleteAPIKey mencabut key. Baris tetap disimpan agar riwayatnya terlihat,
+// tetapi key langsung ditolak pada request berikutnya.
+func (r *SQLiteAPIKeyRepository) DeleteAPIKey(ctx context.Context, householdID int, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(ctx, `UPDATE api_keys SET revoked_at = `+sqliteNow+` WHERE household_id = $1 AND id = $2 AND revoked_at IS NULL`, householdID, id)
+	if err != nil {
+		return dbError("error revoking api key", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return NotFound("api key", id)
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey mencari key yang masih berlaku beserta rumah tangganya,
+// sekaligus mencatat waktu pemakaiannya. RETURNING di SQLite tidak bisa
+// membaca tabel lain, jadi rumah tangga diambil dengan query kedua.
+func (r *SQLiteAPIKeyRepository) AuthenticateAPIKey(ctx context.Context, raw string) (*models.APIKey, *models.Household, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	var id int
+	query := `UPDATE api_keys SET last_used_at = ` + sqliteNow + `
+WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ` + sqliteNow + `)
+RETURNING id`
+	err := r.DB.QueryRowContext(ctx, query, hashAPIKey(raw)).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, dbError("error authenticating api key", err)
+	}
+
+	query = `SELECT ` + apiKeyColumns + `, h.id, h.name, h.address, h.tariff_class, h.contracted_va, h.timezone, h.billing_day, h.created_at
+FROM api_keys k
+JOIN households h ON h.id = k.household_id
+WHERE k.id = $1`
+	key := &models.APIKey{}
+	h := &models.Household{}
+	err = scanSQLiteAPIKey(r.DB.QueryRowContext(ctx, query, id), key,
+		&h.ID, &h.Name, &h.Address, &h.TariffClass, &h.ContractedVA, &h.Timezone, &h.BillingDay, &h.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, dbError("error authenticating api key", err)
+	}
+	return key, h, nil
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/validate"
+	"time"
+)
+
+// SQLiteEnergyRecordRepository menyimpan record energi di file SQLite
+// (DB_DRIVER=sqlite) dengan perilaku yang sama seperti EnergyRecordRepository.
+// Rumah tangga, perangkat dan tarif dibaca dari file yang sama, lihat
+// NewSQLiteRepositories.
+type SQLiteEnergyRecordRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+// sqliteRecordPrice adalah padanan tariffJoin: harga tarif golongan rumah
+// tangga yang berlaku pada tanggal record.
+const sqliteRecordPrice = `SELECT t.price_per_kwh FROM tariffs t
+	JOIN households h ON h.tariff_class = t.class
+	WHERE h.id = e.household_id AND t.effective_from <= e.date
+	ORDER BY t.effective_from DESC LIMIT 1`
+
+const sqliteSelectRecordQuery = `SELECT e.id, e.date, e.usage, ` + deviceNameExpr + `, e.duration, e.device_id, e.started_at, (` + sqliteRecordPrice + `)
+` + recordFrom
+
+// applyCost menghitung energi dan biaya record yang baru disimpan.
+func (r *SQLiteEnergyRecordRepository) applyCost(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	record.ComputeEnergy()
+
+	var price sql.NullFloat64
+	err := r.DB.QueryRowContext(ctx, tariffPriceQuery, householdID, sqliteTime(record.Date)).Scan(&price)
+	if err != nil && err != sql.ErrNoRows {
+		return dbError("error retrieving tariff", err)
+	}
+	record.ComputeCost(price.Float64)
+	return nil
+}
+
+// AddRecord menyimpan record baru; date memakai waktu sekarang bila tidak
+// diisi.
+func (r *SQLiteEnergyRecordRepository) AddRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError("error starting transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := resolveDevice(ctx, tx, householdID, record); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO energy_records (usage, device, duration, device_id, started_at, date, household_id) VALUES ($1, $2, $3, $4, $5, COALESCE($6, ` + sqliteNow + `), $7) RETURNING id, date`
+	err = tx.QueryRowContext(ctx, query, sqliteArgs(record.Usage, record.Device, record.Duration, record.DeviceID, record.StartedAt, sqliteNullTime(record.Date), householdID)...).
+		Scan(&record.ID, &record.Date)
+	if err != nil {
+		return dbError("error inserting record", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return dbError("error committing record", err)
+	}
+	return r.applyCost(ctx, householdID, record)
+}
+
+func (r *SQLiteEnergyRecordRepository) GetByIdRecord(ctx context.Context, householdID int, id string) (*models.EnergyRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	record := &models.EnergyRecord{}
+	err := scanRecord(r.DB.QueryRowContext(ctx, sqliteSelectRecordQuery+` WHERE e.household_id = $1 AND e.id = $2`, householdID, id), record)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &models.EnergyRecord{}, NotFound("energy record", id)
+		}
+		return &models.EnergyRecord{}, dbError("error retrieving record", err)
+	}
+	return record, nil
+}
+
+func (r *SQLiteEnergyRecordRepository) DeleteRecord(ctx context.Context, householdID int, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM energy_records WHERE id = $1 AND household_id = $2`, id, householdID)
+	if err != nil {
+		return dbError("error deleting record", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return NotFound("energy record", id)
+	}
+
+	return nil
+}
+
+// UpdateRecord mengubah record; date dan started_at yang tidak diisi
+// mempertahankan nilai lama.
+func (r *SQLiteEnergyRecordRepository) UpdateRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError("error starting transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkRecordExists(ctx, tx, householdID, record.ID); err != nil {
+		return err
+	}
+	if err := resolveDevice(ctx, tx, householdID, record); err != nil {
+		return err
+	}
+
+	query := `UPDATE energy_records SET usage=$1, device=$2, duration=$3, device_id=$4,
+	started_at=COALESCE($5, started_at), date=COALESCE($6, date) WHERE id=$7 AND household_id=$8 RETURNING date, started_at`
+	err = tx.QueryRowContext(ctx, query, sqliteArgs(record.Usage, record.Device, record.Duration, record.DeviceID, record.StartedAt, sqliteNullTime(record.Date), record.ID, householdID)...).
+		Scan(&record.Date, &record.StartedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return NotFound("energy record", record.ID)
+		}
+		return dbError("error updating record", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return dbError("error committing record", err)
+	}
+	return r.applyCost(ctx, householdID, record)
+}
+
+func (r *SQLiteEnergyRecordRepository) GetRecords(ctx context.Context, householdID int, q RecordQuery) (*RecordPage, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query, args, err := buildRecordQuery(sqliteSelectRecordQuery, householdID, q)
+	if err != nil {
+		return nil, err
+	}
+	records, err := r.queryRecords(ctx, query, sqliteArgs(args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &RecordPage{Records: records}
+	if limit := recordLimit(q.Limit); len(records) > limit {
+		page.Records = records[:limit]
+		page.NextCursor = EncodeRecordCursor(page.Records[limit-1].ID)
+	}
+
+	conditions, countArgs := recordConditions(householdID, q)
+	err = r.DB.QueryRowContext(ctx, `SELECT COUNT(*) `+recordFrom+whereClause(conditions), sqliteArgs(countArgs...)...).Scan(&page.Total)
+	if err != nil {
+		return nil, dbError("error counting records", err)
+	}
+	return page, nil
+}
+
+// sqliteActiveRecordsQuery menyaring kasar dengan julianday (presisi sekitar
+// milidetik, diberi kelonggaran); batas akhir yang tepat dicek di Go.
+const sqliteActiveRecordsQuery = sqliteSelectRecordQuery + `
+WHERE e.household_id = $1 AND e.started_at IS NOT NULL AND e.started_at < $3
+	AND julianday(e.started_at) + e.duration / 24.0 > julianday($2) - 0.000001
+ORDER BY e.started_at, e.id`
+
+// GetActiveRecords mengembalikan record yang rentang pemakaiannya
+// [started_at, started_at + duration) beririsan dengan [from, to).
+func (r *SQLiteEnergyRecordRepository) GetActiveRecords(ctx context.Context, householdID int, from, to time.Time) ([]models.EnergyRecord, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	records, err := r.queryRecords(ctx, sqliteActiveRecordsQuery, sqliteArgs(householdID, from, to)...)
+	if err != nil {
+		return nil, err
+	}
+	active := records[:0]
+	for _, record := range records {
+		if record.StartedAt.Add(time.Duration(record.Duration * float64(time.Hour))).After(from) {
+			active = append(active, record)
+		}
+	}
+	return active, nil
+}
+
+func (r *SQLiteEnergyRecordRepository) queryRecords(ctx context.Context, query string, args ...any) ([]models.EnergyRecord, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, dbError("error fetching records", err)
+	}
+	defer rows.Close()
+
+	records := []models.EnergyRecord{}
+	for rows.Next() {
+		var record models.EnergyRecord
+		if err := scanRecord(rows, &record); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	return records, nil
+}
+
+const sqliteSummarizeRecordsQuery = `SELECT COUNT(*), COALESCE(SUM(` + energyWhExpr + `), 0) / 1000.0,
+	COALESCE(SUM(` + energyWhExpr + ` / 1000 * (` + sqliteRecordPrice + `)), 0)
+FROM energy_records e
+WHERE e.household_id = $1 AND e.date >= $2 AND e.date < $3`
+
+// SummarizeRecords menjumlahkan energi dan biaya record pada rentang [from, to).
+func (r *SQLiteEnergyRecordRepository) SummarizeRecords(ctx context.Context, householdID int, from, to time.Time) (*models.UsageSummary, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	summary := &models.UsageSummary{From: from, To: to}
+	err := r.DB.QueryRowContext(ctx, sqliteSummarizeRecordsQuery, sqliteArgs(householdID, from, to)...).
+		Scan(&summary.RecordCount, &summary.EnergyKWh, &summary.CostIDR)
+	if err != nil {
+		return nil, dbError("error summarizing records", err)
+	}
+	summary.CostIDR = models.RoundIDR(summary.CostIDR)
+	return summary, nil
+}
+
+const sqliteUsageRecordsQuery = `SELECT e.date, ` + deviceNameExpr + `, ` + energyWhExpr + `, (` + sqliteRecordPrice + `)
+` + recordFrom + `
+WHERE e.household_id = $1 AND e.date >= $2 AND e.date < $3`
+
+// GetUsageBuckets menjumlahkan energi, biaya dan jumlah record per bucket
+// pada rentang [From, To). SQLite tidak mengenal zona waktu, jadi record
+// dikelompokkan di Go dengan stats.Truncate.
+func (r *SQLiteEnergyRecordRepository) GetUsageBuckets(ctx context.Context, householdID int, q UsageBucketQuery) ([]models.UsageBucket, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	if !models.ValidBucket(q.Bucket) {
+		return nil, Invalid(validate.NewFieldError("bucket", validate.CodeInvalid, i18n.Params{"values": models.Buckets}))
+	}
+	loc := q.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	rows, err := r.DB.QueryContext(ctx, sqliteUsageRecordsQuery, sqliteArgs(householdID, q.From, q.To)...)
+	if err != nil {
+		return nil, dbError("error aggregating records", err)
+	}
+	defer rows.Close()
+
+	var usages []bucketUsage
+	for rows.Next() {
+		var usage bucketUsage
+		var price sql.NullFloat64
+		if err := rows.Scan(&usage.date, &usage.device, &usage.energyWh, &price); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		usage.price = price.Float64
+		usages = append(usages, usage)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	return aggregateBuckets(usages, q.Bucket, q.GroupByDevice, loc), nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"database/sql"
+	"daya-listrik-api/migrations"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSQLite membuat file SQLite baru dengan migrasi SQLite yang di-embed.
+// Package db tidak dipakai karena mengimpor config yang mengimpor repository.
+func newTestSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "records.db")+"?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	files, err := fs.Glob(migrations.SQLite, "*.up.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		content, err := fs.ReadFile(migrations.SQLite, file)
+		require.NoError(t, err)
+		_, err = db.Exec(string(content))
+		require.NoError(t, err, file)
+	}
+	return db
+}
+
+func TestEnergyRecordContract_SQLite(t *testing.T) {
+	db := newTestSQLite(t)
+
+	testEnergyRecordContract(t, recordStore{repos: NewSQLiteRepositories(db, DefaultQueryTimeout)})
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"fmt"
+)
+
+// SQLiteHouseholdRepository adalah HouseholdRepository untuk DB_DRIVER=sqlite.
+// Hanya query undangan yang berbeda karena memakai waktu sekarang.
+type SQLiteHouseholdRepository struct {
+	HouseholdRepository
+}
+
+// AddInvitation membuat kode undangan baru dan mengisinya ke invitation.Code.
+// Kode tidak bisa diambil lagi setelah ini.
+func (r *SQLiteHouseholdRepository) AddInvitation(ctx context.Context, invitation *models.HouseholdInvitation) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	code, err := newInvitationCode()
+	if err != nil {
+		return fmt.Errorf("error generating invitation code: %v", err)
+	}
+
+	query := `INSERT INTO household_invitations (household_id, code_hash, role, created_by, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	err = r.DB.QueryRowContext(ctx, query, invitation.HouseholdID, hashInvitationCode(code), invitation.Role, invitation.CreatedBy, sqliteTime(invitation.ExpiresAt)).
+		Scan(&invitation.ID, &invitation.CreatedAt)
+	if err != nil {
+		return dbError("error inserting invitation", err)
+	}
+	invitation.Code = code
+	return nil
+}
+
+const sqliteValidInvitationCondition = `code_hash = $1 AND used_at IS NULL AND expires_at > ` + sqliteNow
+
+// GetByCodeInvitation mencari undangan yang masih berlaku tanpa memakainya.
+func (r *SQLiteHouseholdRepository) GetByCodeInvitation(ctx context.Context, code string) (*models.HouseholdInvitation, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	invitation := &models.HouseholdInvitation{}
+	query := `SELECT id, household_id, role, COALESCE(created_by, 0), expires_at, created_at FROM household_invitations WHERE ` + sqliteValidInvitationCondition
+	err := r.DB.QueryRowContext(ctx, query, hashInvitationCode(code)).
+		Scan(&invitation.ID, &invitation.HouseholdID, &invitation.Role, &invitation.CreatedBy, &invitation.ExpiresAt, &invitation.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, dbError("error retrieving invitation", err)
+	}
+	return invitation, nil
+}
+
+// RedeemInvitation memakai kode undangan dan menjadikan userID anggota rumah
+// tangga dalam satu transaksi, sama seperti HouseholdRepository.
+func (r *SQLiteHouseholdRepository) RedeemInvitation(ctx context.Context, code string, userID int) (*models.Membership, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, dbError("error starting transaction", err)
+	}
+	defer tx.Rollback()
+
+	var householdID int
+	var role string
+	query := `UPDATE household_invitations SET used_at = ` + sqliteNow + `, used_by = $2 WHERE ` + sqliteValidInvitationCondition + ` RETURNING household_id, role`
+	err = tx.QueryRowContext(ctx, query, hashInvitationCode(code), userID).Scan(&householdID, &role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, dbError("error redeeming invitation", err)
+	}
+
+	memberQuery := `INSERT INTO household_members (household_id, user_id, role) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, memberQuery, householdID, userID, role); err != nil {
+		if uniqueViolation(err) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, dbError("error inserting household member", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError("error committing invitation", err)
+	}
+	return r.GetMembership(ctx, userID, householdID)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"daya-listrik-api/internal/models"
+)
+
+// SQLiteMeterReadingRepository adalah MeterReadingRepository untuk
+// DB_DRIVER=sqlite.
+type SQLiteMeterReadingRepository struct {
+	MeterReadingRepository
+}
+
+// AddMeterReading menyimpan pembacaan meter; read_at memakai waktu sekarang
+// bila tidak diisi.
+func (r *SQLiteMeterReadingRepository) AddMeterReading(ctx context.Context, householdID int, reading *models.MeterReading) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `INSERT INTO meter_readings (read_at, cumulative_kwh, photo_ref, household_id) VALUES (COALESCE($1, ` + sqliteNow + `), $2, $3, $4) RETURNING id, read_at`
+	err := r.DB.QueryRowContext(ctx, query, sqliteNullTime(reading.ReadAt), reading.CumulativeKWh, reading.PhotoRef, householdID).Scan(&reading.ID, &reading.ReadAt)
+	if err != nil {
+		return dbError("error inserting meter reading", err)
+	}
+	return nil
+}
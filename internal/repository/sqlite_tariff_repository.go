@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"time"
+)
+
+// SQLiteTariffRepository adalah TariffRepository untuk DB_DRIVER=sqlite.
+// effective_from disimpan sebagai teks YYYY-MM-DD sehingga bisa dibandingkan
+// langsung dengan kolom waktu lain.
+type SQLiteTariffRepository struct {
+	TariffRepository
+}
+
+func (r *SQLiteTariffRepository) AddTariff(ctx context.Context, tariff *models.Tariff) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `INSERT INTO tariffs (class, min_va, max_va, price_per_kwh, effective_from) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	err := r.DB.QueryRowContext(ctx, query, tariff.Class, tariff.MinVA, tariff.MaxVA, tariff.PricePerKWh, tariff.EffectiveFrom.Format("2006-01-02")).Scan(&tariff.ID)
+	if err != nil {
+		return dbError("error inserting tariff", err)
+	}
+	return nil
+}
+
+// GetEffectiveTariff mengembalikan tarif golongan class yang berlaku pada waktu at.
+func (r *SQLiteTariffRepository) GetEffectiveTariff(ctx context.Context, class string, at time.Time) (*models.Tariff, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tariff := &models.Tariff{}
+	query := `SELECT id, class, min_va, max_va, price_per_kwh, effective_from FROM tariffs
+WHERE class = $1 AND effective_from <= $2 ORDER BY effective_from DESC LIMIT 1`
+	err := r.DB.QueryRowContext(ctx, query, class, sqliteTime(at)).
+		Scan(&tariff.ID, &tariff.Class, &tariff.MinVA, &tariff.MaxVA, &tariff.PricePerKWh, &tariff.EffectiveFrom)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, newError(ErrNotFound, "tariff_not_in_effect", i18n.Params{"class": class, "date": at.Format("2006-01-02")})
+		}
+		return nil, dbError("error retrieving tariff", err)
+	}
+	return tariff, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+)
+
+// SQLiteTokenPurchaseRepository adalah TokenPurchaseRepository untuk
+// DB_DRIVER=sqlite.
+type SQLiteTokenPurchaseRepository struct {
+	TokenPurchaseRepository
+}
+
+// AddTokenPurchase menyimpan pembelian token; purchased_at memakai waktu
+// sekarang bila tidak diisi.
+func (r *SQLiteTokenPurchaseRepository) AddTokenPurchase(ctx context.Context, householdID int, purchase *models.TokenPurchase) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `INSERT INTO token_purchases (token_number, amount_paid, admin_fee, kwh_credited, purchased_at, household_id)
+VALUES ($1, $2, $3, $4, COALESCE($5, ` + sqliteNow + `), $6) RETURNING id, purchased_at`
+	err := r.DB.QueryRowContext(ctx, query, purchase.TokenNumber, purchase.AmountPaid, purchase.AdminFee, purchase.KWhCredited, sqliteNullTime(purchase.PurchasedAt), householdID).
+		Scan(&purchase.ID, &purchase.PurchasedAt)
+	if err != nil {
+		return dbError("error inserting token purchase", err)
+	}
+	return nil
+}
+
+// GetTokenTotals menjumlahkan kWh dari semua pembelian token rumah tangga.
+// MIN dan MAX di SQLite mengembalikan teks, bukan waktu.
+func (r *SQLiteTokenPurchaseRepository) GetTokenTotals(ctx context.Context, householdID int) (*models.TokenTotals, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	totals := &models.TokenTotals{}
+	var first, latest sql.NullString
+	err := r.DB.QueryRowContext(ctx, tokenTotalsQuery, householdID).Scan(&totals.PurchaseCount, &totals.KWhCredited, &first, &latest)
+	if err != nil {
+		return nil, dbError("error summarizing token purchases", err)
+	}
+	if first.Valid {
+		if totals.FirstPurchase, err = parseSQLiteTime(first.String); err != nil {
+			return nil, dbError("error summarizing token purchases", err)
+		}
+		if totals.LatestPurchase, err = parseSQLiteTime(latest.String); err != nil {
+			return nil, dbError("error summarizing token purchases", err)
+		}
+	}
+	return totals, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"time"
+)
+
+// Tarif bersifat append-only: penyesuaian tarif dicatat sebagai baris baru
+// dengan effective_from baru agar biaya record lama tidak berubah.
+type TariffRepositoryInterface interface {
+	AddTariff(ctx context.Context, tariff *models.Tariff) error
+	GetByIdTariff(ctx context.Context, id string) (*models.Tariff, error)
+	GetTariffs(ctx context.Context, class string) ([]models.Tariff, error)
+	GetEffectiveTariff(ctx context.Context, class string, at time.Time) (*models.Tariff, error)
+}
+
+type TariffRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+func (r *TariffRepository) AddTariff(ctx context.Context, tariff *models.Tariff) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `INSERT INTO tariffs (class, min_va, max_va, price_per_kwh, effective_from) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	err := r.DB.QueryRowContext(ctx, query, tariff.Class, tariff.MinVA, tariff.MaxVA, tariff.PricePerKWh, tariff.EffectiveFrom).Scan(&tariff.ID)
+	if err != nil {
+		return dbError("error inserting tariff", err)
+	}
+	return nil
+}
+
+func (r *TariffRepository) GetByIdTariff(ctx context.Context, id string) (*models.Tariff, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tariff := &models.Tariff{}
+	query := `SELECT id, class, min_va, max_va, price_per_kwh, effective_from FROM tariffs WHERE id = $1`
+	err := r.DB.QueryRowContext(ctx, query, id).
+		Scan(&tariff.ID, &tariff.Class, &tariff.MinVA, &tariff.MaxVA, &tariff.PricePerKWh, &tariff.EffectiveFrom)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &models.Tariff{}, NotFound("tariff", id)
+		}
+		return &models.Tariff{}, dbError("error retrieving tariff", err)
+	}
+	return tariff, nil
+}
+
+// GetTariffs mengembalikan riwayat tarif, opsional difilter per golongan.
+func (r *TariffRepository) GetTariffs(ctx context.Context, class string) ([]models.Tariff, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `SELECT id, class, min_va, max_va, price_per_kwh, effective_from FROM tariffs
+WHERE ($1 = '' OR class = $1) ORDER BY class, effective_from`
+	rows, err := r.DB.QueryContext(ctx, query, class)
+	if err != nil {
+		return nil, dbError("error fetching tariffs", err)
+	}
+	defer rows.Close()
+
+	var tariffs []models.Tariff
+	for rows.Next() {
+		var tariff models.Tariff
+		if err := rows.Scan(&tariff.ID, &tariff.Class, &tariff.MinVA, &tariff.MaxVA, &tariff.PricePerKWh, &tariff.EffectiveFrom); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		tariffs = append(tariffs, tariff)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	if tariffs == nil {
+		tariffs = []models.Tariff{}
+	}
+	return tariffs, nil
+}
+
+// GetEffectiveTariff mengembalikan tarif golongan class yang berlaku pada waktu at.
+func (r *TariffRepository) GetEffectiveTariff(ctx context.Context, class string, at time.Time) (*models.Tariff, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	tariff := &models.Tariff{}
+	query := `SELECT id, class, min_va, max_va, price_per_kwh, effective_from FROM tariffs
+WHERE class = $1 AND effective_from <= $2 ORDER BY effective_from DESC LIMIT 1`
+	err := r.DB.QueryRowContext(ctx, query, class, at).
+		Scan(&tariff.ID, &tariff.Class, &tariff.MinVA, &tariff.MaxVA, &tariff.PricePerKWh, &tariff.EffectiveFrom)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, newError(ErrNotFound, "tariff_not_in_effect", i18n.Params{"class": class, "date": at.Format("2006-01-02")})
+		}
+		return nil, dbError("error retrieving tariff", err)
+	}
+	return tariff, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var tariffColumns = []string{"id", "class", "min_va", "max_va", "price_per_kwh", "effective_from"}
+
+func TestAddTariff(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &TariffRepository{DB: db}
+	maxVA := 1300
+	tariff := &models.Tariff{
+		Class:         models.TariffR1_1300VA,
+		MinVA:         1300,
+		MaxVA:         &maxVA,
+		PricePerKWh:   1500,
+		EffectiveFrom: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	query := `INSERT INTO tariffs (class, min_va, max_va, price_per_kwh, effective_from) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(tariff.Class, tariff.MinVA, &maxVA, tariff.PricePerKWh, tariff.EffectiveFrom).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+
+	err = repo.AddTariff(context.Background(), tariff)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, tariff.ID)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(errors.New("duplicate key"))
+
+	err = repo.AddTariff(context.Background(), tariff)
+	assert.Error(t, err)
+}
+
+func TestGetByIdTariff(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &TariffRepository{DB: db}
+	query := `SELECT id, class, min_va, max_va, price_per_kwh, effective_from FROM tariffs WHERE id = $1`
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("1").
+		WillReturnRows(sqlmock.NewRows(tariffColumns).AddRow(1, models.TariffR3, 6600, nil, 1699.53, time.Now()))
+
+	tariff, err := repo.GetByIdTariff(context.Background(), "1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TariffR3, tariff.Class)
+	assert.Nil(t, tariff.MaxVA)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("99").WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByIdTariff(context.Background(), "99")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestGetTariffs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &TariffRepository{DB: db}
+	query := `SELECT id, class, min_va, max_va, price_per_kwh, effective_from FROM tariffs
+WHERE ($1 = '' OR class = $1) ORDER BY class, effective_from`
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(models.TariffR1_1300VA).
+		WillReturnRows(sqlmock.NewRows(tariffColumns).
+			AddRow(1, models.TariffR1_1300VA, 1300, 1300, 1444.70, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).
+			AddRow(9, models.TariffR1_1300VA, 1300, 1300, 1500.00, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)))
+
+	tariffs, err := repo.GetTariffs(context.Background(), models.TariffR1_1300VA)
+	assert.NoError(t, err)
+	assert.Len(t, tariffs, 2)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("").WillReturnError(errors.New("query error"))
+
+	_, err = repo.GetTariffs(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestGetEffectiveTariff(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &TariffRepository{DB: db}
+	query := `SELECT id, class, min_va, max_va, price_per_kwh, effective_from FROM tariffs
+WHERE class = $1 AND effective_from <= $2 ORDER BY effective_from DESC LIMIT 1`
+	at := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(models.TariffR2, at).
+		WillReturnRows(sqlmock.NewRows(tariffColumns).AddRow(6, models.TariffR2, 3500, 5500, 1699.53, time.Now()))
+
+	tariff, err := repo.GetEffectiveTariff(context.Background(), models.TariffR2, at)
+	assert.NoError(t, err)
+	assert.Equal(t, 1699.53, tariff.PricePerKWh)
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(models.TariffR2, at).WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetEffectiveTariff(context.Background(), models.TariffR2, at)
+	assert.Error(t, err)
+}
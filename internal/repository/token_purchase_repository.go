@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"time"
+)
+
+// TokenPurchaseRepositoryInterface selalu dibatasi pada pembelian token milik
+// householdID.
+type TokenPurchaseRepositoryInterface interface {
+	AddTokenPurchase(ctx context.Context, householdID int, purchase *models.TokenPurchase) error
+	GetByIdTokenPurchase(ctx context.Context, householdID int, id string) (*models.TokenPurchase, error)
+	DeleteTokenPurchase(ctx context.Context, householdID int, id string) error
+	GetTokenPurchases(ctx context.Context, householdID int) ([]models.TokenPurchase, error)
+	GetTokenTotals(ctx context.Context, householdID int) (*models.TokenTotals, error)
+}
+
+type TokenPurchaseRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+const selectTokenPurchaseQuery = `SELECT id, token_number, amount_paid, admin_fee, kwh_credited, purchased_at FROM token_purchases`
+
+func scanTokenPurchase(row interface{ Scan(...any) error }, purchase *models.TokenPurchase) error {
+	return row.Scan(&purchase.ID, &purchase.TokenNumber, &purchase.AmountPaid, &purchase.AdminFee, &purchase.KWhCredited, &purchase.PurchasedAt)
+}
+
+// AddTokenPurchase menyimpan pembelian token; purchased_at memakai NOW()
+// bila tidak diisi.
+func (r *TokenPurchaseRepository) AddTokenPurchase(ctx context.Context, householdID int, purchase *models.TokenPurchase) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `INSERT INTO token_purchases (token_number, amount_paid, admin_fee, kwh_credited, purchased_at, household_id)
+VALUES ($1, $2, $3, $4, COALESCE($5, NOW()), $6) RETURNING id, purchased_at`
+	purchasedAt := sql.NullTime{Time: purchase.PurchasedAt, Valid: !purchase.PurchasedAt.IsZero()}
+	err := r.DB.QueryRowContext(ctx, query, purchase.TokenNumber, purchase.AmountPaid, purchase.AdminFee, purchase.KWhCredited, purchasedAt, householdID).
+		Scan(&purchase.ID, &purchase.PurchasedAt)
+	if err != nil {
+		return dbError("error inserting token purchase", err)
+	}
+	return nil
+}
+
+func (r *TokenPurchaseRepository) GetByIdTokenPurchase(ctx context.Context, householdID int, id string) (*models.TokenPurchase, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	purchase := &models.TokenPurchase{}
+	err := scanTokenPurchase(r.DB.QueryRowContext(ctx, selectTokenPurchaseQuery+` WHERE id = $1 AND household_id = $2`, id, householdID), purchase)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &models.TokenPurchase{}, NotFound("token purchase", id)
+		}
+		return &models.TokenPurchase{}, dbError("error retrieving token purchase", err)
+	}
+	return purchase, nil
+}
+
+func (r *TokenPurchaseRepository) DeleteTokenPurchase(ctx context.Context, householdID int, id string) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `DELETE FROM token_purchases WHERE id = $1 AND household_id = $2`
+	result, err := r.DB.ExecContext(ctx, query, id, householdID)
+	if err != nil {
+		return dbError("error deleting token purchase", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return NotFound("token purchase", id)
+	}
+
+	return nil
+}
+
+func (r *TokenPurchaseRepository) GetTokenPurchases(ctx context.Context, householdID int) ([]models.TokenPurchase, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(ctx, selectTokenPurchaseQuery+` WHERE household_id = $1 ORDER BY purchased_at DESC, id DESC`, householdID)
+	if err != nil {
+		return nil, dbError("error fetching token purchases", err)
+	}
+	defer rows.Close()
+
+	var purchases []models.TokenPurchase
+	for rows.Next() {
+		var purchase models.TokenPurchase
+		if err := scanTokenPurchase(rows, &purchase); err != nil {
+			return nil, dbError("error scanning row", err)
+		}
+		purchases = append(purchases, purchase)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError("error in row iteration", err)
+	}
+	if purchases == nil {
+		purchases = []models.TokenPurchase{}
+	}
+	return purchases, nil
+}
+
+const tokenTotalsQuery = `SELECT COUNT(*), COALESCE(SUM(kwh_credited), 0), MIN(purchased_at), MAX(purchased_at) FROM token_purchases WHERE household_id = $1`
+
+// GetTokenTotals menjumlahkan kWh dari semua pembelian token rumah tangga.
+func (r *TokenPurchaseRepository) GetTokenTotals(ctx context.Context, householdID int) (*models.TokenTotals, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	totals := &models.TokenTotals{}
+	var first, latest sql.NullTime
+	err := r.DB.QueryRowContext(ctx, tokenTotalsQuery, householdID).Scan(&totals.PurchaseCount, &totals.KWhCredited, &first, &latest)
+	if err != nil {
+		return nil, dbError("error summarizing token purchases", err)
+	}
+	totals.FirstPurchase = first.Time
+	totals.LatestPurchase = latest.Time
+	return totals, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var tokenPurchaseColumns = []string{"id", "token_number", "amount_paid", "admin_fee", "kwh_credited", "purchased_at"}
+
+func TestAddTokenPurchase(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &TokenPurchaseRepository{DB: db}
+	query := `INSERT INTO token_purchases (token_number, amount_paid, admin_fee, kwh_credited, purchased_at, household_id)
+VALUES ($1, $2, $3, $4, COALESCE($5, NOW()), $6) RETURNING id, purchased_at`
+
+	purchase := &models.TokenPurchase{TokenNumber: "12345678901234567890", AmountPaid: 102500, AdminFee: 2500, KWhCredited: 69.2}
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(purchase.TokenNumber, purchase.AmountPaid, purchase.AdminFee, purchase.KWhCredited, sql.NullTime{}, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "purchased_at"}).AddRow(1, time.Now()))
+
+	err = repo.AddTokenPurchase(context.Background(), 1, purchase)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purchase.ID)
+	assert.False(t, purchase.PurchasedAt.IsZero())
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).WillReturnError(errors.New("insert error"))
+
+	err = repo.AddTokenPurchase(context.Background(), 1, purchase)
+	assert.Error(t, err)
+}
+
+func TestGetByIdTokenPurchase(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &TokenPurchaseRepository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectTokenPurchaseQuery+` WHERE id = $1 AND household_id = $2`)).WithArgs("1", 1).
+		WillReturnRows(sqlmock.NewRows(tokenPurchaseColumns).AddRow(1, "12345678901234567890", 52500.0, 2500.0, 34.6, time.Now()))
+
+	purchase, err := repo.GetByIdTokenPurchase(context.Background(), 1, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 34.6, purchase.KWhCredited)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectTokenPurchaseQuery+` WHERE id = $1 AND household_id = $2`)).WithArgs("2", 1).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByIdTokenPurchase(context.Background(), 1, "2")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestDeleteTokenPurchase(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &TokenPurchaseRepository{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM token_purchases WHERE id = $1 AND household_id = $2`)).WithArgs("1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.DeleteTokenPurchase(context.Background(), 1, "1"))
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM token_purchases WHERE id = $1 AND household_id = $2`)).WithArgs("1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = repo.DeleteTokenPurchase(context.Background(), 1, "1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestGetTokenPurchases(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &TokenPurchaseRepository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectTokenPurchaseQuery + ` WHERE household_id = $1 ORDER BY purchased_at DESC, id DESC`)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(tokenPurchaseColumns).
+			AddRow(2, "09876543210987654321", 102500.0, 2500.0, 69.2, time.Now()).
+			AddRow(1, "12345678901234567890", 52500.0, 2500.0, 34.6, time.Now().AddDate(0, 0, -10)))
+
+	purchases, err := repo.GetTokenPurchases(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, purchases, 2)
+}
+
+func TestGetTokenTotals(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &TokenPurchaseRepository{DB: db}
+	first := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	latest := time.Date(2026, 10, 10, 8, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(tokenTotalsQuery)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "sum", "min", "max"}).AddRow(2, 103.8, first, latest))
+
+	totals, err := repo.GetTokenTotals(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, totals.PurchaseCount)
+	assert.Equal(t, first, totals.FirstPurchase)
+
+	// Belum ada pembelian
+	mock.ExpectQuery(regexp.QuoteMeta(tokenTotalsQuery)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "sum", "min", "max"}).AddRow(0, 0.0, nil, nil))
+
+	totals, err = repo.GetTokenTotals(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.True(t, totals.FirstPurchase.IsZero())
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"cmp"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/stats"
+	"slices"
+	"time"
+)
+
+// bucketUsage adalah energi satu record beserta harga per kWh-nya untuk
+// aggregateBuckets.
+type bucketUsage struct {
+	date     time.Time
+	device   string
+	energyWh float64
+	price    float64
+}
+
+// aggregateBuckets adalah padanan GROUP BY pada usageBucketsQuery: hasilnya
+// diurutkan menurut awal bucket lalu nama perangkat.
+func aggregateBuckets(usages []bucketUsage, bucket string, groupByDevice bool, loc *time.Location) []models.UsageBucket {
+	type key struct {
+		start  time.Time
+		device string
+	}
+	totals := map[key]*models.UsageBucket{}
+	for _, usage := range usages {
+		k := key{start: stats.Truncate(usage.date, bucket, loc)}
+		if groupByDevice {
+			k.device = usage.device
+		}
+		total := totals[k]
+		if total == nil {
+			total = &models.UsageBucket{Start: k.start, Device: k.device}
+			totals[k] = total
+		}
+		total.RecordCount++
+		total.EnergyKWh += usage.energyWh / 1000
+		total.CostIDR += usage.energyWh / 1000 * usage.price
+	}
+
+	buckets := make([]models.UsageBucket, 0, len(totals))
+	for _, total := range totals {
+		total.CostIDR = models.RoundIDR(total.CostIDR)
+		buckets = append(buckets, *total)
+	}
+	slices.SortFunc(buckets, func(a, b models.UsageBucket) int {
+		return cmp.Or(a.Start.Compare(b.Start), cmp.Compare(a.Device, b.Device))
+	})
+	return buckets
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"daya-listrik-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateBuckets(t *testing.T) {
+	jakarta, err := models.LoadTimezone("Asia/Jakarta")
+	require.NoError(t, err)
+	// Rabu 13 Maret 2024 02.00 WIB = Selasa 12 Maret 19.00 UTC
+	usages := []bucketUsage{
+		{date: time.Date(2024, 3, 12, 19, 0, 0, 0, time.UTC), device: "AC", energyWh: 1000, price: 1444.70},
+		{date: time.Date(2024, 3, 17, 12, 0, 0, 0, jakarta), device: "Lampu", energyWh: 500, price: 1444.70},
+	}
+
+	days := aggregateBuckets(usages, models.BucketDay, false, jakarta)
+	require.Len(t, days, 2)
+	assert.Equal(t, time.Date(2024, 3, 13, 0, 0, 0, 0, jakarta), days[0].Start)
+
+	weeks := aggregateBuckets(usages, models.BucketWeek, false, jakarta)
+	require.Len(t, weeks, 1, "weeks start on Monday")
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, jakarta), weeks[0].Start)
+	assert.Equal(t, 2, weeks[0].RecordCount)
+	assert.InDelta(t, 1.5, weeks[0].EnergyKWh, 1e-9)
+	assert.Equal(t, models.RoundIDR(1.5*1444.70), weeks[0].CostIDR)
+
+	devices := aggregateBuckets(usages, models.BucketMonth, true, jakarta)
+	require.Len(t, devices, 2)
+	assert.Equal(t, []string{"AC", "Lampu"}, []string{devices[0].Device, devices[1].Device})
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrEmailTaken dikembalikan AddUser bila email sudah terdaftar.
+	ErrEmailTaken   = Conflict("email_taken")
+	ErrUserNotFound = newError(ErrNotFound, "user_not_found", nil)
+)
+
+type UserRepositoryInterface interface {
+	AddUser(ctx context.Context, user *models.User) error
+	GetByIdUser(ctx context.Context, id int) (*models.User, error)
+	GetByEmailUser(ctx context.Context, email string) (*models.User, error)
+}
+
+type UserRepository struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+const selectUserQuery = `SELECT id, email, password_hash, is_admin, created_at FROM users`
+
+// AddUser menyimpan user baru. User baru tidak pernah menjadi admin; admin
+// ditetapkan terpisah dengan SetAdmin (admin grant-admin). User baru juga
+// tidak pernah mendapat data yang dibuat sebelum ada akun, lihat
+// HouseholdRepository.AdoptOrphans.
+func (r *UserRepository) AddUser(ctx context.Context, user *models.User) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	query := `INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, is_admin, created_at`
+	err := r.DB.QueryRowContext(ctx, query, strings.TrimSpace(user.Email), user.PasswordHash).Scan(&user.ID, &user.IsAdmin, &user.CreatedAt)
+	if err != nil {
+		if uniqueViolation(err) {
+			return ErrEmailTaken
+		}
+		return dbError("error inserting user", err)
+	}
+	return nil
+}
+
+const setAdminQuery = `UPDATE users SET is_admin = $1 WHERE LOWER(email) = LOWER($2)`
+
+// SetAdmin memberi atau mencabut status admin user dengan email tersebut.
+func (r *UserRepository) SetAdmin(ctx context.Context, email string, admin bool) error {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(ctx, setAdminQuery, admin, strings.TrimSpace(email))
+	if err != nil {
+		return dbError("error updating user", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dbError("error checking rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) GetByIdUser(ctx context.Context, id int) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	return r.getUser(ctx, selectUserQuery+` WHERE id = $1`, id)
+}
+
+// GetByEmailUser mencari user berdasarkan email tanpa membedakan huruf besar/kecil.
+func (r *UserRepository) GetByEmailUser(ctx context.Context, email string) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	return r.getUser(ctx, selectUserQuery+` WHERE LOWER(email) = LOWER($1)`, strings.TrimSpace(email))
+}
+
+func (r *UserRepository) getUser(ctx context.Context, query string, arg any) (*models.User, error) {
+	user := &models.User{}
+	err := r.DB.QueryRowContext(ctx, query, arg).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.IsAdmin, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUserNotFound
+		}
+		return nil, dbError("error retrieving user", err)
+	}
+	return user, nil
+}
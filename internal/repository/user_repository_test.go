@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"daya-listrik-api/internal/models"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var userColumns = []string{"id", "email", "password_hash", "is_admin", "created_at"}
+
+const insertUserQuery = `INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, is_admin, created_at`
+
+func TestAddUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &UserRepository{DB: db}
+	user := &models.User{Email: " budi@example.com ", PasswordHash: "hash"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(insertUserQuery)).WithArgs("budi@example.com", "hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "is_admin", "created_at"}).AddRow(1, false, time.Now()))
+
+	err = repo.AddUser(context.Background(), user)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	assert.False(t, user.IsAdmin, "registration never grants admin")
+
+	// Email sudah terdaftar
+	mock.ExpectQuery(regexp.QuoteMeta(insertUserQuery)).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err = repo.AddUser(context.Background(), user)
+	assert.ErrorIs(t, err, ErrEmailTaken)
+
+	mock.ExpectQuery(regexp.QuoteMeta(insertUserQuery)).WillReturnError(errors.New("insert error"))
+
+	err = repo.AddUser(context.Background(), user)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrEmailTaken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByEmailUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &UserRepository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectUserQuery + ` WHERE LOWER(email) = LOWER($1)`)).WithArgs("Budi@Example.com").
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "budi@example.com", "hash", false, time.Now()))
+
+	user, err := repo.GetByEmailUser(context.Background(), "Budi@Example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	assert.Equal(t, "hash", user.PasswordHash)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectUserQuery + ` WHERE LOWER(email) = LOWER($1)`)).WithArgs("siti@example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.GetByEmailUser(context.Background(), "siti@example.com")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestGetByIdUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &UserRepository{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectUserQuery + ` WHERE id = $1`)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(1, "budi@example.com", "hash", false, time.Now()))
+
+	user, err := repo.GetByIdUser(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "budi@example.com", user.Email)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectUserQuery + ` WHERE id = $1`)).WithArgs(2).
+		WillReturnError(errors.New("db error"))
+
+	_, err = repo.GetByIdUser(context.Background(), 2)
+	assert.Error(t, err)
+}
+
+func TestSetAdmin(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := &UserRepository{DB: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(setAdminQuery)).WithArgs(true, "Budi@Example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.SetAdmin(context.Background(), " Budi@Example.com ", true))
+
+	mock.ExpectExec(regexp.QuoteMeta(setAdminQuery)).WithArgs(false, "nobody@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.SetAdmin(context.Background(), "nobody@example.com", false), ErrUserNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// StopFunc menghentikan satu komponen, mis. worker latar belakang atau pool
+// database, dalam batas waktu ctx.
+type StopFunc func(ctx context.Context) error
+
+// Run mendengarkan di srv.Addr lalu menjalankan Serve.
+func Run(ctx context.Context, srv *http.Server, drain time.Duration, stops ...StopFunc) error {
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", srv.Addr, err)
+	}
+	return Serve(ctx, srv, ln, drain, stops...)
+}
+
+// Serve melayani request dari ln sampai ctx selesai (mis. SIGTERM). Setelah
+// itu server berhenti menerima koneksi baru, menunggu request yang sedang
+// berjalan paling lama drain, lalu memanggil stops berurutan. Urutkan stops
+// dari worker latar belakang ke pool database agar tidak ada yang memakai
+// koneksi yang sudah ditutup. stops tetap dipanggil bila drain terlewati.
+func Serve(ctx context.Context, srv *http.Server, ln net.Listener, drain time.Duration, stops ...StopFunc) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+
+	var errs []error
+	select {
+	case err := <-serveErr:
+		// Server berhenti sendiri sebelum ada sinyal; tetap bereskan sisanya.
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs = append(errs, err)
+		}
+	case <-ctx.Done():
+		log.Printf("Shutting down, draining in-flight requests for up to %s", drain)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drain)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("error draining requests: %w", err))
+	}
+	for _, stop := range stops {
+		if err := stop(shutdownCtx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
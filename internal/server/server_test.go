@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingServer mengembalikan server yang handler-nya memberi tahu started
+// lalu menunggu release sebelum menjawab.
+func blockingServer(t *testing.T) (*http.Server, net.Listener, chan struct{}, chan struct{}) {
+	t.Helper()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return srv, ln, started, release
+}
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	srv, ln, started, release := blockingServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	var order []string
+	stop := func(name string) StopFunc {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, srv, ln, 5*time.Second, stop("workers"), stop("database"))
+	}()
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{string(body), err}
+	}()
+
+	<-started
+	cancel() // setara dengan SIGTERM saat request masih berjalan
+
+	// Server menunggu request selesai dan belum menghentikan komponen lain.
+	select {
+	case err := <-served:
+		t.Fatalf("Serve returned before the in-flight request finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	mu.Lock()
+	assert.Empty(t, order)
+	mu.Unlock()
+
+	close(release)
+
+	res := <-responses
+	require.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+	require.NoError(t, <-served)
+	assert.Equal(t, []string{"workers", "database"}, order)
+
+	_, err := net.DialTimeout("tcp", ln.Addr().String(), time.Second)
+	assert.Error(t, err, "listener is closed after shutdown")
+}
+
+func TestServe_DrainTimeout(t *testing.T) {
+	srv, ln, started, release := blockingServer(t)
+	defer close(release)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stopped := false
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, srv, ln, 20*time.Millisecond, func(context.Context) error {
+			stopped = true
+			return nil
+		})
+	}()
+	go http.Get("http://" + ln.Addr().String())
+
+	<-started
+	cancel()
+
+	err := <-served
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, stopped, "stop functions run even when draining times out")
+}
@@ -0,0 +1,94 @@
+package stats
+
+import (
+	"daya-listrik-api/internal/models"
+	"sort"
+	"time"
+)
+
+// MaxBuckets membatasi jumlah bucket dalam satu respons.
+const MaxBuckets = 1000
+
+// Truncate mengembalikan awal bucket yang memuat t menurut jam dinding loc.
+// Minggu dimulai hari Senin, sama dengan date_trunc('week') di PostgreSQL.
+func Truncate(t time.Time, bucket string, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch bucket {
+	case models.BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	case models.BucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+func next(start time.Time, bucket string) time.Time {
+	switch bucket {
+	case models.BucketMonth:
+		return start.AddDate(0, 1, 0)
+	case models.BucketWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Align memperluas [from, to) ke batas bucket terdekat agar bucket pertama
+// dan terakhir tidak terpotong.
+func Align(from, to time.Time, bucket string, loc *time.Location) (time.Time, time.Time) {
+	end := Truncate(to, bucket, loc)
+	if end.Before(to) {
+		end = next(end, bucket)
+	}
+	return Truncate(from, bucket, loc), end
+}
+
+// Starts mengembalikan awal setiap bucket yang beririsan dengan [from, to).
+func Starts(from, to time.Time, bucket string, loc *time.Location) []time.Time {
+	var starts []time.Time
+	for start := Truncate(from, bucket, loc); start.Before(to); start = next(start, bucket) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// Fill melengkapi hasil agregasi dengan bucket kosong bernilai nol agar
+// grafik tidak berlubang. Bila groupByDevice, setiap perangkat yang muncul
+// pada rows mendapat baris untuk setiap bucket.
+func Fill(rows []models.UsageBucket, starts []time.Time, groupByDevice bool) []models.UsageBucket {
+	type key struct {
+		start  int64
+		device string
+	}
+	found := make(map[key]models.UsageBucket, len(rows))
+	devices := []string{""}
+	if groupByDevice {
+		seen := map[string]bool{}
+		devices = devices[:0]
+		for _, row := range rows {
+			if !seen[row.Device] {
+				seen[row.Device] = true
+				devices = append(devices, row.Device)
+			}
+		}
+		sort.Strings(devices)
+	}
+	for _, row := range rows {
+		found[key{row.Start.Unix(), row.Device}] = row
+	}
+
+	filled := make([]models.UsageBucket, 0, len(starts)*len(devices))
+	for _, start := range starts {
+		for _, device := range devices {
+			row, ok := found[key{start.Unix(), device}]
+			if !ok {
+				row = models.UsageBucket{Device: device}
+			}
+			row.Start = start
+			filled = append(filled, row)
+		}
+	}
+	return filled
+}
@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"daya-listrik-api/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	wita, _ := models.LoadTimezone("WITA")
+	// 2026-10-14 17:30 UTC = Kamis 2026-10-15 01:30 WITA
+	at := time.Date(2026, 10, 14, 17, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, wita), Truncate(at, models.BucketDay, wita))
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, wita), Truncate(at, models.BucketWeek, wita))
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, wita), Truncate(at, models.BucketMonth, wita))
+
+	sunday := time.Date(2026, 10, 18, 23, 0, 0, 0, wita)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, wita), Truncate(sunday, models.BucketWeek, wita))
+}
+
+func TestStarts(t *testing.T) {
+	wib, _ := models.LoadTimezone("WIB")
+	from := time.Date(2026, 10, 1, 12, 0, 0, 0, wib)
+	to := time.Date(2026, 10, 4, 0, 0, 0, 0, wib)
+
+	starts := Starts(from, to, models.BucketDay, wib)
+	assert.Len(t, starts, 3)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, wib), starts[0])
+
+	assert.Len(t, Starts(from, from.AddDate(0, 3, 0), models.BucketMonth, wib), 4)
+}
+
+func TestAlign(t *testing.T) {
+	wit, _ := models.LoadTimezone("WIT")
+	from := time.Date(2026, 10, 14, 9, 0, 0, 0, wit)
+	to := time.Date(2026, 10, 17, 15, 0, 0, 0, wit)
+
+	start, end := Align(from, to, models.BucketDay, wit)
+	assert.Equal(t, time.Date(2026, 10, 14, 0, 0, 0, 0, wit), start)
+	assert.Equal(t, time.Date(2026, 10, 18, 0, 0, 0, 0, wit), end)
+
+	midnight := time.Date(2026, 10, 18, 0, 0, 0, 0, wit)
+	_, end = Align(from, midnight, models.BucketDay, wit)
+	assert.Equal(t, midnight, end)
+}
+
+func TestFill(t *testing.T) {
+	wib, _ := models.LoadTimezone("WIB")
+	day1 := time.Date(2026, 10, 1, 0, 0, 0, 0, wib)
+	day2 := day1.AddDate(0, 0, 1)
+	starts := []time.Time{day1, day2}
+
+	t.Run("totals", func(t *testing.T) {
+		filled := Fill([]models.UsageBucket{{Start: day2, RecordCount: 3, EnergyKWh: 2.5}}, starts, false)
+
+		assert.Len(t, filled, 2)
+		assert.Equal(t, models.UsageBucket{Start: day1}, filled[0])
+		assert.Equal(t, 2.5, filled[1].EnergyKWh)
+	})
+
+	t.Run("per device", func(t *testing.T) {
+		rows := []models.UsageBucket{
+			{Start: day1, Device: "Kulkas", RecordCount: 1, EnergyKWh: 1.2},
+			{Start: day2, Device: "AC", RecordCount: 1, EnergyKWh: 2.8},
+		}
+		filled := Fill(rows, starts, true)
+
+		assert.Len(t, filled, 4)
+		assert.Equal(t, models.UsageBucket{Start: day1, Device: "AC"}, filled[0])
+		assert.Equal(t, 1.2, filled[1].EnergyKWh)
+		assert.Equal(t, 2.8, filled[2].EnergyKWh)
+		assert.Equal(t, models.UsageBucket{Start: day2, Device: "Kulkas"}, filled[3])
+	})
+}
@@ -0,0 +1,118 @@
+package tenant
+
+import (
+	"context"
+	"daya-listrik-api/internal/auth"
+	"daya-listrik-api/internal/i18n"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/problem"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/validate"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Header adalah header request untuk memilih rumah tangga aktif.
+const Header = "X-Household-ID"
+
+type contextKey struct{}
+
+// WithMembership menyimpan rumah tangga aktif dan peran user di context.
+func WithMembership(ctx context.Context, m models.Membership) context.Context {
+	return context.WithValue(ctx, contextKey{}, m)
+}
+
+// FromContext mengembalikan rumah tangga aktif dari context.
+func FromContext(ctx context.Context) (models.Membership, bool) {
+	m, ok := ctx.Value(contextKey{}).(models.Membership)
+	return m, ok
+}
+
+// Middleware menentukan rumah tangga aktif dari header X-Household-ID. Tanpa
+// header, rumah tangga dipilih otomatis bila user hanya anggota satu rumah
+// tangga. Harus dipasang setelah auth.Middleware.
+func Middleware(repo repository.HouseholdRepositoryInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := auth.UserID(r.Context())
+			if !ok {
+				problem.Write(w, r, problem.New(http.StatusUnauthorized, "unauthorized", nil))
+				return
+			}
+
+			membership, err := resolve(r.Context(), repo, userID, strings.TrimSpace(r.Header.Get(Header)))
+			if err != nil {
+				problem.Error(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithMembership(r.Context(), *membership)))
+		})
+	}
+}
+
+// PathMiddleware menentukan rumah tangga dari path parameter param, untuk
+// route pengelolaan seperti /api/households/{id}. Rumah tangga milik orang
+// lain dilaporkan tidak ditemukan agar keberadaannya tidak bocor. Harus
+// dipasang setelah auth.Middleware.
+func PathMiddleware(repo repository.HouseholdRepositoryInterface, param string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := auth.UserID(r.Context())
+			if !ok {
+				problem.Write(w, r, problem.New(http.StatusUnauthorized, "unauthorized", nil))
+				return
+			}
+
+			householdID, err := strconv.Atoi(strings.TrimSpace(mux.Vars(r)[param]))
+			if err != nil {
+				problem.Write(w, r, problem.InvalidParameter(param, validate.CodeInvalid, nil))
+				return
+			}
+
+			membership, err := repo.GetMembership(r.Context(), userID, householdID)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotMember) {
+					err = repository.NotFound("household", householdID)
+				}
+				problem.Error(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithMembership(r.Context(), *membership)))
+		})
+	}
+}
+
+func resolve(ctx context.Context, repo repository.HouseholdRepositoryInterface, userID int, raw string) (*models.Membership, error) {
+	if raw != "" {
+		householdID, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, problem.New(http.StatusBadRequest, "invalid_household_header", i18n.Params{"header": Header})
+		}
+		membership, err := repo.GetMembership(ctx, userID, householdID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotMember) {
+				return nil, problem.New(http.StatusForbidden, "not_household_member", nil)
+			}
+			return nil, err
+		}
+		return membership, nil
+	}
+
+	memberships, err := repo.GetHouseholds(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch len(memberships) {
+	case 0:
+		return nil, problem.New(http.StatusForbidden, "household_required", nil)
+	case 1:
+		return &memberships[0], nil
+	}
+	return nil, problem.New(http.StatusBadRequest, "household_header_required", i18n.Params{"header": Header})
+}
@@ -0,0 +1,110 @@
+package tenant
+
+import (
+	"daya-listrik-api/internal/auth"
+	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/repository/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMiddleware(t *testing.T) {
+	rumah := models.Membership{Household: models.Household{ID: 10, Name: "Rumah"}, Role: models.RoleOwner}
+	kos := models.Membership{Household: models.Household{ID: 11, Name: "Kos"}, Role: models.RoleViewer}
+
+	repo := new(mocks.MockHouseholdRepository)
+	repo.On("GetHouseholds", mock.Anything, 1).Return([]models.Membership{rumah}, nil)
+	repo.On("GetHouseholds", mock.Anything, 2).Return([]models.Membership{rumah, kos}, nil)
+	repo.On("GetHouseholds", mock.Anything, 3).Return([]models.Membership{}, nil)
+	repo.On("GetMembership", mock.Anything, 2, 11).Return(&kos, nil)
+	repo.On("GetMembership", mock.Anything, 2, 12).Return(nil, repository.ErrNotMember)
+
+	handler := Middleware(repo)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m, _ := FromContext(r.Context())
+		w.Write([]byte(strconv.Itoa(m.Household.ID)))
+	}))
+
+	cases := []struct {
+		name   string
+		userID int
+		header string
+		status int
+		body   string
+	}{
+		{"single household", 1, "", http.StatusOK, "10"},
+		{"header selects household", 2, "11", http.StatusOK, "11"},
+		{"several households need header", 2, "", http.StatusBadRequest, ""},
+		{"not a member", 2, "12", http.StatusForbidden, ""},
+		{"invalid header", 2, "kos", http.StatusBadRequest, ""},
+		{"no household", 3, "", http.StatusForbidden, ""},
+		{"no user", 0, "", http.StatusUnauthorized, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/records", nil)
+			if tc.userID != 0 {
+				req = req.WithContext(auth.WithUserID(req.Context(), tc.userID))
+			}
+			if tc.header != "" {
+				req.Header.Set(Header, tc.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.body != "" {
+				assert.Equal(t, tc.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPathMiddleware(t *testing.T) {
+	kos := models.Membership{Household: models.Household{ID: 11, Name: "Kos"}, Role: models.RoleViewer}
+
+	repo := new(mocks.MockHouseholdRepository)
+	repo.On("GetMembership", mock.Anything, 2, 11).Return(&kos, nil)
+	repo.On("GetMembership", mock.Anything, 2, 12).Return(nil, repository.ErrNotMember)
+
+	router := mux.NewRouter()
+	router.Use(PathMiddleware(repo, "id"))
+	router.HandleFunc("/api/households/{id}", func(w http.ResponseWriter, r *http.Request) {
+		m, _ := FromContext(r.Context())
+		w.Write([]byte(strconv.Itoa(m.Household.ID)))
+	})
+
+	cases := []struct {
+		name   string
+		userID int
+		id     string
+		status int
+		body   string
+	}{
+		{"member", 2, "11", http.StatusOK, "11"},
+		{"not a member", 2, "12", http.StatusNotFound, ""},
+		{"invalid id", 2, "kos", http.StatusBadRequest, ""},
+		{"no user", 0, "11", http.StatusUnauthorized, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/households/"+tc.id, nil)
+			if tc.userID != 0 {
+				req = req.WithContext(auth.WithUserID(req.Context(), tc.userID))
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+			if tc.body != "" {
+				assert.Equal(t, tc.body, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package validate
+
+import (
+	"daya-listrik-api/internal/i18n"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kode error field yang stabil untuk dibaca klien.
+const (
+	CodeRequired     = "required"
+	CodeInvalid      = "invalid"
+	CodeOutOfRange   = "out_of_range"
+	CodeTooLong      = "too_long"
+	CodeInFuture     = "in_future"
+	CodeNotFound     = "not_found"
+	CodeUnknownField = "unknown_field"
+	CodeInvalidType  = "invalid_type"
+	CodeTooShort     = "too_short"
+	CodeConflict     = "conflict"
+)
+
+// FieldError menjelaskan satu field input yang tidak valid. Message dirender
+// dari katalog i18n berdasarkan Field, Code dan Params.
+type FieldError struct {
+	Field   string      `json:"field"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Params  i18n.Params `json:"params,omitempty"`
+}
+
+// NewFieldError membuat FieldError dengan pesan berbahasa Inggris.
+func NewFieldError(field, code string, params i18n.Params) FieldError {
+	return FieldError{Field: field, Code: code, Message: i18n.FieldMessage(i18n.EN, field, code, params), Params: params}
+}
+
+// Localize mengembalikan salinan f dengan pesan dalam bahasa lang.
+func (f FieldError) Localize(lang string) FieldError {
+	f.Message = i18n.FieldMessage(lang, f.Field, f.Code, f.Params)
+	return f
+}
+
+// Errors adalah semua field yang gagal validasi, sesuai urutan pengecekan.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, f := range e {
+		messages[i] = f.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Localize mengembalikan salinan e dengan pesan dalam bahasa lang.
+func (e Errors) Localize(lang string) Errors {
+	localized := make(Errors, len(e))
+	for i, f := range e {
+		localized[i] = f.Localize(lang)
+	}
+	return localized
+}
+
+// Validator mengumpulkan semua field yang gagal, bukan berhenti pada
+// kegagalan pertama. Hanya kegagalan pertama per field yang dicatat agar
+// pesan tidak saling menumpuk.
+type Validator struct {
+	errs Errors
+}
+
+// Add mencatat field yang gagal validasi. params mengisi placeholder pesan
+// di katalog.
+func (v *Validator) Add(field, code string, params i18n.Params) {
+	if v.Failed(field) {
+		return
+	}
+	v.errs = append(v.errs, NewFieldError(field, code, params))
+}
+
+// Check mencatat field bila ok bernilai false.
+func (v *Validator) Check(ok bool, field, code string, params i18n.Params) {
+	if !ok {
+		v.Add(field, code, params)
+	}
+}
+
+// Required mengecek string yang tidak boleh kosong setelah di-trim.
+func (v *Validator) Required(field, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, CodeRequired, nil)
+}
+
+// MaxLength mengecek panjang string dalam karakter, sesuai VARCHAR(n) di
+// PostgreSQL.
+func (v *Validator) MaxLength(field, value string, max int) {
+	v.Check(utf8.RuneCountInString(value) <= max, field, CodeTooLong, i18n.Params{"max": max})
+}
+
+// Failed mengecek apakah field sudah tercatat gagal.
+func (v *Validator) Failed(field string) bool {
+	for _, f := range v.errs {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err mengembalikan Errors, atau nil bila semua field valid.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
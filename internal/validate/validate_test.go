@@ -0,0 +1,52 @@
+package validate
+
+import (
+	"daya-listrik-api/internal/i18n"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator_CollectsAllFields(t *testing.T) {
+	var v Validator
+	v.Required("name", " ")
+	v.Check(false, "usage", CodeOutOfRange, i18n.Params{"max": 10000})
+	v.Check(true, "duration", CodeRequired, nil)
+
+	err := v.Err()
+	assert.Equal(t, Errors{
+		{Field: "name", Code: CodeRequired, Message: "name is required"},
+		{Field: "usage", Code: CodeOutOfRange, Message: "usage must not exceed 10000 W", Params: i18n.Params{"max": 10000}},
+	}, err)
+	assert.EqualError(t, err, "name is required; usage must not exceed 10000 W")
+}
+
+func TestValidator_FirstFailurePerField(t *testing.T) {
+	var v Validator
+	v.Required("name", "")
+	v.MaxLength("name", "", 0)
+	v.Add("name", CodeInvalid, nil)
+
+	assert.Len(t, v.Err(), 1)
+	assert.True(t, v.Failed("name"))
+	assert.False(t, v.Failed("usage"))
+}
+
+func TestValidator_MaxLengthCountsCharacters(t *testing.T) {
+	var v Validator
+	// "é" dua byte tetapi satu karakter, sama seperti VARCHAR di PostgreSQL.
+	v.MaxLength("device", strings.Repeat("é", 100), 100)
+	assert.NoError(t, v.Err())
+
+	v.MaxLength("device", strings.Repeat("a", 101), 100)
+	assert.Equal(t, CodeTooLong, v.Err().(Errors)[0].Code)
+}
+
+func TestErrors_Localize(t *testing.T) {
+	errs := Errors{NewFieldError("usage", CodeRequired, nil), NewFieldError("label", CodeTooLong, i18n.Params{"max": 100})}
+
+	assert.EqualError(t, errs, "usage is required and must be greater than 0; label must not exceed 100 characters")
+	assert.EqualError(t, errs.Localize(i18n.ID), "usage wajib diisi dan harus lebih dari 0; label tidak boleh lebih dari 100 karakter")
+	assert.Equal(t, "usage is required and must be greater than 0", errs[0].Message, "Localize must not modify the original")
+}
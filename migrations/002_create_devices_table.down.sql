@@ -0,0 +1,3 @@
+ALTER TABLE energy_records DROP COLUMN IF EXISTS device_id;
+
+DROP TABLE IF EXISTS devices;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS devices (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rated_wattage REAL NOT NULL DEFAULT 0,
+    category VARCHAR(50) NOT NULL DEFAULT '',
+    room VARCHAR(100) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- "AC" dan "ac" dianggap perangkat yang sama
+CREATE UNIQUE INDEX IF NOT EXISTS devices_name_key ON devices (LOWER(name));
+
+ALTER TABLE energy_records ADD COLUMN IF NOT EXISTS device_id INTEGER REFERENCES devices(id) ON DELETE SET NULL;
+
+-- Backfill perangkat dari string device yang sudah ada
+INSERT INTO devices (name)
+SELECT DISTINCT ON (LOWER(TRIM(device))) TRIM(device)
+FROM energy_records
+WHERE TRIM(device) <> ''
+ORDER BY LOWER(TRIM(device)), TRIM(device)
+ON CONFLICT ((LOWER(name))) DO NOTHING;
+
+UPDATE energy_records e
+SET device_id = d.id
+FROM devices d
+WHERE e.device_id IS NULL AND LOWER(d.name) = LOWER(TRIM(e.device));
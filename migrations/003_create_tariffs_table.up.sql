@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS tariffs (
+    id SERIAL PRIMARY KEY,
+    class VARCHAR(20) NOT NULL,
+    min_va INTEGER NOT NULL,
+    max_va INTEGER,
+    price_per_kwh NUMERIC(10, 2) NOT NULL,
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (class, effective_from)
+);
+
+-- Tarif rumah tangga PLN (Rp/kWh). Penyesuaian tarif berikutnya ditambahkan
+-- sebagai baris baru dengan effective_from baru, bukan mengubah baris lama.
+INSERT INTO tariffs (class, min_va, max_va, price_per_kwh, effective_from) VALUES
+    ('R-1/450VA', 450, 450, 415.00, '2024-01-01'),
+    ('R-1/900VA', 900, 900, 605.00, '2024-01-01'),
+    ('R-1/900VA-RTM', 900, 900, 1352.00, '2024-01-01'),
+    ('R-1/1300VA', 1300, 1300, 1444.70, '2024-01-01'),
+    ('R-1/2200VA', 2200, 2200, 1444.70, '2024-01-01'),
+    ('R-2', 3500, 5500, 1699.53, '2024-01-01'),
+    ('R-3', 6600, NULL, 1699.53, '2024-01-01')
+ON CONFLICT (class, effective_from) DO NOTHING;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS token_purchases (
+    id SERIAL PRIMARY KEY,
+    token_number CHAR(20) NOT NULL,
+    amount_paid NUMERIC(12, 2) NOT NULL,
+    admin_fee NUMERIC(12, 2) NOT NULL DEFAULT 0,
+    kwh_credited REAL NOT NULL,
+    purchased_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS token_purchases_purchased_at_idx ON token_purchases (purchased_at);
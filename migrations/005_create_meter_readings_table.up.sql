@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS meter_readings (
+    id SERIAL PRIMARY KEY,
+    read_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    cumulative_kwh NUMERIC(12, 2) NOT NULL,
+    photo_ref TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS meter_readings_read_at_idx ON meter_readings (read_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS energy_records_started_at_idx;
+
+ALTER TABLE energy_records DROP COLUMN IF EXISTS started_at;
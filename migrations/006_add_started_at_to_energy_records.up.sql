@@ -0,0 +1,3 @@
+ALTER TABLE energy_records ADD COLUMN IF NOT EXISTS started_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS energy_records_started_at_idx ON energy_records (started_at);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS energy_records_date_idx;
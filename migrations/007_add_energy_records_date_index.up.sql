@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS energy_records_date_idx ON energy_records (date, id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS energy_records_user_id_idx;
+
+ALTER TABLE energy_records DROP COLUMN IF EXISTS user_id;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Email tidak membedakan huruf besar/kecil
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (LOWER(email));
+
+-- Record lama tanpa pemilik diambil alih oleh user pertama yang mendaftar
+ALTER TABLE energy_records ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS energy_records_user_id_idx ON energy_records (user_id, date);
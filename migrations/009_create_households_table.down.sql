@@ -0,0 +1,18 @@
+-- Salinan perangkat per rumah tangga tidak digabung kembali. Bila ada nama
+-- perangkat yang sama di rumah tangga berbeda, index unik lama gagal dibuat
+-- dan seluruh rollback dibatalkan.
+DROP INDEX IF EXISTS devices_household_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS devices_name_key ON devices (LOWER(name));
+
+DROP INDEX IF EXISTS energy_records_household_id_idx;
+DROP INDEX IF EXISTS token_purchases_household_id_idx;
+DROP INDEX IF EXISTS meter_readings_household_id_idx;
+
+ALTER TABLE energy_records DROP COLUMN IF EXISTS household_id;
+ALTER TABLE devices DROP COLUMN IF EXISTS household_id;
+ALTER TABLE token_purchases DROP COLUMN IF EXISTS household_id;
+ALTER TABLE meter_readings DROP COLUMN IF EXISTS household_id;
+
+DROP TABLE IF EXISTS household_invitations;
+DROP TABLE IF EXISTS household_members;
+DROP TABLE IF EXISTS households;
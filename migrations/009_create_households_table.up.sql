@@ -0,0 +1,96 @@
+CREATE TABLE IF NOT EXISTS households (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    tariff_class VARCHAR(20) NOT NULL DEFAULT 'R-1/1300VA',
+    -- 0 berarti ikut daya golongan tarif
+    contracted_va INTEGER NOT NULL DEFAULT 0,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta',
+    billing_day SMALLINT NOT NULL DEFAULT 1 CHECK (billing_day BETWEEN 1 AND 28),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS household_members (
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (household_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS household_members_user_id_idx ON household_members (user_id);
+
+-- Kode undangan hanya disimpan sebagai hash SHA-256 dan hanya bisa dipakai sekali
+CREATE TABLE IF NOT EXISTS household_invitations (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL UNIQUE,
+    role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    used_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE energy_records ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+ALTER TABLE devices ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+ALTER TABLE token_purchases ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+ALTER TABLE meter_readings ADD COLUMN IF NOT EXISTS household_id INTEGER REFERENCES households(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS energy_records_household_id_idx ON energy_records (household_id, date, id);
+CREATE INDEX IF NOT EXISTS token_purchases_household_id_idx ON token_purchases (household_id, purchased_at);
+CREATE INDEX IF NOT EXISTS meter_readings_household_id_idx ON meter_readings (household_id, read_at);
+
+-- Nama perangkat kini unik per rumah tangga
+DROP INDEX IF EXISTS devices_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS devices_household_name_key ON devices (household_id, LOWER(name));
+
+-- Backfill: setiap user yang sudah punya record mendapat rumah tangga sendiri
+DO $$
+DECLARE
+    owner_id INTEGER;
+    new_household_id INTEGER;
+BEGIN
+    FOR owner_id IN
+        SELECT DISTINCT user_id FROM energy_records
+        WHERE household_id IS NULL AND user_id IS NOT NULL
+        ORDER BY user_id
+    LOOP
+        SELECT household_id INTO new_household_id FROM household_members
+        WHERE user_id = owner_id AND role = 'owner'
+        ORDER BY household_id LIMIT 1;
+
+        IF new_household_id IS NULL THEN
+            INSERT INTO households (name) VALUES ('Rumah') RETURNING id INTO new_household_id;
+            INSERT INTO household_members (household_id, user_id, role) VALUES (new_household_id, owner_id, 'owner');
+        END IF;
+
+        UPDATE energy_records SET household_id = new_household_id
+        WHERE user_id = owner_id AND household_id IS NULL;
+    END LOOP;
+END $$;
+
+-- Perangkat yang dipakai record rumah tangga lain disalin ke rumah tangga tersebut
+INSERT INTO devices (household_id, name, rated_wattage, category, room, notes)
+SELECT DISTINCT ON (e.household_id, LOWER(d.name)) e.household_id, d.name, d.rated_wattage, d.category, d.room, d.notes
+FROM energy_records e
+JOIN devices d ON d.id = e.device_id
+WHERE e.household_id IS NOT NULL AND d.household_id IS DISTINCT FROM e.household_id
+ORDER BY e.household_id, LOWER(d.name), d.id
+ON CONFLICT (household_id, (LOWER(name))) DO NOTHING;
+
+UPDATE energy_records e
+SET device_id = copy.id
+FROM devices d, devices copy
+WHERE d.id = e.device_id
+    AND d.household_id IS DISTINCT FROM e.household_id
+    AND copy.household_id = e.household_id
+    AND LOWER(copy.name) = LOWER(d.name);
+
+-- Data lain tanpa rumah tangga ikut rumah tangga pertama; bila belum ada,
+-- diambil alih saat rumah tangga pertama dibuat
+UPDATE energy_records SET household_id = (SELECT MIN(id) FROM households) WHERE household_id IS NULL;
+UPDATE devices SET household_id = (SELECT MIN(id) FROM households) WHERE household_id IS NULL;
+UPDATE token_purchases SET household_id = (SELECT MIN(id) FROM households) WHERE household_id IS NULL;
+UPDATE meter_readings SET household_id = (SELECT MIN(id) FROM households) WHERE household_id IS NULL;
@@ -0,0 +1,7 @@
+-- Admin boleh mengubah tabel tarif PLN yang dipakai semua rumah tangga
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- User pertama menjadi admin bila belum ada admin sama sekali
+UPDATE users SET is_admin = TRUE
+WHERE id = (SELECT MIN(id) FROM users)
+  AND NOT EXISTS (SELECT 1 FROM users WHERE is_admin);
@@ -0,0 +1 @@
+-- Admin yang dicabut tidak dikembalikan otomatis; gunakan grant-admin.
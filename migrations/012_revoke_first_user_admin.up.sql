@@ -0,0 +1,5 @@
+-- Migrasi 011 menjadikan user pertama admin secara otomatis. Status admin itu
+-- dicabut; operator memberikannya lagi secara eksplisit dengan grant-admin
+-- bila memang dikehendaki.
+UPDATE users SET is_admin = FALSE
+WHERE id = (SELECT MIN(id) FROM users);
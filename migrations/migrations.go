@@ -0,0 +1,28 @@
+// Package migrations menyimpan file migrasi SQL di dalam binary sehingga
+// server dan cmd/migrate tidak bergantung pada working directory.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS berisi semua file NNN_name.up.sql dan NNN_name.down.sql di folder ini.
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// SQLite berisi migrasi skema SQLite (DB_DRIVER=sqlite) dari folder sqlite,
+// dengan file di root-nya seperti FS.
+var SQLite = mustSub(sqliteFiles, "sqlite")
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS energy_records;
+DROP TABLE IF EXISTS devices;
+DROP TABLE IF EXISTS tariffs;
+DROP TABLE IF EXISTS households;
@@ -0,0 +1,50 @@
+-- Penyimpanan record energi SQLite (DB_DRIVER=sqlite). Rumah tangga,
+-- perangkat dan tarif hanya menyimpan kolom yang dibutuhkan record.
+-- Waktu disimpan sebagai TEXT UTC 'YYYY-MM-DD HH:MM:SS.ffffff' agar urutan
+-- teks sama dengan urutan waktu.
+CREATE TABLE IF NOT EXISTS households (
+    id INTEGER PRIMARY KEY,
+    tariff_class TEXT NOT NULL DEFAULT 'R-1/1300VA'
+);
+
+CREATE TABLE IF NOT EXISTS tariffs (
+    id INTEGER PRIMARY KEY,
+    class TEXT NOT NULL,
+    price_per_kwh REAL NOT NULL,
+    effective_from TEXT NOT NULL,
+    UNIQUE (class, effective_from)
+);
+
+-- Sama dengan tarif di migrasi Postgres 003
+INSERT INTO tariffs (class, price_per_kwh, effective_from) VALUES
+    ('R-1/450VA', 415.00, '2024-01-01'),
+    ('R-1/900VA', 605.00, '2024-01-01'),
+    ('R-1/900VA-RTM', 1352.00, '2024-01-01'),
+    ('R-1/1300VA', 1444.70, '2024-01-01'),
+    ('R-1/2200VA', 1444.70, '2024-01-01'),
+    ('R-2', 1699.53, '2024-01-01'),
+    ('R-3', 1699.53, '2024-01-01')
+ON CONFLICT (class, effective_from) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS devices (
+    id INTEGER PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS devices_household_name_key ON devices (household_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS energy_records (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    date TEXT NOT NULL,
+    usage REAL NOT NULL,
+    duration REAL NOT NULL DEFAULT 1,
+    device TEXT NOT NULL,
+    device_id INTEGER REFERENCES devices(id) ON DELETE SET NULL,
+    started_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS energy_records_household_id_idx ON energy_records (household_id, date, id);
+CREATE INDEX IF NOT EXISTS energy_records_started_at_idx ON energy_records (household_id, started_at);
@@ -0,0 +1,72 @@
+-- Mengembalikan skema 001. Data user, anggota, undangan, token, pembacaan
+-- meter dan API key ikut terhapus.
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS meter_readings;
+DROP TABLE IF EXISTS token_purchases;
+DROP TABLE IF EXISTS household_invitations;
+DROP TABLE IF EXISTS household_members;
+
+ALTER TABLE households RENAME TO households_002;
+
+CREATE TABLE households (
+    id INTEGER PRIMARY KEY,
+    tariff_class TEXT NOT NULL DEFAULT 'R-1/1300VA'
+);
+
+INSERT INTO households (id, tariff_class) SELECT id, tariff_class FROM households_002;
+
+ALTER TABLE tariffs RENAME TO tariffs_002;
+
+CREATE TABLE tariffs (
+    id INTEGER PRIMARY KEY,
+    class TEXT NOT NULL,
+    price_per_kwh REAL NOT NULL,
+    effective_from TEXT NOT NULL,
+    UNIQUE (class, effective_from)
+);
+
+INSERT INTO tariffs (id, class, price_per_kwh, effective_from)
+SELECT id, class, price_per_kwh, effective_from FROM tariffs_002;
+
+DROP TABLE tariffs_002;
+
+ALTER TABLE devices RENAME TO devices_002;
+DROP INDEX IF EXISTS devices_household_name_key;
+
+CREATE TABLE devices (
+    id INTEGER PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS devices_household_name_key ON devices (household_id, LOWER(name));
+
+INSERT INTO devices (id, household_id, name, category)
+SELECT id, household_id, name, category FROM devices_002;
+
+ALTER TABLE energy_records RENAME TO energy_records_002;
+DROP INDEX IF EXISTS energy_records_household_id_idx;
+DROP INDEX IF EXISTS energy_records_started_at_idx;
+
+CREATE TABLE energy_records (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    date TEXT NOT NULL,
+    usage REAL NOT NULL,
+    duration REAL NOT NULL DEFAULT 1,
+    device TEXT NOT NULL,
+    device_id INTEGER REFERENCES devices(id) ON DELETE SET NULL,
+    started_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS energy_records_household_id_idx ON energy_records (household_id, date, id);
+CREATE INDEX IF NOT EXISTS energy_records_started_at_idx ON energy_records (household_id, started_at);
+
+INSERT INTO energy_records (id, household_id, date, usage, duration, device, device_id, started_at)
+SELECT id, household_id, date, usage, duration, device, device_id, started_at FROM energy_records_002;
+
+DROP TABLE energy_records_002;
+DROP TABLE devices_002;
+DROP TABLE households_002;
+DROP TABLE IF EXISTS users;
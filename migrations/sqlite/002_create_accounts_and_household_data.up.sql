@@ -0,0 +1,185 @@
+-- Melengkapi skema 001 menjadi setara dengan migrasi Postgres 001-011 agar
+-- DB_DRIVER=sqlite tidak butuh PostgreSQL. Kolom bertipe DATETIME dan DATE
+-- dibaca driver sebagai time.Time; isinya tetap TEXT UTC
+-- 'YYYY-MM-DD HH:MM:SS.ffffff' seperti di 001.
+--
+-- Tabel dari 001 dibangun ulang karena SQLite tidak bisa mengubah tipe
+-- kolom. Tabel lama di-rename lebih dulu; REFERENCES di tabel lain ikut
+-- pindah ke nama barunya, sehingga tabel lama bisa dihapus tanpa memicu
+-- ON DELETE CASCADE ke data yang sudah disalin.
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    is_admin BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+-- Email tidak membedakan huruf besar/kecil
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (LOWER(email));
+
+ALTER TABLE households RENAME TO households_001;
+
+CREATE TABLE households (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    tariff_class TEXT NOT NULL DEFAULT 'R-1/1300VA',
+    -- 0 berarti ikut daya golongan tarif
+    contracted_va INTEGER NOT NULL DEFAULT 0,
+    timezone TEXT NOT NULL DEFAULT 'Asia/Jakarta',
+    billing_day INTEGER NOT NULL DEFAULT 1 CHECK (billing_day BETWEEN 1 AND 28),
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+-- Rumah tangga dari 001 belum punya anggota
+INSERT INTO households (id, name, tariff_class)
+SELECT id, 'Rumah', tariff_class FROM households_001;
+
+CREATE TABLE IF NOT EXISTS household_members (
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    joined_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    PRIMARY KEY (household_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS household_members_user_id_idx ON household_members (user_id);
+
+-- Kode undangan hanya disimpan sebagai hash SHA-256 dan hanya bisa dipakai sekali
+CREATE TABLE IF NOT EXISTS household_invitations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL UNIQUE,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    used_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+ALTER TABLE tariffs RENAME TO tariffs_001;
+
+CREATE TABLE tariffs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    class TEXT NOT NULL,
+    min_va INTEGER NOT NULL,
+    max_va INTEGER,
+    price_per_kwh REAL NOT NULL,
+    effective_from DATE NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000'),
+    UNIQUE (class, effective_from)
+);
+
+-- Batas daya sama dengan tarif di migrasi Postgres 003
+INSERT INTO tariffs (id, class, min_va, max_va, price_per_kwh, effective_from)
+SELECT id, class,
+    CASE class
+        WHEN 'R-1/450VA' THEN 450
+        WHEN 'R-1/900VA' THEN 900
+        WHEN 'R-1/900VA-RTM' THEN 900
+        WHEN 'R-1/1300VA' THEN 1300
+        WHEN 'R-1/2200VA' THEN 2200
+        WHEN 'R-2' THEN 3500
+        ELSE 6600
+    END,
+    CASE class
+        WHEN 'R-1/450VA' THEN 450
+        WHEN 'R-1/900VA' THEN 900
+        WHEN 'R-1/900VA-RTM' THEN 900
+        WHEN 'R-1/1300VA' THEN 1300
+        WHEN 'R-1/2200VA' THEN 2200
+        WHEN 'R-2' THEN 5500
+    END,
+    price_per_kwh, effective_from
+FROM tariffs_001;
+
+DROP TABLE tariffs_001;
+
+ALTER TABLE devices RENAME TO devices_001;
+DROP INDEX IF EXISTS devices_household_name_key;
+
+CREATE TABLE devices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    rated_wattage REAL NOT NULL DEFAULT 0,
+    category TEXT NOT NULL DEFAULT '',
+    room TEXT NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+-- "AC" dan "ac" dianggap perangkat yang sama
+CREATE UNIQUE INDEX IF NOT EXISTS devices_household_name_key ON devices (household_id, LOWER(name));
+
+INSERT INTO devices (id, household_id, name, category)
+SELECT id, household_id, name, category FROM devices_001;
+
+ALTER TABLE energy_records RENAME TO energy_records_001;
+DROP INDEX IF EXISTS energy_records_household_id_idx;
+DROP INDEX IF EXISTS energy_records_started_at_idx;
+
+CREATE TABLE energy_records (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    date DATETIME NOT NULL,
+    usage REAL NOT NULL,
+    duration REAL NOT NULL DEFAULT 1,
+    device TEXT NOT NULL,
+    device_id INTEGER REFERENCES devices(id) ON DELETE SET NULL,
+    started_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS energy_records_household_id_idx ON energy_records (household_id, date, id);
+CREATE INDEX IF NOT EXISTS energy_records_started_at_idx ON energy_records (household_id, started_at);
+
+INSERT INTO energy_records (id, household_id, date, usage, duration, device, device_id, started_at)
+SELECT id, household_id, date, usage, duration, device, device_id, started_at FROM energy_records_001;
+
+-- Anak dihapus lebih dulu agar tidak ada ON DELETE yang berjalan
+DROP TABLE energy_records_001;
+DROP TABLE devices_001;
+DROP TABLE households_001;
+
+CREATE TABLE IF NOT EXISTS token_purchases (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    token_number TEXT NOT NULL,
+    amount_paid REAL NOT NULL,
+    admin_fee REAL NOT NULL DEFAULT 0,
+    kwh_credited REAL NOT NULL,
+    purchased_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX IF NOT EXISTS token_purchases_household_id_idx ON token_purchases (household_id, purchased_at);
+
+CREATE TABLE IF NOT EXISTS meter_readings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    read_at DATETIME NOT NULL,
+    cumulative_kwh REAL NOT NULL,
+    photo_ref TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX IF NOT EXISTS meter_readings_household_id_idx ON meter_readings (household_id, read_at);
+
+-- API key hanya disimpan sebagai hash SHA-256; scopes adalah array JSON
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    household_id INTEGER NOT NULL REFERENCES households(id) ON DELETE CASCADE,
+    label TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT NOT NULL DEFAULT '[]',
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at DATETIME,
+    last_used_at DATETIME,
+    revoked_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now') || '000')
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_household_id_idx ON api_keys (household_id);
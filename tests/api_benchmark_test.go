@@ -1,8 +1,10 @@
 package test
 
 import (
+	"daya-listrik-api/internal/capacity"
 	"daya-listrik-api/internal/handlers"
 	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository"
 	"encoding/json"
 
 	"net/http"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func BenchmarkGetRecords(b *testing.B) {
@@ -27,13 +30,15 @@ func BenchmarkGetRecords(b *testing.B) {
 		{ID: 2, Usage: 200, Device: "Refrigerator", Date: date2},
 	}
 
-	mockRepo.On("GetRecords").Return(expectedRecords, nil)
+	mockRepo.On("GetRecords", mock.Anything, TestHouseholdID, repository.RecordQuery{Limit: repository.DefaultRecordLimit}).
+		Return(&repository.RecordPage{Records: expectedRecords, Total: len(expectedRecords)}, nil)
 
 	handler := handlers.GetRecords(mockRepo)
 
 	b.ResetTimer() // Mereset timer untuk memastikan hanya bagian pengujian yang dihitung
 	for i := 0; i < b.N; i++ {
 		req, _ := http.NewRequest("GET", routeApi, nil)
+		req = WithUser(req)
 		rr := httptest.NewRecorder()
 
 		handler.ServeHTTP(rr, req)
@@ -42,13 +47,16 @@ func BenchmarkGetRecords(b *testing.B) {
 			b.Errorf("Expected status 200, got %d", rr.Code)
 		}
 
-		var actualRecords []models.EnergyRecord
-		err := json.NewDecoder(rr.Body).Decode(&actualRecords)
+		var actualPage struct {
+			Data  []models.EnergyRecord `json:"data"`
+			Total int                   `json:"total"`
+		}
+		err := json.NewDecoder(rr.Body).Decode(&actualPage)
 		if err != nil {
 			b.Errorf("Error decoding response body: %v", err)
 		}
-		if len(actualRecords) != len(expectedRecords) {
-			b.Errorf("Expected %d records, got %d", len(expectedRecords), len(actualRecords))
+		if len(actualPage.Data) != len(expectedRecords) {
+			b.Errorf("Expected %d records, got %d", len(expectedRecords), len(actualPage.Data))
 		}
 	}
 
@@ -59,18 +67,19 @@ func BenchmarkAddRecord(b *testing.B) {
 	const routeApi = "/api/records/add"
 	mockRepo := new(MockRepository)
 	mockRecord := &models.EnergyRecord{
-		Usage:  100,
-		Device: "Laptop",
+		Usage:    100,
+		Device:   "Laptop",
+		Duration: 1,
 	}
 
-	mockRepo.On("AddRecord", mockRecord).Return(nil)
+	mockRepo.On("AddRecord", mock.Anything, TestHouseholdID, mockRecord).Return(nil)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		body, _ := json.Marshal(mockRecord)
 		req, rr := MakeRequest("POST", routeApi, body)
 
-		handler := handlers.AddRecord(mockRepo)
+		handler := handlers.AddRecord(mockRepo, capacity.Config{}, handlers.DefaultRecordRules())
 		handler.ServeHTTP(rr, req)
 
 		if rr.Code != http.StatusCreated {
@@ -98,7 +107,7 @@ func BenchmarkGetByIdRecord(b *testing.B) {
 	date1, _ := time.Parse(datePattern, "2023-12-31")
 	expectedRecords := &models.EnergyRecord{ID: 1, Usage: 100, Device: "Air Conditioner", Date: date1}
 
-	mockRepo.On("GetByIdRecord", "1").Return(expectedRecords, nil)
+	mockRepo.On("GetByIdRecord", mock.Anything, TestHouseholdID, "1").Return(expectedRecords, nil)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -107,7 +116,7 @@ func BenchmarkGetByIdRecord(b *testing.B) {
 		req, _ := http.NewRequest("GET", "/api/records/1", nil)
 		rr := httptest.NewRecorder()
 
-		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		req = mux.SetURLVars(WithUser(req), map[string]string{"id": "1"})
 		b.StartTimer()
 
 		handler.ServeHTTP(rr, req)
@@ -131,7 +140,7 @@ func BenchmarkGetByIdRecord(b *testing.B) {
 func BenchmarkDeleteRecord(b *testing.B) {
 	mockRepo := new(MockRepository)
 
-	mockRepo.On("DeleteRecord", "1").Return(nil)
+	mockRepo.On("DeleteRecord", mock.Anything, TestHouseholdID, "1").Return(nil)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -140,7 +149,7 @@ func BenchmarkDeleteRecord(b *testing.B) {
 		req, _ := http.NewRequest("DELETE", "/api/records/1", nil)
 		rr := httptest.NewRecorder()
 
-		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		req = mux.SetURLVars(WithUser(req), map[string]string{"id": "1"})
 		b.StartTimer()
 
 		handler.ServeHTTP(rr, req)
@@ -158,15 +167,16 @@ func BenchmarkDeleteRecord(b *testing.B) {
 func BenchmarkUpdateRecord(b *testing.B) {
 	mockRepo := new(MockRepository)
 	mockRecord := &models.EnergyRecord{
-		Usage:  100,
-		Device: "Laptop",
+		Usage:    100,
+		Device:   "Laptop",
+		Duration: 1,
 	}
-	mockRepo.On("UpdateRecord", mockRecord).Return(nil)
+	mockRepo.On("UpdateRecord", mock.Anything, TestHouseholdID, mockRecord).Return(nil)
 
 	// Start benchmark
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		handler := handlers.UpdateRecords(mockRepo)
+		handler := handlers.UpdateRecords(mockRepo, handlers.DefaultRecordRules())
 
 		body, err := json.Marshal(mockRecord)
 		if err != nil {
@@ -175,7 +185,7 @@ func BenchmarkUpdateRecord(b *testing.B) {
 
 		req, rr := MakeRequest("PUT", "/api/records/1", body)
 
-		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		req = mux.SetURLVars(WithUser(req), map[string]string{"id": "1"})
 		b.StartTimer()
 
 		handler.ServeHTTP(rr, req)
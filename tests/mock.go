@@ -2,10 +2,15 @@ package test
 
 import (
 	"bytes"
+	"context"
+	"daya-listrik-api/internal/auth"
 	"daya-listrik-api/internal/models"
+	"daya-listrik-api/internal/repository"
+	"daya-listrik-api/internal/tenant"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -14,40 +19,73 @@ type MockRepository struct {
 	mock.Mock
 }
 
-func (m *MockRepository) AddRecord(record *models.EnergyRecord) error {
-	args := m.Called(record)
+func (m *MockRepository) AddRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	args := m.Called(ctx, householdID, record)
 	return args.Error(0)
 }
 
-func (m *MockRepository) GetRecords() ([]models.EnergyRecord, error) {
-	args := m.Called()
-	return args.Get(0).([]models.EnergyRecord), args.Error(1)
+func (m *MockRepository) GetRecords(ctx context.Context, householdID int, query repository.RecordQuery) (*repository.RecordPage, error) {
+	args := m.Called(ctx, householdID, query)
+	page, _ := args.Get(0).(*repository.RecordPage)
+	return page, args.Error(1)
 }
 
-func (m *MockRepository) DeleteRecord(id string) error {
-	args := m.Called(id)
+func (m *MockRepository) DeleteRecord(ctx context.Context, householdID int, id string) error {
+	args := m.Called(ctx, householdID, id)
 	return args.Error(0)
 }
 
-func (m *MockRepository) UpdateRecord(record *models.EnergyRecord) error {
-	args := m.Called(record)
+func (m *MockRepository) UpdateRecord(ctx context.Context, householdID int, record *models.EnergyRecord) error {
+	args := m.Called(ctx, householdID, record)
 	return args.Error(0)
 }
 
-func (m *MockRepository) GetByIdRecord(id string) (*models.EnergyRecord, error) {
-	args := m.Called(id)
+func (m *MockRepository) GetByIdRecord(ctx context.Context, householdID int, id string) (*models.EnergyRecord, error) {
+	args := m.Called(ctx, householdID, id)
 	return args.Get(0).(*models.EnergyRecord), args.Error(1)
 }
 
+func (m *MockRepository) SummarizeRecords(ctx context.Context, householdID int, from, to time.Time) (*models.UsageSummary, error) {
+	args := m.Called(ctx, householdID, from, to)
+	summary, _ := args.Get(0).(*models.UsageSummary)
+	return summary, args.Error(1)
+}
+
+func (m *MockRepository) GetUsageBuckets(ctx context.Context, householdID int, query repository.UsageBucketQuery) ([]models.UsageBucket, error) {
+	args := m.Called(ctx, householdID, query)
+	buckets, _ := args.Get(0).([]models.UsageBucket)
+	return buckets, args.Error(1)
+}
+
+func (m *MockRepository) GetActiveRecords(ctx context.Context, householdID int, from, to time.Time) ([]models.EnergyRecord, error) {
+	args := m.Called(ctx, householdID, from, to)
+	return args.Get(0).([]models.EnergyRecord), args.Error(1)
+}
+
+// TestUserID dan TestHouseholdID adalah user dan rumah tangga yang dipakai
+// semua request benchmark.
+const (
+	TestUserID      = 1
+	TestHouseholdID = 10
+)
+
+// WithUser menandai request seolah sudah melewati auth.Middleware dan
+// tenant.Middleware.
+func WithUser(req *http.Request) *http.Request {
+	ctx := auth.WithUserID(req.Context(), TestUserID)
+	household := models.Household{ID: TestHouseholdID, Name: models.DefaultHouseholdName, TariffClass: models.DefaultTariffClass, Timezone: models.DefaultTimezone, BillingDay: 1}
+	return req.WithContext(tenant.WithMembership(ctx, models.Membership{Household: household, Role: models.RoleOwner}))
+}
+
 func MakeRequest(method, url string, body []byte) (*http.Request, *httptest.ResponseRecorder) {
 	req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
-	return req, rr
+	return WithUser(req), rr
 }
 
 func AssertStatusCode(t *testing.T, rr *httptest.ResponseRecorder, expected int) {
 	if status := rr.Code; status != expected {
 		t.Errorf("Expected status code %v, got %v", expected, status)
 	}
-}
\ No newline at end of file
+}